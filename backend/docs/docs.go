@@ -0,0 +1,4051 @@
+// Package docs Code generated by swaggo/swag. DO NOT EDIT
+package docs
+
+import "github.com/swaggo/swag"
+
+const docTemplate = `{
+    "schemes": {{ marshal .Schemes }},
+    "swagger": "2.0",
+    "info": {
+        "description": "{{escape .Description}}",
+        "title": "{{.Title}}",
+        "contact": {},
+        "version": "{{.Version}}"
+    },
+    "host": "{{.Host}}",
+    "basePath": "{{.BasePath}}",
+    "paths": {
+        "/deploy-check": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "deploys"
+                ],
+                "summary": "Check whether a deploy is safe",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Service name",
+                        "name": "service",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment",
+                        "name": "env",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.DeployCheck"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/environments": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "environments"
+                ],
+                "summary": "List environments",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Environment"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "environments"
+                ],
+                "summary": "Create an environment",
+                "parameters": [
+                    {
+                        "description": "Environment",
+                        "name": "environment",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.environmentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Environment"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/health": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Health check",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.healthResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "List incidents",
+                "parameters": [
+                    {
+                        "enum": [
+                            "open",
+                            "mitigated",
+                            "resolved"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "service_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Incident"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Open an incident",
+                "parameters": [
+                    {
+                        "description": "Incident",
+                        "name": "incident",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.incidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Get an incident with its budget impact",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Update an incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Incident",
+                        "name": "incident",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.incidentRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Delete an incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/incidents/{id}/resolve": {
+            "post": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "Resolve an incident",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Incident ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Incident"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/integrations/alertmanager": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "integrations"
+                ],
+                "summary": "Receive Alertmanager notifications",
+                "parameters": [
+                    {
+                        "description": "Alertmanager webhook payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.AlertmanagerWebhook"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.AlertmanagerResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/ingest": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Ingest a metric sample",
+                "parameters": [
+                    {
+                        "description": "Sample",
+                        "name": "sample",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/api.metricSample"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Duplicate sample, or late for a minute already rolled up",
+                        "schema": {
+                            "$ref": "#/definitions/api.ingestStatus"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/api.ingestStatus"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/ingest/batch": {
+            "post": {
+                "consumes": [
+                    "application/json",
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Ingest a batch of metric samples",
+                "parameters": [
+                    {
+                        "description": "Samples, as a JSON array or NDJSON",
+                        "name": "samples",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/api.metricSample"
+                            }
+                        }
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the original response for a repeated key",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/services.IngestBatchResult"
+                        },
+                        "headers": {
+                            "Idempotent-Replayed": {
+                                "type": "string",
+                                "description": "true when the response is a replay"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Every sample was rejected",
+                        "schema": {
+                            "$ref": "#/definitions/services.IngestBatchResult"
+                        }
+                    },
+                    "422": {
+                        "description": "Idempotency-Key was used for a different batch",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/metrics/remote-write": {
+            "post": {
+                "description": "A snappy-compressed protobuf WriteRequest.",
+                "consumes": [
+                    "application/x-protobuf"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Receive a Prometheus remote write request",
+                "parameters": [
+                    {
+                        "description": "Snappy-compressed WriteRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/services.RemoteWriteResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/otlp/v1/metrics": {
+            "post": {
+                "description": "An OTLP/HTTP ExportMetricsServiceRequest, optionally gzip-encoded. The response is an empty ExportMetricsServiceResponse in the request's encoding.",
+                "consumes": [
+                    "application/x-protobuf",
+                    "application/json"
+                ],
+                "produces": [
+                    "application/x-protobuf",
+                    "application/json"
+                ],
+                "tags": [
+                    "metrics"
+                ],
+                "summary": "Receive OTLP metrics",
+                "parameters": [
+                    {
+                        "description": "ExportMetricsServiceRequest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "413": {
+                        "description": "Request Entity Too Large",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Unsupported Media Type",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/overview": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Get the fleet overview",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Owner team",
+                        "name": "owner_team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 100,
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Size of the worst offender lists",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Overview"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "List services",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Owner team",
+                        "name": "owner_team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name prefix",
+                        "name": "name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "healthy",
+                            "degraded",
+                            "breached",
+                            "no_data"
+                        ],
+                        "type": "string",
+                        "description": "Only services with an SLO in this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 200,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort field, prefixed with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.listResponse-models_Service"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "next and first page links"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Register a service",
+                "parameters": [
+                    {
+                        "description": "Service",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Service"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "409": {
+                        "description": "A service with this name exists in the environment",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Get a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Replace a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read; the update fails if the service changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Service",
+                        "name": "service",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.Service"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Service"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the updated service"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A service with this name exists in the environment",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "412": {
+                        "description": "The service changed since the If-Match revision",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "services"
+                ],
+                "summary": "Delete a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Update some fields of a service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read; the update fails if the service changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Service"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the updated service"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A service with this name exists in the environment",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "412": {
+                        "description": "The service changed since the If-Match revision",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/environments": {
+            "get": {
+                "description": "The service registered under the same name in every environment, in promotion order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "environments"
+                ],
+                "summary": "List a service's environments",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Service"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/error-budget": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Get the error budgets of a service's SLOs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.ErrorBudget"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/incidents": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "incidents"
+                ],
+                "summary": "List a service's incidents",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "enum": [
+                            "open",
+                            "mitigated",
+                            "resolved"
+                        ],
+                        "type": "string",
+                        "description": "Status",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.Incident"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/promote": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "environments"
+                ],
+                "summary": "Promote SLOs to another environment",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Source service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Promotion; defaults to all SLOs to the next environment",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/services.PromotionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.PromotionResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/restore": {
+            "post": {
+                "description": "Brings back the service with the SLOs and dependencies deleted along with it. Restoring a live service returns it unchanged.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "services"
+                ],
+                "summary": "Restore a deleted service",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.Service"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "A service with this name exists in the environment",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/slo-recommendation": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Recommend an SLO target from history",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "SLI to backtest",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.TargetRecommendationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.TargetRecommendation"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/slo-status": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Get the status of a service's SLOs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "array",
+                            "items": {
+                                "$ref": "#/definitions/models.SLOStatus"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/services/{id}/slos": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slos"
+                ],
+                "summary": "List a service's SLOs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "SLI type",
+                        "name": "sli_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name prefix",
+                        "name": "name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "healthy",
+                            "degraded",
+                            "breached",
+                            "no_data"
+                        ],
+                        "type": "string",
+                        "description": "Current status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 200,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort field, prefixed with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.listResponse-models_SLO"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "next and first page links"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/slos": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slos"
+                ],
+                "summary": "List SLOs",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner team of the service",
+                        "name": "owner_team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment of the service",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "SLI type",
+                        "name": "sli_type",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Name prefix",
+                        "name": "name_prefix",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "healthy",
+                            "degraded",
+                            "breached",
+                            "no_data"
+                        ],
+                        "type": "string",
+                        "description": "Current status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "maximum": 200,
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Page size",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "next_cursor of the previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "name",
+                        "description": "Sort field, prefixed with - for descending",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return",
+                        "name": "fields",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/api.listResponse-models_SLO"
+                        },
+                        "headers": {
+                            "Link": {
+                                "type": "string",
+                                "description": "next and first page links"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slos"
+                ],
+                "summary": "Create an SLO",
+                "parameters": [
+                    {
+                        "description": "SLO",
+                        "name": "slo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SLO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLO"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/slos/{id}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slos"
+                ],
+                "summary": "Get an SLO",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SLO ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "put": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slos"
+                ],
+                "summary": "Replace an SLO",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SLO ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read; the update fails if the SLO changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "SLO",
+                        "name": "slo",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/models.SLO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the updated SLO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The SLO changed since the If-Match revision",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "tags": [
+                    "slos"
+                ],
+                "summary": "Delete an SLO",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SLO ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "204": {
+                        "description": "No Content"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "consumes": [
+                    "application/json",
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slos"
+                ],
+                "summary": "Update some fields of an SLO",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SLO ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous read; the update fails if the SLO changed since",
+                        "name": "If-Match",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "type": "object"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLO"
+                        },
+                        "headers": {
+                            "ETag": {
+                                "type": "string",
+                                "description": "Revision of the updated SLO"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid fields",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "412": {
+                        "description": "The SLO changed since the If-Match revision",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/slos/{id}/forecast": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Forecast when an SLO's budget runs out",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SLO ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.BudgetForecast"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/slos/{id}/restore": {
+            "post": {
+                "description": "Restoring a live SLO returns it unchanged. An SLO deleted with its service is restored with the service.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "slos"
+                ],
+                "summary": "Restore a deleted SLO",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SLO ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "The SLO's service is deleted",
+                        "schema": {
+                            "$ref": "#/definitions/api.problem"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/slos/{id}/simulate": {
+            "post": {
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Simulate SLO and deploy policy changes",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SLO ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Proposed configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/services.SimulationRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SimulationResult"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/slos/{id}/sli": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "status"
+                ],
+                "summary": "Measure an SLO's current SLI",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "SLO ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.SLIMeasurement"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream": {
+            "get": {
+                "description": "Each SSE message has the event ID as id, the event type as event and a StreamEvent as data.",
+                "produces": [
+                    "text/event-stream"
+                ],
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Stream events (Server-Sent Events)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service name",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner team",
+                        "name": "owner_team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated event types: slo_status, alert, deploy_check",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this event ID",
+                        "name": "Last-Event-ID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this event ID",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/models.StreamEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/stream/ws": {
+            "get": {
+                "description": "Upgrades to a WebSocket that sends each StreamEvent as a JSON text message. Send {\"type\": \"subscribe\", \"filter\": {...}} to change the filter.",
+                "tags": [
+                    "stream"
+                ],
+                "summary": "Stream events (WebSocket)",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Service ID",
+                        "name": "service_id",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Service name",
+                        "name": "service",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Owner team",
+                        "name": "owner_team",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Environment",
+                        "name": "environment",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated event types: slo_status, alert, deploy_check",
+                        "name": "types",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Resume after this event ID",
+                        "name": "last_event_id",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching protocols",
+                        "schema": {
+                            "$ref": "#/definitions/models.StreamEvent"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/api.errorResponse"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "api.environmentRequest": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "type": "integer"
+                }
+            }
+        },
+        "api.errorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string",
+                    "example": "Service not found"
+                }
+            }
+        },
+        "api.healthResponse": {
+            "type": "object",
+            "properties": {
+                "service": {
+                    "type": "string",
+                    "example": "slo-platform"
+                },
+                "status": {
+                    "type": "string",
+                    "example": "healthy"
+                }
+            }
+        },
+        "api.incidentRequest": {
+            "type": "object",
+            "required": [
+                "severity",
+                "title"
+            ],
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "postmortem_url": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "service_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "severity": {
+                    "enum": [
+                        "critical",
+                        "major",
+                        "minor"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.IncidentSeverity"
+                        }
+                    ]
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "enum": [
+                        "open",
+                        "mitigated",
+                        "resolved"
+                    ],
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.IncidentStatus"
+                        }
+                    ]
+                },
+                "title": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.ingestStatus": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "description": "\"accepted\", \"duplicate\" or \"late\"",
+                    "type": "string",
+                    "example": "accepted"
+                }
+            }
+        },
+        "api.listResponse-models_SLO": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SLO"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/api.pageLinks"
+                },
+                "meta": {
+                    "$ref": "#/definitions/services.PageInfo"
+                }
+            }
+        },
+        "api.listResponse-models_Service": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Service"
+                    }
+                },
+                "links": {
+                    "$ref": "#/definitions/api.pageLinks"
+                },
+                "meta": {
+                    "$ref": "#/definitions/services.PageInfo"
+                }
+            }
+        },
+        "api.metricSample": {
+            "type": "object",
+            "required": [
+                "metric_type",
+                "service_id",
+                "slo_id",
+                "timestamp",
+                "value"
+            ],
+            "properties": {
+                "metric_type": {
+                    "type": "string",
+                    "enum": [
+                        "success",
+                        "total",
+                        "latency"
+                    ]
+                },
+                "service_id": {
+                    "type": "integer"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "timestamp": {
+                    "description": "RFC 3339",
+                    "type": "string",
+                    "example": "2024-01-15T10:30:00Z"
+                },
+                "value": {
+                    "description": "pointer so 0 is accepted",
+                    "type": "number"
+                }
+            }
+        },
+        "api.pageLinks": {
+            "type": "object",
+            "properties": {
+                "first": {
+                    "type": "string"
+                },
+                "next": {
+                    "type": "string"
+                },
+                "self": {
+                    "type": "string"
+                }
+            }
+        },
+        "api.problem": {
+            "type": "object",
+            "properties": {
+                "detail": {
+                    "type": "string",
+                    "example": "target: must be between 0 and 1, got 5"
+                },
+                "error": {
+                    "type": "string",
+                    "example": "target: must be between 0 and 1, got 5"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.FieldError"
+                    }
+                },
+                "status": {
+                    "type": "integer",
+                    "example": 400
+                },
+                "title": {
+                    "type": "string",
+                    "example": "Bad Request"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "about:blank"
+                }
+            }
+        },
+        "models.BudgetForecast": {
+            "type": "object",
+            "properties": {
+                "data_points": {
+                    "type": "integer"
+                },
+                "expected_exhaustion": {
+                    "description": "Exhaustion estimates from the primary (EWMA) model, widened by the\nlinear model's range. Nil means the budget lasts past WindowEnd.",
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "models": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ForecastModelResult"
+                    }
+                },
+                "optimistic_exhaustion": {
+                    "type": "string"
+                },
+                "pessimistic_exhaustion": {
+                    "type": "string"
+                },
+                "projected_traffic": {
+                    "description": "events expected until window end",
+                    "type": "number"
+                },
+                "remaining_budget": {
+                    "description": "0.87 of the window's budget left",
+                    "type": "number"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "will_not_exhaust": {
+                    "description": "true when budget survives until WindowEnd",
+                    "type": "boolean"
+                },
+                "window_end": {
+                    "description": "end of the projection, one window length ahead",
+                    "type": "string"
+                },
+                "window_start": {
+                    "description": "start of the rolling window ending now",
+                    "type": "string"
+                }
+            }
+        },
+        "models.BudgetQueryMode": {
+            "type": "string",
+            "enum": [
+                "instant",
+                "range",
+                "recording_rule"
+            ],
+            "x-enum-comments": {
+                "BudgetQueryInstant": "current 5m rate ratio (default)",
+                "BudgetQueryRange": "increase() per step over the compliance window, summed",
+                "BudgetQueryRecordingRule": "per-step counts pre-aggregated by recording rules, summed"
+            },
+            "x-enum-varnames": [
+                "BudgetQueryInstant",
+                "BudgetQueryRange",
+                "BudgetQueryRecordingRule"
+            ]
+        },
+        "models.CandidateTarget": {
+            "type": "object",
+            "properties": {
+                "compliance_rate": {
+                    "description": "95.0 (% of windows meeting the target)",
+                    "type": "number"
+                },
+                "target": {
+                    "type": "number"
+                },
+                "windows_met": {
+                    "type": "integer"
+                },
+                "worst_budget_use": {
+                    "description": "1.4 = worst window used 140% of its budget",
+                    "type": "number"
+                }
+            }
+        },
+        "models.DeployCheck": {
+            "type": "object",
+            "properties": {
+                "burn_rate": {
+                    "type": "number"
+                },
+                "checked_at": {
+                    "type": "string"
+                },
+                "decision": {
+                    "$ref": "#/definitions/models.DeployDecision"
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "last_slo_breach": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "recent_incidents": {
+                    "type": "boolean"
+                },
+                "remaining_budget": {
+                    "type": "number"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "worst_slice": {
+                    "description": "labels of the slice that decided the check",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.DeployDecision": {
+            "type": "string",
+            "enum": [
+                "SAFE",
+                "RISKY",
+                "BLOCKED"
+            ],
+            "x-enum-varnames": [
+                "DeployDecisionSafe",
+                "DeployDecisionRisky",
+                "DeployDecisionBlocked"
+            ]
+        },
+        "models.DeployPolicy": {
+            "type": "object",
+            "properties": {
+                "block_budget_below": {
+                    "description": "block when budget is below this...",
+                    "type": "number"
+                },
+                "block_burn_above": {
+                    "description": "...and burning faster than this",
+                    "type": "number"
+                },
+                "risky_budget_below": {
+                    "type": "number"
+                },
+                "risky_burn_above": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.Environment": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rank": {
+                    "description": "promotion order, lowest first: dev 10, staging 20, prod 30",
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ErrorBudget": {
+            "type": "object",
+            "properties": {
+                "consumed_budget": {
+                    "description": "0.00013",
+                    "type": "number"
+                },
+                "consumed_budget_minutes": {
+                    "type": "number"
+                },
+                "current_burn_rate": {
+                    "description": "Burn rate analysis",
+                    "type": "number"
+                },
+                "data_coverage": {
+                    "description": "% of the compliance window with data",
+                    "type": "number"
+                },
+                "five_minute_burn": {
+                    "description": "burn rate over last 5 min",
+                    "type": "number"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "no_data": {
+                    "type": "boolean"
+                },
+                "one_hour_burn": {
+                    "description": "burn rate over last 1 hour",
+                    "type": "number"
+                },
+                "remaining_budget": {
+                    "description": "0.00087",
+                    "type": "number"
+                },
+                "remaining_budget_minutes": {
+                    "type": "number"
+                },
+                "remaining_percent": {
+                    "description": "87.0",
+                    "type": "number"
+                },
+                "six_hour_burn": {
+                    "description": "burn rate over last 6 hours",
+                    "type": "number"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "total_budget": {
+                    "description": "0.001 for 99.9% target",
+                    "type": "number"
+                },
+                "total_budget_minutes": {
+                    "description": "Window-based SLOs only: budget as minutes of allowed bad time",
+                    "type": "number"
+                },
+                "twenty_four_hour_burn": {
+                    "description": "burn rate over last 24h",
+                    "type": "number"
+                },
+                "warnings": {
+                    "description": "from Prometheus",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "models.ForecastModel": {
+            "type": "string",
+            "enum": [
+                "linear",
+                "ewma"
+            ],
+            "x-enum-varnames": [
+                "ForecastModelLinear",
+                "ForecastModelEWMA"
+            ]
+        },
+        "models.ForecastModelResult": {
+            "type": "object",
+            "properties": {
+                "error_ratio": {
+                    "description": "projected bad/total ratio at the start of the horizon",
+                    "type": "number"
+                },
+                "expected_exhaustion": {
+                    "type": "string"
+                },
+                "model": {
+                    "$ref": "#/definitions/models.ForecastModel"
+                },
+                "optimistic_exhaustion": {
+                    "type": "string"
+                },
+                "pessimistic_exhaustion": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.Incident": {
+            "type": "object",
+            "properties": {
+                "budget_impact": {
+                    "description": "Runtime fields (not persisted)",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.IncidentBudgetImpact"
+                    }
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "description": {
+                    "type": "string"
+                },
+                "external_id": {
+                    "description": "e.g. Alertmanager fingerprint",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "postmortem_url": {
+                    "type": "string"
+                },
+                "resolved_at": {
+                    "type": "string"
+                },
+                "services": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.Service"
+                    }
+                },
+                "severity": {
+                    "$ref": "#/definitions/models.IncidentSeverity"
+                },
+                "slo_id": {
+                    "description": "SLO that triggered the incident, if any",
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "\"manual\", \"slo_breach\", \"alertmanager\"",
+                    "type": "string"
+                },
+                "started_at": {
+                    "type": "string"
+                },
+                "status": {
+                    "$ref": "#/definitions/models.IncidentStatus"
+                },
+                "title": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IncidentBudgetImpact": {
+            "type": "object",
+            "properties": {
+                "consumed_budget": {
+                    "description": "0.00004 of the 0.001 total budget",
+                    "type": "number"
+                },
+                "consumed_percent": {
+                    "description": "4.0 (% of the window's budget)",
+                    "type": "number"
+                },
+                "method": {
+                    "description": "\"events\" or \"duration\"",
+                    "type": "string"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "slo_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.IncidentSeverity": {
+            "type": "string",
+            "enum": [
+                "critical",
+                "major",
+                "minor"
+            ],
+            "x-enum-varnames": [
+                "IncidentSeverityCritical",
+                "IncidentSeverityMajor",
+                "IncidentSeverityMinor"
+            ]
+        },
+        "models.IncidentStatus": {
+            "type": "string",
+            "enum": [
+                "open",
+                "mitigated",
+                "resolved"
+            ],
+            "x-enum-varnames": [
+                "IncidentStatusOpen",
+                "IncidentStatusMitigated",
+                "IncidentStatusResolved"
+            ]
+        },
+        "models.LatencyMode": {
+            "type": "string",
+            "enum": [
+                "interpolate",
+                "nearest_bucket",
+                "native"
+            ],
+            "x-enum-comments": {
+                "LatencyModeInterpolate": "classic buckets, interpolated at the threshold",
+                "LatencyModeNative": "native histogram via histogram_fraction",
+                "LatencyModeNearestBucket": "classic buckets, nearest bucket boundary"
+            },
+            "x-enum-varnames": [
+                "LatencyModeInterpolate",
+                "LatencyModeNearestBucket",
+                "LatencyModeNative"
+            ]
+        },
+        "models.Overview": {
+            "type": "object",
+            "properties": {
+                "counts": {
+                    "$ref": "#/definitions/models.StatusCounts"
+                },
+                "environments": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatusRollup"
+                    }
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "oldest_evaluation": {
+                    "description": "the stalest snapshot included",
+                    "type": "string"
+                },
+                "teams": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.StatusRollup"
+                    }
+                },
+                "worst_by_budget": {
+                    "description": "lowest remaining budget first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OverviewSLO"
+                    }
+                },
+                "worst_by_burn_rate": {
+                    "description": "fastest burning first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.OverviewSLO"
+                    }
+                }
+            }
+        },
+        "models.OverviewSLO": {
+            "type": "object",
+            "properties": {
+                "current_burn_rate": {
+                    "type": "number"
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "evaluated_at": {
+                    "type": "string"
+                },
+                "owner_team": {
+                    "type": "string"
+                },
+                "remaining_budget": {
+                    "type": "number"
+                },
+                "service_id": {
+                    "type": "integer"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "slo_name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.PromotionResult": {
+            "type": "object",
+            "properties": {
+                "created": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SLO"
+                    }
+                },
+                "from_environment": {
+                    "type": "string"
+                },
+                "service": {
+                    "description": "the service in the target environment",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.Service"
+                        }
+                    ]
+                },
+                "service_created": {
+                    "description": "registered in the target environment by the promotion",
+                    "type": "boolean"
+                },
+                "skipped": {
+                    "description": "names of SLOs already defined in the target environment",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source_service_id": {
+                    "type": "integer"
+                },
+                "to_environment": {
+                    "type": "string"
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SLO"
+                    }
+                }
+            }
+        },
+        "models.SLIMeasurement": {
+            "type": "object",
+            "properties": {
+                "buckets": {
+                    "description": "Histogram bucket boundaries (le) the latency threshold was read from",
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "data_coverage": {
+                    "description": "Window SLIs only: % of the compliance window with data, and the\nevaluated range (shorter than the window when retention is)",
+                    "type": "number"
+                },
+                "good": {
+                    "description": "good events (or rate) behind Value",
+                    "type": "number"
+                },
+                "method": {
+                    "description": "latency: \"exact_bucket\", \"interpolated\", \"nearest_bucket\", \"native_histogram\", \"samples\", \"query\"",
+                    "type": "string"
+                },
+                "no_data": {
+                    "description": "the queries returned no series; Value is meaningless",
+                    "type": "boolean"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "\"prometheus\", \"ingested\", \"mock\"",
+                    "type": "string"
+                },
+                "total": {
+                    "description": "total events (or rate) behind Value",
+                    "type": "number"
+                },
+                "value": {
+                    "description": "0.9987",
+                    "type": "number"
+                },
+                "warnings": {
+                    "description": "from Prometheus",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "window_end": {
+                    "type": "string"
+                },
+                "window_start": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.SLIMode": {
+            "type": "string",
+            "enum": [
+                "request",
+                "window"
+            ],
+            "x-enum-comments": {
+                "SLIModeRequest": "good events / total events",
+                "SLIModeWindow": "good intervals / total intervals"
+            },
+            "x-enum-varnames": [
+                "SLIModeRequest",
+                "SLIModeWindow"
+            ]
+        },
+        "models.SLIType": {
+            "type": "string",
+            "enum": [
+                "availability",
+                "latency",
+                "error_rate",
+                "custom",
+                "freshness",
+                "throughput",
+                "correctness",
+                "durability"
+            ],
+            "x-enum-comments": {
+                "SLITypeCorrectness": "validated outputs / total outputs",
+                "SLITypeDurability": "intact objects / objects checked",
+                "SLITypeFreshness": "data is younger than FreshnessThresholdSeconds",
+                "SLITypeThroughput": "at least MinThroughputPerMinute items processed"
+            },
+            "x-enum-varnames": [
+                "SLITypeAvailability",
+                "SLITypeLatency",
+                "SLITypeErrorRate",
+                "SLITypeCustom",
+                "SLITypeFreshness",
+                "SLITypeThroughput",
+                "SLITypeCorrectness",
+                "SLITypeDurability"
+            ]
+        },
+        "models.SLO": {
+            "type": "object",
+            "properties": {
+                "budget_query_mode": {
+                    "description": "how the SLI is computed over the window",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.BudgetQueryMode"
+                        }
+                    ]
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current_status": {
+                    "description": "Runtime fields (not persisted)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SLOStatus"
+                        }
+                    ]
+                },
+                "description": {
+                    "type": "string"
+                },
+                "error_budget": {
+                    "$ref": "#/definitions/models.ErrorBudget"
+                },
+                "fast_burn_threshold": {
+                    "description": "Burn rate thresholds",
+                    "type": "number"
+                },
+                "freshness_metric": {
+                    "description": "Freshness and throughput SLIs are always window-based",
+                    "type": "string"
+                },
+                "freshness_threshold_seconds": {
+                    "description": "maximum acceptable data age",
+                    "type": "number"
+                },
+                "group_by": {
+                    "description": "Multi-dimensional SLOs: the SLI and budget are also computed per value\nof these labels, keeping at most MaxSlices (worst first)",
+                    "type": "string"
+                },
+                "hard_budget_policy": {
+                    "description": "Error budget policy",
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "ingest_matchers": {
+                    "description": "Pushed metrics (remote_write, OTLP) feed the SLO when their name is\nSuccessMetric or TotalMetric and their labels match this selector,\ne.g. {job=\"checkout\",env=\"prod\"}. Default: {service=\"\u003cservice name\u003e\"}",
+                    "type": "string"
+                },
+                "latency_metric": {
+                    "description": "histogram name, default http_request_duration_seconds",
+                    "type": "string"
+                },
+                "latency_mode": {
+                    "description": "how the threshold is read from the histogram",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.LatencyMode"
+                        }
+                    ]
+                },
+                "latency_threshold": {
+                    "description": "for latency SLOs, in seconds",
+                    "type": "number"
+                },
+                "max_slices": {
+                    "description": "default 50",
+                    "type": "integer"
+                },
+                "min_throughput_per_minute": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prometheus_query": {
+                    "description": "SLI configuration",
+                    "type": "string"
+                },
+                "range_step_seconds": {
+                    "description": "step for range and recording_rule modes, default 3600",
+                    "type": "integer"
+                },
+                "series_aggregation": {
+                    "description": "for prometheus_query results with several series",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SeriesAggregation"
+                        }
+                    ]
+                },
+                "service": {
+                    "$ref": "#/definitions/models.Service"
+                },
+                "service_id": {
+                    "type": "integer"
+                },
+                "sli_mode": {
+                    "description": "Window-based SLIs: each interval is good when the query result\ncompares to WindowThreshold with WindowComparison (e.g. up \u003e= 1)",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/models.SLIMode"
+                        }
+                    ]
+                },
+                "sli_type": {
+                    "$ref": "#/definitions/models.SLIType"
+                },
+                "slow_burn_threshold": {
+                    "type": "number"
+                },
+                "success_metric": {
+                    "type": "string"
+                },
+                "target": {
+                    "description": "0.999 for 99.9%",
+                    "type": "number"
+                },
+                "throughput_metric": {
+                    "description": "counter of processed items",
+                    "type": "string"
+                },
+                "time_window_days": {
+                    "type": "integer"
+                },
+                "total_metric": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "window_comparison": {
+                    "description": "\"\u003e=\" (default), \"\u003e\", \"\u003c=\", \"\u003c\"",
+                    "type": "string"
+                },
+                "window_interval_seconds": {
+                    "type": "integer"
+                },
+                "window_threshold": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SLOSlice": {
+            "type": "object",
+            "properties": {
+                "consumed_budget": {
+                    "description": "percent",
+                    "type": "number"
+                },
+                "current_sli": {
+                    "type": "number"
+                },
+                "good": {
+                    "type": "number"
+                },
+                "labels": {
+                    "description": "{\"endpoint\": \"/login\"}",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "remaining_budget": {
+                    "description": "percent",
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total": {
+                    "type": "number"
+                }
+            }
+        },
+        "models.SLOStatus": {
+            "type": "object",
+            "properties": {
+                "consumed_budget": {
+                    "description": "0.13 (13%)",
+                    "type": "number"
+                },
+                "current_burn_rate": {
+                    "description": "1.2",
+                    "type": "number"
+                },
+                "current_sli": {
+                    "description": "0.9987",
+                    "type": "number"
+                },
+                "data_coverage": {
+                    "description": "% of the compliance window with data",
+                    "type": "number"
+                },
+                "fast_burn_rate": {
+                    "description": "0.8",
+                    "type": "number"
+                },
+                "last_updated": {
+                    "type": "string"
+                },
+                "remaining_budget": {
+                    "description": "0.87 (87%)",
+                    "type": "number"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "slices": {
+                    "description": "Per-slice results for SLOs with group_by labels, worst first",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SLOSlice"
+                    }
+                },
+                "slices_truncated": {
+                    "description": "more slices than max_slices were returned",
+                    "type": "boolean"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "slo_name": {
+                    "type": "string"
+                },
+                "slow_burn_rate": {
+                    "description": "1.1",
+                    "type": "number"
+                },
+                "status": {
+                    "description": "\"healthy\", \"degraded\", \"breached\", \"no_data\"",
+                    "type": "string"
+                },
+                "target": {
+                    "description": "0.999",
+                    "type": "number"
+                },
+                "time_to_exhaustion": {
+                    "description": "hours until budget exhausted",
+                    "type": "integer"
+                },
+                "warnings": {
+                    "description": "from Prometheus",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "worst_slice": {
+                    "$ref": "#/definitions/models.SLOSlice"
+                }
+            }
+        },
+        "models.SeriesAggregation": {
+            "type": "string",
+            "enum": [
+                "min",
+                "max",
+                "avg",
+                "sum",
+                "error"
+            ],
+            "x-enum-comments": {
+                "SeriesAggregationError": "reject results with several series",
+                "SeriesAggregationMin": "worst series, the default"
+            },
+            "x-enum-varnames": [
+                "SeriesAggregationMin",
+                "SeriesAggregationMax",
+                "SeriesAggregationAvg",
+                "SeriesAggregationSum",
+                "SeriesAggregationError"
+            ]
+        },
+        "models.Service": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "dependencies": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.ServiceDependency"
+                    }
+                },
+                "description": {
+                    "type": "string"
+                },
+                "environment": {
+                    "description": "name of an Environment",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "description": "unique per environment",
+                    "type": "string"
+                },
+                "owner_team": {
+                    "type": "string"
+                },
+                "slos": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.SLO"
+                    }
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.ServiceDependency": {
+            "type": "object",
+            "properties": {
+                "critical": {
+                    "type": "boolean"
+                },
+                "depends_on_id": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "service_id": {
+                    "type": "integer"
+                },
+                "type": {
+                    "description": "\"api\", \"database\", \"queue\", etc.",
+                    "type": "string"
+                }
+            }
+        },
+        "models.SimulationOutcome": {
+            "type": "object",
+            "properties": {
+                "blocked_deploys": {
+                    "type": "integer"
+                },
+                "budget_exhausted_days": {
+                    "type": "integer"
+                },
+                "deploy_checks": {
+                    "type": "integer"
+                },
+                "fast_burn_alerts": {
+                    "type": "integer"
+                },
+                "fast_burn_threshold": {
+                    "type": "number"
+                },
+                "min_remaining_budget": {
+                    "description": "lowest remaining % seen",
+                    "type": "number"
+                },
+                "policy": {
+                    "$ref": "#/definitions/models.DeployPolicy"
+                },
+                "risky_deploys": {
+                    "type": "integer"
+                },
+                "safe_deploys": {
+                    "type": "integer"
+                },
+                "slow_burn_alerts": {
+                    "type": "integer"
+                },
+                "slow_burn_threshold": {
+                    "type": "number"
+                },
+                "target": {
+                    "type": "number"
+                },
+                "time_window_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "models.SimulationResult": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "$ref": "#/definitions/models.SimulationOutcome"
+                },
+                "data_points": {
+                    "type": "integer"
+                },
+                "end": {
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "proposed": {
+                    "$ref": "#/definitions/models.SimulationOutcome"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.StatusCounts": {
+            "type": "object",
+            "properties": {
+                "breached": {
+                    "type": "integer"
+                },
+                "degraded": {
+                    "type": "integer"
+                },
+                "healthy": {
+                    "type": "integer"
+                },
+                "no_data": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unevaluated": {
+                    "description": "no snapshot yet",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StatusRollup": {
+            "type": "object",
+            "properties": {
+                "breached": {
+                    "type": "integer"
+                },
+                "degraded": {
+                    "type": "integer"
+                },
+                "healthy": {
+                    "type": "integer"
+                },
+                "max_burn_rate": {
+                    "type": "number"
+                },
+                "min_remaining_budget": {
+                    "description": "percent, over SLOs with data",
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "no_data": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                },
+                "unevaluated": {
+                    "description": "no snapshot yet",
+                    "type": "integer"
+                }
+            }
+        },
+        "models.StreamEvent": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "string"
+                },
+                "data": {
+                    "description": "SLOStatus, AlertEvent or DeployCheck",
+                    "type": "object"
+                },
+                "environment": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "owner_team": {
+                    "type": "string"
+                },
+                "service_id": {
+                    "type": "integer"
+                },
+                "service_name": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "models.TargetRecommendation": {
+            "type": "object",
+            "properties": {
+                "candidates": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.CandidateTarget"
+                    }
+                },
+                "days": {
+                    "description": "backtest period",
+                    "type": "integer"
+                },
+                "generated_at": {
+                    "type": "string"
+                },
+                "median_sli": {
+                    "type": "number"
+                },
+                "min_sli": {
+                    "type": "number"
+                },
+                "reasoning": {
+                    "type": "string"
+                },
+                "recommended_target": {
+                    "description": "0 when no candidate qualifies",
+                    "type": "number"
+                },
+                "service_id": {
+                    "type": "integer"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "window_days": {
+                    "description": "compliance window length",
+                    "type": "integer"
+                },
+                "windows": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/models.WindowSLI"
+                    }
+                }
+            }
+        },
+        "models.WindowSLI": {
+            "type": "object",
+            "properties": {
+                "end": {
+                    "type": "string"
+                },
+                "events": {
+                    "type": "number"
+                },
+                "sli": {
+                    "type": "number"
+                },
+                "start": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.AlertmanagerAlert": {
+            "type": "object",
+            "properties": {
+                "annotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "endsAt": {
+                    "type": "string"
+                },
+                "fingerprint": {
+                    "type": "string"
+                },
+                "generatorURL": {
+                    "type": "string"
+                },
+                "labels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "startsAt": {
+                    "type": "string"
+                },
+                "status": {
+                    "description": "\"firing\" or \"resolved\"",
+                    "type": "string"
+                }
+            }
+        },
+        "services.AlertmanagerResult": {
+            "type": "object",
+            "properties": {
+                "opened": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "resolved": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.SkippedAlert"
+                    }
+                },
+                "updated": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "services.AlertmanagerWebhook": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.AlertmanagerAlert"
+                    }
+                },
+                "commonAnnotations": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "commonLabels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "externalURL": {
+                    "type": "string"
+                },
+                "groupKey": {
+                    "type": "string"
+                },
+                "groupLabels": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "receiver": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "truncatedAlerts": {
+                    "type": "integer"
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.FieldError": {
+            "type": "object",
+            "properties": {
+                "field": {
+                    "type": "string",
+                    "example": "target"
+                },
+                "message": {
+                    "type": "string",
+                    "example": "must be between 0 and 1, got 5"
+                }
+            }
+        },
+        "services.IngestBatchResult": {
+            "type": "object",
+            "properties": {
+                "accepted": {
+                    "type": "integer"
+                },
+                "duplicates": {
+                    "type": "integer"
+                },
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/services.IngestSampleError"
+                    }
+                },
+                "late": {
+                    "description": "in or before a rolled-up minute, not stored",
+                    "type": "integer"
+                },
+                "received": {
+                    "type": "integer"
+                },
+                "rejected": {
+                    "type": "integer"
+                }
+            }
+        },
+        "services.IngestSampleError": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                }
+            }
+        },
+        "services.PageInfo": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "has_more": {
+                    "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "next_cursor": {
+                    "type": "string"
+                },
+                "sort": {
+                    "type": "string"
+                },
+                "total": {
+                    "description": "omitted when a status filter applies",
+                    "type": "integer"
+                }
+            }
+        },
+        "services.PromotionRequest": {
+            "type": "object",
+            "properties": {
+                "overwrite": {
+                    "description": "replace SLOs with the same name in the target",
+                    "type": "boolean"
+                },
+                "slo_ids": {
+                    "description": "default: all of the service's SLOs",
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                },
+                "to_environment": {
+                    "description": "default: the next environment by rank",
+                    "type": "string"
+                }
+            }
+        },
+        "services.RemoteWriteResult": {
+            "type": "object",
+            "properties": {
+                "late": {
+                    "description": "in or before a rolled-up minute, not stored",
+                    "type": "integer"
+                },
+                "matched_series": {
+                    "type": "integer"
+                },
+                "samples": {
+                    "type": "integer"
+                },
+                "series": {
+                    "type": "integer"
+                },
+                "stored": {
+                    "type": "integer"
+                },
+                "unmatched_series": {
+                    "type": "integer"
+                }
+            }
+        },
+        "services.SimulationRequest": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "history to replay, default 30; with the longest window at most 450",
+                    "type": "integer"
+                },
+                "deploy_interval_minutes": {
+                    "description": "deploy check cadence, default 60",
+                    "type": "integer"
+                },
+                "deploy_times": {
+                    "description": "overrides the cadence with real deploys",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "fast_burn_threshold": {
+                    "type": "number"
+                },
+                "policy": {
+                    "$ref": "#/definitions/models.DeployPolicy"
+                },
+                "slow_burn_threshold": {
+                    "type": "number"
+                },
+                "target": {
+                    "type": "number"
+                },
+                "time_window_days": {
+                    "type": "integer"
+                }
+            }
+        },
+        "services.SkippedAlert": {
+            "type": "object",
+            "properties": {
+                "alertname": {
+                    "type": "string"
+                },
+                "fingerprint": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                }
+            }
+        },
+        "services.TargetRecommendationRequest": {
+            "type": "object",
+            "properties": {
+                "days": {
+                    "description": "default 90, at most 450",
+                    "type": "integer"
+                },
+                "min_compliance": {
+                    "description": "default 0.9 of windows must meet the target",
+                    "type": "number"
+                },
+                "prometheus_query": {
+                    "type": "string"
+                },
+                "sli_type": {
+                    "$ref": "#/definitions/models.SLIType"
+                },
+                "slo_id": {
+                    "type": "integer"
+                },
+                "success_metric": {
+                    "type": "string"
+                },
+                "total_metric": {
+                    "type": "string"
+                },
+                "window_days": {
+                    "description": "default 30, or the SLO's window",
+                    "type": "integer"
+                }
+            }
+        }
+    }
+}`
+
+// SwaggerInfo holds exported Swagger Info so clients can modify it
+var SwaggerInfo = &swag.Spec{
+	Version:          "1.0",
+	Host:             "",
+	BasePath:         "/api/v1",
+	Schemes:          []string{},
+	Title:            "SLO Platform API",
+	Description:      "Services, SLOs, error budgets, incidents and deploy checks.\nThe API does not authenticate requests; deploy it behind a gateway that does.\nErrors are returned as {\"error\": \"message\"}, except that service and SLO writes answer\ninvalid fields (400), conflicts (409) and failed If-Match preconditions (412) with\napplication/problem+json details, which repeat the message in \"error\".",
+	InfoInstanceName: "swagger",
+	SwaggerTemplate:  docTemplate,
+	LeftDelim:        "{{",
+	RightDelim:       "}}",
+}
+
+func init() {
+	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
+}
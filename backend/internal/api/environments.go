@@ -0,0 +1,152 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"slo-platform/internal/models"
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type environmentRequest struct {
+	Name        string `json:"name" binding:"required"`
+	Description string `json:"description"`
+	Rank        int    `json:"rank"`
+}
+
+// @Summary List environments
+// @Tags environments
+// @Produce json
+// @Success 200 {array} models.Environment
+// @Failure 500 {object} errorResponse
+// @Router /environments [get]
+func listEnvironments(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		environments, err := serviceRegistry.ListEnvironments()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, environments)
+	}
+}
+
+// @Summary Create an environment
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Param environment body environmentRequest true "Environment"
+// @Success 201 {object} models.Environment
+// @Failure 400 {object} errorResponse
+// @Failure 409 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /environments [post]
+func createEnvironment(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req environmentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		environment := models.Environment{Name: req.Name, Description: req.Description, Rank: req.Rank}
+		err := serviceRegistry.CreateEnvironment(&environment)
+		if errors.Is(err, services.ErrEnvironmentExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, environment)
+	}
+}
+
+// listServiceEnvironments returns a service's registrations in every
+// environment, in promotion order.
+//
+// @Summary List a service's environments
+// @Description The service registered under the same name in every environment, in promotion order.
+// @Tags environments
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.Service
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /services/{id}/environments [get]
+func listServiceEnvironments(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+			return
+		}
+
+		services, err := serviceRegistry.ListServiceEnvironments(uint(id))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, services)
+	}
+}
+
+// promoteSLOs copies a service's SLO definitions to the same service in
+// another environment, by default the next one (e.g. staging to prod).
+//
+// @Summary Promote SLOs to another environment
+// @Tags environments
+// @Accept json
+// @Produce json
+// @Param id path int true "Source service ID"
+// @Param request body services.PromotionRequest false "Promotion; defaults to all SLOs to the next environment"
+// @Success 200 {object} models.PromotionResult
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /services/{id}/promote [post]
+func promoteSLOs(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+			return
+		}
+
+		var req services.PromotionRequest
+		if c.Request.ContentLength != 0 {
+			if err := c.ShouldBindJSON(&req); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+		}
+
+		result, err := sloService.PromoteSLOs(uint(id), req)
+		switch {
+		case errors.Is(err, gorm.ErrRecordNotFound):
+			c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+			return
+		case errors.Is(err, services.ErrUnknownEnvironment), errors.Is(err, services.ErrInvalidPromotion):
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		case err != nil:
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
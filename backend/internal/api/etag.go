@@ -0,0 +1,33 @@
+package api
+
+import (
+	"strings"
+	"time"
+
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Services and SLOs carry an ETag derived from their update time. Sending it
+// back in If-Match makes PUT and PATCH fail with 412 when the record changed
+// in the meantime; without If-Match the last write wins.
+
+func setETag(c *gin.Context, updatedAt time.Time) {
+	c.Header("ETag", `"`+services.Revision(updatedAt)+`"`)
+}
+
+// ifMatch returns the revisions listed in the If-Match header, or none when
+// the header is missing or "*".
+func ifMatch(c *gin.Context) []string {
+	var revisions []string
+	for _, tag := range strings.Split(c.GetHeader("If-Match"), ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+		tag = strings.TrimPrefix(tag, "W/")
+		revisions = append(revisions, strings.Trim(tag, `"`))
+	}
+	return revisions
+}
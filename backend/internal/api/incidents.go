@@ -0,0 +1,270 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"slo-platform/internal/models"
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+type incidentRequest struct {
+	Title         string                  `json:"title" binding:"required"`
+	Description   string                  `json:"description"`
+	Severity      models.IncidentSeverity `json:"severity" binding:"required,oneof=critical major minor"`
+	Status        models.IncidentStatus   `json:"status" binding:"omitempty,oneof=open mitigated resolved"`
+	StartedAt     *time.Time              `json:"started_at"`
+	ResolvedAt    *time.Time              `json:"resolved_at"`
+	PostmortemURL string                  `json:"postmortem_url"`
+	ServiceIDs    []uint                  `json:"service_ids"`
+}
+
+func (r *incidentRequest) apply(incident *models.Incident) {
+	incident.Title = r.Title
+	incident.Description = r.Description
+	incident.Severity = r.Severity
+	incident.PostmortemURL = r.PostmortemURL
+	if r.Status != "" {
+		incident.Status = r.Status
+	}
+	if r.StartedAt != nil {
+		incident.StartedAt = *r.StartedAt
+	}
+	if r.ResolvedAt != nil {
+		incident.ResolvedAt = r.ResolvedAt
+	}
+}
+
+// @Summary Open an incident
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param incident body incidentRequest true "Incident"
+// @Success 201 {object} models.Incident
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /incidents [post]
+func createIncident(incidentService *services.IncidentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var req incidentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if len(req.ServiceIDs) == 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "service_ids must reference at least one service"})
+			return
+		}
+
+		var incident models.Incident
+		req.apply(&incident)
+		err := incidentService.CreateIncident(&incident, req.ServiceIDs)
+		if errors.Is(err, services.ErrUnknownService) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusCreated, incident)
+	}
+}
+
+// @Summary List incidents
+// @Tags incidents
+// @Produce json
+// @Param status query string false "Status" Enums(open, mitigated, resolved)
+// @Param service_id query int false "Service ID"
+// @Success 200 {array} models.Incident
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /incidents [get]
+func listIncidents(incidentService *services.IncidentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := services.IncidentFilter{
+			Status: models.IncidentStatus(c.Query("status")),
+		}
+		if serviceID := c.Query("service_id"); serviceID != "" {
+			id, err := strconv.ParseUint(serviceID, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+				return
+			}
+			filter.ServiceID = uint(id)
+		}
+
+		incidents, err := incidentService.ListIncidents(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, incidents)
+	}
+}
+
+// @Summary List a service's incidents
+// @Tags incidents
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param status query string false "Status" Enums(open, mitigated, resolved)
+// @Success 200 {array} models.Incident
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /services/{id}/incidents [get]
+func listServiceIncidents(incidentService *services.IncidentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+			return
+		}
+
+		incidents, err := incidentService.ListIncidents(services.IncidentFilter{
+			Status:    models.IncidentStatus(c.Query("status")),
+			ServiceID: uint(serviceID),
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, incidents)
+	}
+}
+
+// @Summary Get an incident with its budget impact
+// @Tags incidents
+// @Produce json
+// @Param id path int true "Incident ID"
+// @Success 200 {object} models.Incident
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /incidents/{id} [get]
+func getIncident(incidentService *services.IncidentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident ID"})
+			return
+		}
+
+		incident, err := incidentService.GetIncident(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+			return
+		}
+
+		impact, err := incidentService.CalculateBudgetImpact(incident)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		incident.BudgetImpact = impact
+
+		c.JSON(http.StatusOK, incident)
+	}
+}
+
+// @Summary Update an incident
+// @Tags incidents
+// @Accept json
+// @Produce json
+// @Param id path int true "Incident ID"
+// @Param incident body incidentRequest true "Incident"
+// @Success 200 {object} models.Incident
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /incidents/{id} [put]
+func updateIncident(incidentService *services.IncidentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident ID"})
+			return
+		}
+
+		var req incidentRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		incident, err := incidentService.GetIncident(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+			return
+		}
+
+		req.apply(incident)
+		err = incidentService.UpdateIncident(incident, req.ServiceIDs)
+		if errors.Is(err, services.ErrUnknownService) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, incident)
+	}
+}
+
+// @Summary Resolve an incident
+// @Tags incidents
+// @Produce json
+// @Param id path int true "Incident ID"
+// @Success 200 {object} models.Incident
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /incidents/{id}/resolve [post]
+func resolveIncident(incidentService *services.IncidentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident ID"})
+			return
+		}
+
+		incident, err := incidentService.ResolveIncident(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Incident not found"})
+			return
+		}
+
+		c.JSON(http.StatusOK, incident)
+	}
+}
+
+// @Summary Delete an incident
+// @Tags incidents
+// @Param id path int true "Incident ID"
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /incidents/{id} [delete]
+func deleteIncident(incidentService *services.IncidentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid incident ID"})
+			return
+		}
+
+		if err := incidentService.DeleteIncident(uint(id)); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusNoContent, nil)
+	}
+}
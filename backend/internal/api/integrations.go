@@ -0,0 +1,143 @@
+package api
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Receive Alertmanager notifications
+// @Tags integrations
+// @Accept json
+// @Produce json
+// @Param payload body services.AlertmanagerWebhook true "Alertmanager webhook payload"
+// @Success 200 {object} services.AlertmanagerResult
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /integrations/alertmanager [post]
+func receiveAlertmanagerWebhook(receiver *services.AlertmanagerReceiver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var payload services.AlertmanagerWebhook
+		if err := c.ShouldBindJSON(&payload); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := receiver.HandleWebhook(&payload)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// maxIngestBody bounds the body of ingest, remote write and OTLP requests.
+const maxIngestBody = 32 << 20
+
+// @Summary Receive a Prometheus remote write request
+// @Description A snappy-compressed protobuf WriteRequest.
+// @Tags metrics
+// @Accept application/x-protobuf
+// @Produce json
+// @Param request body string true "Snappy-compressed WriteRequest"
+// @Success 200 {object} services.RemoteWriteResult
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /metrics/remote-write [post]
+func receiveRemoteWrite(receiver *services.RemoteWriteReceiver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIngestBody))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		result, err := receiver.HandleWriteRequest(body)
+		if errors.Is(err, services.ErrInvalidPayload) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// receiveOTLPMetrics implements the OTLP/HTTP metrics endpoint. Responses are
+// empty ExportMetricsServiceResponse messages in the request's encoding, as
+// OTLP exporters expect.
+//
+// @Summary Receive OTLP metrics
+// @Description An OTLP/HTTP ExportMetricsServiceRequest, optionally gzip-encoded. The response is an empty ExportMetricsServiceResponse in the request's encoding.
+// @Tags metrics
+// @Accept application/x-protobuf
+// @Accept json
+// @Produce application/x-protobuf
+// @Produce json
+// @Param request body string true "ExportMetricsServiceRequest"
+// @Success 200
+// @Failure 400 {object} errorResponse
+// @Failure 413 {object} errorResponse
+// @Failure 415 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /otlp/v1/metrics [post]
+func receiveOTLPMetrics(receiver *services.OTLPReceiver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		isJSON := strings.HasPrefix(c.ContentType(), "application/json")
+		if !isJSON && c.ContentType() != "application/x-protobuf" {
+			c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "content type must be application/x-protobuf or application/json"})
+			return
+		}
+
+		var reader io.Reader = http.MaxBytesReader(c.Writer, c.Request.Body, maxIngestBody)
+		if c.GetHeader("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(reader)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			defer gz.Close()
+			// The limit above only covers the compressed body.
+			reader = io.LimitReader(gz, maxIngestBody+1)
+		}
+
+		body, err := io.ReadAll(reader)
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) || len(body) > maxIngestBody {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("request body exceeds %d bytes", maxIngestBody)})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		_, err = receiver.HandleExport(body, isJSON)
+		if errors.Is(err, services.ErrInvalidPayload) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		if isJSON {
+			c.Data(http.StatusOK, "application/json", []byte("{}"))
+			return
+		}
+		c.Data(http.StatusOK, "application/x-protobuf", nil)
+	}
+}
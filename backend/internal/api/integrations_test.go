@@ -0,0 +1,46 @@
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOTLPBodyLimit(t *testing.T) {
+	router := newTestRouter(t)
+
+	gzipped := func(data []byte) *bytes.Buffer {
+		var body bytes.Buffer
+		gz := gzip.NewWriter(&body)
+		gz.Write(data)
+		gz.Close()
+		return &body
+	}
+	tests := []struct {
+		name     string
+		body     *bytes.Buffer
+		encoding string
+		want     int
+	}{
+		{"gzip body", gzipped([]byte("{}")), "gzip", http.StatusOK},
+		{"gzip body that inflates past the limit", gzipped(bytes.Repeat([]byte(" "), maxIngestBody+1)), "gzip",
+			http.StatusRequestEntityTooLarge},
+		{"uncompressed body past the limit", bytes.NewBuffer(make([]byte, maxIngestBody+1)), "", http.StatusRequestEntityTooLarge},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("POST", "/api/v1/otlp/v1/metrics", tt.body)
+			req.Header.Set("Content-Type", "application/json")
+			if tt.encoding != "" {
+				req.Header.Set("Content-Encoding", tt.encoding)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status %d, want %d: %s", rec.Code, tt.want, rec.Body)
+			}
+		})
+	}
+}
@@ -0,0 +1,155 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// List endpoints share one envelope:
+//
+//	{"data": [...], "meta": {"limit", "count", "total", "sort", "next_cursor", "has_more"}, "links": {"self", "first", "next"}}
+//
+// The next and first links are also sent in a Link header.
+
+type pageLinks struct {
+	Self  string `json:"self"`
+	First string `json:"first"`
+	Next  string `json:"next,omitempty"`
+}
+
+// pageRequest reads the limit, cursor and sort query parameters.
+func pageRequest(c *gin.Context) (services.PageRequest, error) {
+	page := services.PageRequest{
+		Cursor: c.Query("cursor"),
+		Sort:   c.Query("sort"),
+	}
+	if limit := c.Query("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return page, fmt.Errorf("%w: limit must be a number", services.ErrInvalidPage)
+		}
+		page.Limit = n
+	}
+	return page, nil
+}
+
+// fieldSelection reads the fields query parameter, a comma-separated list of
+// JSON fields to return, and checks them against the item type. The id is
+// always returned.
+func fieldSelection(c *gin.Context, item interface{}) ([]string, error) {
+	if c.Query("fields") == "" {
+		return nil, nil
+	}
+	known := jsonFields(reflect.TypeOf(item))
+	fields := []string{"id"}
+	for _, field := range strings.Split(c.Query("fields"), ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || field == "id" {
+			continue
+		}
+		if !known[field] {
+			return nil, fmt.Errorf("%w: unknown field %q", services.ErrInvalidPage, field)
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+func hasField(fields []string, name string) bool {
+	if fields == nil {
+		return true
+	}
+	for _, field := range fields {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
+
+// respondPage writes a page of items in the list envelope, keeping only the
+// selected fields when fields is set.
+func respondPage(c *gin.Context, items interface{}, info *services.PageInfo, fields []string) {
+	var data interface{} = items
+	if fields != nil {
+		sparse, err := selectFields(items, fields)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		data = sparse
+	}
+
+	links := pageLinks{
+		Self:  c.Request.URL.RequestURI(),
+		First: pageURL(c, ""),
+	}
+	header := fmt.Sprintf(`<%s>; rel="first"`, links.First)
+	if info.NextCursor != "" {
+		links.Next = pageURL(c, info.NextCursor)
+		header = fmt.Sprintf(`<%s>; rel="next", %s`, links.Next, header)
+	}
+	c.Header("Link", header)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":  data,
+		"meta":  info,
+		"links": links,
+	})
+}
+
+// pageURL is the request URL with its cursor replaced.
+func pageURL(c *gin.Context, cursor string) string {
+	u := *c.Request.URL
+	query := u.Query()
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
+
+func selectFields(items interface{}, fields []string) ([]map[string]json.RawMessage, error) {
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	var full []map[string]json.RawMessage
+	if err := json.Unmarshal(data, &full); err != nil {
+		return nil, err
+	}
+	sparse := make([]map[string]json.RawMessage, len(full))
+	for i, item := range full {
+		sparse[i] = make(map[string]json.RawMessage, len(fields))
+		for _, field := range fields {
+			if value, ok := item[field]; ok {
+				sparse[i][field] = value
+			}
+		}
+	}
+	return sparse, nil
+}
+
+// jsonFields returns the JSON names of a struct's fields.
+func jsonFields(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	fields := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			fields[name] = true
+		}
+	}
+	return fields
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// instrumentRequests counts requests and their latency per route. Requests
+// that match no route are recorded under "unmatched".
+func instrumentRequests() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		services.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// serveMetrics exposes the platform's own metrics and its SLO gauges for
+// Prometheus to scrape.
+func serveMetrics(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body bytes.Buffer
+		if err := sloService.WriteMetrics(&body); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", body.Bytes())
+	}
+}
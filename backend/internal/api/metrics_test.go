@@ -0,0 +1,175 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"slo-platform/internal/services"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// TestMetrics scrapes /metrics after an evaluation and checks every series
+// documented in DEVELOPMENT.md with its labels. Prometheus answers instant
+// queries with no data and fails range queries.
+func TestMetrics(t *testing.T) {
+	prometheus := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/query":
+			w.Write([]byte(`{"status": "success", "data": {"resultType": "vector", "result": []}}`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+			w.Write([]byte(`{"status": "error", "errorType": "internal", "error": "unavailable"}`))
+		}
+	}))
+	defer prometheus.Close()
+
+	db := newTestDB(t)
+	router := newTestRouterOn(db)
+	newTestSLO(t, router)
+	if rec := serve(router, "POST", "/api/v1/slos",
+		`{"name": "checkout \"latency\"", "service_id": 1, "sli_type": "availability", "target": 0.99,
+			"time_window_days": 30, "success_metric": "http_requests_ok_total", "total_metric": "http_requests_total"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create SLO 2: status %d: %s", rec.Code, rec.Body)
+	}
+	timestamp := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	if rec := serve(router, "POST", "/api/v1/metrics/ingest/batch",
+		`[{"service_id": 1, "slo_id": 1, "timestamp": "`+timestamp+`", "value": 1000, "metric_type": "total"},
+		  {"service_id": 1, "slo_id": 1, "timestamp": "`+timestamp+`", "value": 995, "metric_type": "success"}]`); rec.Code != http.StatusAccepted {
+		t.Fatalf("ingest: status %d: %s", rec.Code, rec.Body)
+	}
+
+	// SLO 1 is measured from the ingested samples, SLO 2 has no data.
+	eventHub := services.NewEventHub(db, time.Hour)
+	metricsService := services.NewMetricsService(db, prometheus.URL)
+	sloService := services.NewSLOService(db, services.NewIncidentService(db), metricsService, eventHub)
+	evaluator, err := services.NewSLOEvaluator(db, sloService, eventHub, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result, err := evaluator.Run(time.Now()); err != nil || result.Evaluated != 2 {
+		t.Fatalf("evaluation: %+v, %v", result, err)
+	}
+
+	rec := serve(router, "GET", "/metrics", "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/plain; version=0.0.4") {
+		t.Errorf("Content-Type %q, want the Prometheus text format", got)
+	}
+	families, err := new(expfmt.TextParser).TextToMetricFamilies(rec.Body)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+
+	sloLabels := []string{"environment", "owner_team", "service", "slo", "slo_id"}
+	documented := []struct {
+		name   string
+		kind   dto.MetricType
+		labels []string
+	}{
+		{"slo_platform_http_requests_total", dto.MetricType_COUNTER, []string{"code", "method", "route"}},
+		{"slo_platform_http_request_duration_seconds", dto.MetricType_HISTOGRAM, []string{"method", "route"}},
+		{"slo_platform_prometheus_queries_total", dto.MetricType_COUNTER, []string{"endpoint"}},
+		{"slo_platform_prometheus_query_errors_total", dto.MetricType_COUNTER, []string{"endpoint"}},
+		{"slo_platform_prometheus_query_duration_seconds", dto.MetricType_HISTOGRAM, []string{"endpoint"}},
+		{"slo_platform_evaluation_duration_seconds", dto.MetricType_HISTOGRAM, nil},
+		{"slo_platform_evaluation_slos_total", dto.MetricType_COUNTER, []string{"result"}},
+		{"slo_platform_evaluation_last_success_timestamp_seconds", dto.MetricType_GAUGE, nil},
+		{"slo_platform_slo_target", dto.MetricType_GAUGE, sloLabels},
+		{"slo_platform_slo_sli", dto.MetricType_GAUGE, sloLabels},
+		{"slo_platform_slo_error_budget_remaining_ratio", dto.MetricType_GAUGE, sloLabels},
+		{"slo_platform_slo_burn_rate", dto.MetricType_GAUGE, append([]string{"window"}, sloLabels...)},
+		{"slo_platform_slo_status", dto.MetricType_GAUGE, append([]string{"status"}, sloLabels...)},
+		{"slo_platform_slo_evaluated_timestamp_seconds", dto.MetricType_GAUGE, sloLabels},
+	}
+	for _, want := range documented {
+		family, ok := families[want.name]
+		if !ok {
+			t.Errorf("%s is missing", want.name)
+			continue
+		}
+		if family.GetType() != want.kind {
+			t.Errorf("%s is a %s, want a %s", want.name, family.GetType(), want.kind)
+		}
+		wantLabels := append([]string(nil), want.labels...)
+		sort.Strings(wantLabels)
+		for _, metric := range family.Metric {
+			var names []string
+			for _, label := range metric.Label {
+				names = append(names, label.GetName())
+			}
+			sort.Strings(names)
+			if strings.Join(names, ",") != strings.Join(wantLabels, ",") {
+				t.Errorf("%s has labels %v, want %v", want.name, names, wantLabels)
+				break
+			}
+		}
+	}
+
+	// Values of SLO 1, then of SLO 2, which has no data and only reports its
+	// status, target and evaluation time.
+	series := func(name string, labels ...string) []float64 {
+		var values []float64
+	metrics:
+		for _, metric := range families[name].GetMetric() {
+			for i := 0; i+1 < len(labels); i += 2 {
+				found := false
+				for _, label := range metric.Label {
+					found = found || label.GetName() == labels[i] && label.GetValue() == labels[i+1]
+				}
+				if !found {
+					continue metrics
+				}
+			}
+			if metric.Gauge != nil {
+				values = append(values, metric.Gauge.GetValue())
+			} else {
+				values = append(values, metric.Counter.GetValue())
+			}
+		}
+		return values
+	}
+	checks := []struct {
+		name   string
+		labels []string
+		want   []float64
+	}{
+		{"slo_platform_slo_sli", []string{"slo_id", "1", "slo", "checkout availability", "service", "checkout",
+			"environment", "prod", "owner_team", "payments"}, []float64{0.995}},
+		{"slo_platform_slo_target", []string{"slo_id", "1"}, []float64{0.99}},
+		{"slo_platform_slo_status", []string{"slo_id", "1", "status", "healthy"}, []float64{1}},
+		{"slo_platform_slo_status", []string{"slo_id", "1", "status", "breached"}, []float64{0}},
+		{"slo_platform_slo_burn_rate", []string{"slo_id", "1", "window", "1h"}, []float64{0}},
+		{"slo_platform_slo_target", []string{"slo_id", "2", "slo", `checkout "latency"`}, []float64{0.99}},
+		{"slo_platform_slo_status", []string{"slo_id", "2", "status", "no_data"}, []float64{1}},
+		{"slo_platform_slo_sli", []string{"slo_id", "2"}, nil},
+		{"slo_platform_slo_burn_rate", []string{"slo_id", "2"}, nil},
+		{"slo_platform_evaluation_slos_total", []string{"result", "evaluated"}, []float64{2}},
+	}
+	for _, check := range checks {
+		got := series(check.name, check.labels...)
+		if len(got) != len(check.want) {
+			t.Errorf("%s%v: %v, want %v", check.name, check.labels, got, check.want)
+			continue
+		}
+		for i := range got {
+			if got[i] != check.want[i] {
+				t.Errorf("%s%v: %v, want %v", check.name, check.labels, got, check.want)
+			}
+		}
+	}
+	if len(series("slo_platform_http_requests_total", "method", "POST", "route", "/api/v1/slos", "code", "201")) != 1 {
+		t.Error("requests are not counted by route pattern and code")
+	}
+	if len(series("slo_platform_prometheus_queries_total", "endpoint", "query")) != 1 ||
+		len(series("slo_platform_prometheus_query_errors_total", "endpoint", "query_range")) != 1 {
+		t.Error("queries to Prometheus are not counted by endpoint")
+	}
+}
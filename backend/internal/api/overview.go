@@ -0,0 +1,53 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// getOverview returns fleet-wide SLO health from the evaluator's snapshots,
+// optionally narrowed by owner_team and environment.
+//
+// @Summary Get the fleet overview
+// @Tags status
+// @Produce json
+// @Param owner_team query string false "Owner team"
+// @Param environment query string false "Environment"
+// @Param limit query int false "Size of the worst offender lists" default(10) maximum(100)
+// @Success 200 {object} models.Overview
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /overview [get]
+func getOverview(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter := services.OverviewFilter{
+			OwnerTeam:   c.Query("owner_team"),
+			Environment: c.Query("environment"),
+		}
+		if limit := c.Query("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be a number"})
+				return
+			}
+			filter.Limit = n
+		}
+
+		overview, err := sloService.Overview(filter)
+		if errors.Is(err, services.ErrInvalidPage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+
+		c.JSON(http.StatusOK, overview)
+	}
+}
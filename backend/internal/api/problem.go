@@ -0,0 +1,80 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Validation failures on service and SLO writes are RFC 7807 problem
+// details with one entry per invalid field:
+//
+//	{"type": "about:blank", "title": "Bad Request", "status": 400,
+//	 "detail": "target: must be between 0 and 1, got 5",
+//	 "errors": [{"field": "target", "message": "must be between 0 and 1, got 5"}],
+//	 "error": "target: must be between 0 and 1, got 5"}
+//
+// error repeats detail so clients reading {"error": ...}, the shape of every
+// other error response, keep working.
+
+type problem struct {
+	Type   string                `json:"type" example:"about:blank"`
+	Title  string                `json:"title" example:"Bad Request"`
+	Status int                   `json:"status" example:"400"`
+	Detail string                `json:"detail" example:"target: must be between 0 and 1, got 5"`
+	Errors []services.FieldError `json:"errors,omitempty"`
+	Error  string                `json:"error" example:"target: must be between 0 and 1, got 5"`
+}
+
+func respondProblem(c *gin.Context, status int, err error) {
+	body := problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: err.Error(),
+		Error:  err.Error(),
+	}
+	var invalid *services.ValidationError
+	if errors.As(err, &invalid) {
+		body.Errors = invalid.Fields
+	}
+	c.Header("Content-Type", "application/problem+json")
+	c.JSON(status, body)
+}
+
+// invalidBody turns a JSON type mismatch into a field error; other decode
+// errors are returned as they are.
+func invalidBody(err error) error {
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return &services.ValidationError{Fields: []services.FieldError{{
+			Field:   typeErr.Field,
+			Message: fmt.Sprintf("must be a %s, got %s", typeErr.Type, typeErr.Value),
+		}}}
+	}
+	return err
+}
+
+// respondServiceError writes the error from a service or SLO write:
+// validation failures, conflicts and stale revisions as problem details,
+// anything else as a 500.
+func respondServiceError(c *gin.Context, err error) {
+	var invalid *services.ValidationError
+	switch {
+	case errors.As(err, &invalid):
+		respondProblem(c, http.StatusBadRequest, err)
+	case errors.Is(err, services.ErrInvalidPayload):
+		respondProblem(c, http.StatusBadRequest, invalidBody(err))
+	case errors.Is(err, services.ErrServiceExists), errors.Is(err, services.ErrServiceDeleted):
+		respondProblem(c, http.StatusConflict, err)
+	case errors.Is(err, services.ErrPreconditionFailed):
+		respondProblem(c, http.StatusPreconditionFailed, err)
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
 	"time"
@@ -9,47 +11,100 @@ import (
 	"slo-platform/internal/services"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 )
 
-func SetupRoutes(router *gin.Engine, serviceRegistry *services.ServiceRegistry, sloService *services.SLOService, metricsService *services.MetricsService) {
+func SetupRoutes(router *gin.Engine, serviceRegistry *services.ServiceRegistry, sloService *services.SLOService, metricsService *services.MetricsService, incidentService *services.IncidentService, alertmanagerReceiver *services.AlertmanagerReceiver, remoteWriteReceiver *services.RemoteWriteReceiver, otlpReceiver *services.OTLPReceiver, eventHub *services.EventHub) {
+	router.Use(instrumentRequests())
 	api := router.Group("/api/v1")
 	
 	// Service endpoints
 	api.POST("/services", createService(serviceRegistry))
-	api.GET("/services", listServices(serviceRegistry))
+	api.GET("/services", listServices(serviceRegistry, sloService))
 	api.GET("/services/:id", getService(serviceRegistry))
 	api.PUT("/services/:id", updateService(serviceRegistry))
+	api.PATCH("/services/:id", patchService(serviceRegistry))
 	api.DELETE("/services/:id", deleteService(serviceRegistry))
+	api.POST("/services/:id/restore", restoreService(serviceRegistry))
+	api.GET("/services/:id/environments", listServiceEnvironments(serviceRegistry))
+	api.POST("/services/:id/promote", promoteSLOs(sloService))
+	
+	// Environment endpoints
+	api.GET("/environments", listEnvironments(serviceRegistry))
+	api.POST("/environments", createEnvironment(serviceRegistry))
 	
 	// SLO endpoints
 	api.POST("/slos", createSLO(sloService))
-	api.GET("/services/:serviceId/slos", listSLOs(sloService))
+	api.GET("/slos", listAllSLOs(sloService))
+	api.GET("/services/:id/slos", listSLOs(sloService))
 	api.GET("/slos/:id", getSLO(sloService))
 	api.PUT("/slos/:id", updateSLO(sloService))
+	api.PATCH("/slos/:id", patchSLO(sloService))
 	api.DELETE("/slos/:id", deleteSLO(sloService))
+	api.POST("/slos/:id/restore", restoreSLO(sloService))
 	
 	// Status and monitoring endpoints
 	api.GET("/services/:id/slo-status", getSLOStatus(sloService))
 	api.GET("/services/:id/error-budget", getErrorBudget(sloService))
+	api.GET("/slos/:id/sli", getCurrentSLI(sloService, metricsService))
+	api.GET("/slos/:id/forecast", getBudgetForecast(sloService))
+	api.POST("/services/:id/slo-recommendation", recommendSLOTarget(sloService))
+	api.POST("/slos/:id/simulate", simulateSLOPolicy(sloService))
 	api.GET("/deploy-check", checkDeploySafety(sloService))
+	api.GET("/overview", getOverview(sloService))
+	
+	// Event streams
+	api.GET("/stream", streamEvents(eventHub))
+	api.GET("/stream/ws", streamEventsWebSocket(eventHub))
+	
+	// Incident endpoints
+	api.POST("/incidents", createIncident(incidentService))
+	api.GET("/incidents", listIncidents(incidentService))
+	api.GET("/incidents/:id", getIncident(incidentService))
+	api.PUT("/incidents/:id", updateIncident(incidentService))
+	api.DELETE("/incidents/:id", deleteIncident(incidentService))
+	api.POST("/incidents/:id/resolve", resolveIncident(incidentService))
+	api.GET("/services/:id/incidents", listServiceIncidents(incidentService))
 	
 	// Metrics ingestion
 	api.POST("/metrics/ingest", ingestMetrics(metricsService))
+	api.POST("/metrics/ingest/batch", ingestMetricsBatch(metricsService))
+	api.POST("/metrics/remote-write", receiveRemoteWrite(remoteWriteReceiver))
+	api.POST("/otlp/v1/metrics", receiveOTLPMetrics(otlpReceiver))
+	
+	// Integrations
+	api.POST("/integrations/alertmanager", receiveAlertmanagerWebhook(alertmanagerReceiver))
 	
 	// Health check
 	api.GET("/health", healthCheck)
+
+	// API documentation
+	router.GET("/swagger/*any", serveSwagger())
+
+	// Platform and SLO metrics for Prometheus
+	router.GET("/metrics", serveMetrics(sloService))
 }
 
+// @Summary Register a service
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param service body models.Service true "Service"
+// @Success 201 {object} models.Service
+// @Failure 400 {object} problem "Invalid fields"
+// @Failure 409 {object} problem "A service with this name exists in the environment"
+// @Failure 500 {object} errorResponse
+// @Router /services [post]
 func createService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var service models.Service
 		if err := c.ShouldBindJSON(&service); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondProblem(c, http.StatusBadRequest, invalidBody(err))
 			return
 		}
 		
 		if err := serviceRegistry.CreateService(&service); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondServiceError(c, err)
 			return
 		}
 		
@@ -57,18 +112,66 @@ func createService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
 	}
 }
 
-func listServices(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
+// listServices returns a page of services, filtered by owner_team,
+// environment, name_prefix and status (services with an SLO in that status).
+//
+// @Summary List services
+// @Tags services
+// @Produce json
+// @Param owner_team query string false "Owner team"
+// @Param environment query string false "Environment"
+// @Param name_prefix query string false "Name prefix"
+// @Param status query string false "Only services with an SLO in this status" Enums(healthy, degraded, breached, no_data)
+// @Param limit query int false "Page size" default(50) maximum(200)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Sort field, prefixed with - for descending" default(name)
+// @Param fields query string false "Comma-separated fields to return"
+// @Success 200 {object} listResponse[models.Service]
+// @Header 200 {string} Link "next and first page links"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /services [get]
+func listServices(serviceRegistry *services.ServiceRegistry, sloService *services.SLOService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		services, err := serviceRegistry.ListServices()
+		page, err := pageRequest(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		fields, err := fieldSelection(c, models.Service{})
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		
+		filter := services.ServiceFilter{
+			OwnerTeam:   c.Query("owner_team"),
+			Environment: c.Query("environment"),
+			NamePrefix:  c.Query("name_prefix"),
+			Status:      c.Query("status"),
+		}
+		list, info, err := serviceRegistry.ListServicesPage(filter, page, hasField(fields, "slos"), sloService)
+		if errors.Is(err, services.ErrInvalidPage) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 		
-		c.JSON(http.StatusOK, services)
+		respondPage(c, list, info, fields)
 	}
 }
 
+// @Summary Get a service
+// @Tags services
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {object} models.Service
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /services/{id} [get]
 func getService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -83,10 +186,29 @@ func getService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
 			return
 		}
 		
+		setETag(c, service.UpdatedAt)
 		c.JSON(http.StatusOK, service)
 	}
 }
 
+// updateService replaces every field of the service; fields left out of the
+// body are cleared.
+//
+// @Summary Replace a service
+// @Tags services
+// @Accept json
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param If-Match header string false "ETag from a previous read; the update fails if the service changed since"
+// @Param service body models.Service true "Service"
+// @Success 200 {object} models.Service
+// @Header 200 {string} ETag "Revision of the updated service"
+// @Failure 400 {object} problem "Invalid fields"
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} problem "A service with this name exists in the environment"
+// @Failure 412 {object} problem "The service changed since the If-Match revision"
+// @Failure 500 {object} errorResponse
+// @Router /services/{id} [put]
 func updateService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -97,20 +219,84 @@ func updateService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
 		
 		var service models.Service
 		if err := c.ShouldBindJSON(&service); err != nil {
+			respondProblem(c, http.StatusBadRequest, invalidBody(err))
+			return
+		}
+		
+		updated, err := serviceRegistry.UpdateService(uint(id), &service, ifMatch(c)...)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+			return
+		}
+		if err != nil {
+			respondServiceError(c, err)
+			return
+		}
+		
+		setETag(c, updated.UpdatedAt)
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// patchService applies a JSON merge patch (RFC 7396): only the fields in the
+// body change, and null clears a field.
+//
+// @Summary Update some fields of a service
+// @Tags services
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param If-Match header string false "ETag from a previous read; the update fails if the service changed since"
+// @Param patch body object true "Fields to change"
+// @Success 200 {object} models.Service
+// @Header 200 {string} ETag "Revision of the updated service"
+// @Failure 400 {object} problem "Invalid fields"
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} problem "A service with this name exists in the environment"
+// @Failure 412 {object} problem "The service changed since the If-Match revision"
+// @Failure 500 {object} errorResponse
+// @Router /services/{id} [patch]
+func patchService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+			return
+		}
+		
+		patch, err := c.GetRawData()
+		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 		
-		service.ID = uint(id)
-		if err := serviceRegistry.UpdateService(&service); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		updated, err := serviceRegistry.PatchService(uint(id), patch, ifMatch(c)...)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+			return
+		}
+		if err != nil {
+			respondServiceError(c, err)
 			return
 		}
 		
-		c.JSON(http.StatusOK, service)
+		setETag(c, updated.UpdatedAt)
+		c.JSON(http.StatusOK, updated)
 	}
 }
 
+// deleteService soft-deletes the service along with its SLOs and
+// dependencies; restoreService brings them back.
+//
+// @Summary Delete a service
+// @Tags services
+// @Param id path int true "Service ID"
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /services/{id} [delete]
 func deleteService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -119,7 +305,12 @@ func deleteService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
 			return
 		}
 		
-		if err := serviceRegistry.DeleteService(uint(id)); err != nil {
+		err = serviceRegistry.DeleteService(uint(id))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+			return
+		}
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
@@ -128,16 +319,59 @@ func deleteService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
 	}
 }
 
+// @Summary Restore a deleted service
+// @Description Brings back the service with the SLOs and dependencies deleted along with it. Restoring a live service returns it unchanged.
+// @Tags services
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {object} models.Service
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} problem "A service with this name exists in the environment"
+// @Failure 500 {object} errorResponse
+// @Router /services/{id}/restore [post]
+func restoreService(serviceRegistry *services.ServiceRegistry) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+			return
+		}
+		
+		service, err := serviceRegistry.RestoreService(uint(id))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Service not found"})
+			return
+		}
+		if err != nil {
+			respondServiceError(c, err)
+			return
+		}
+		
+		setETag(c, service.UpdatedAt)
+		c.JSON(http.StatusOK, service)
+	}
+}
+
+// @Summary Create an SLO
+// @Tags slos
+// @Accept json
+// @Produce json
+// @Param slo body models.SLO true "SLO"
+// @Success 201 {object} models.SLO
+// @Failure 400 {object} problem "Invalid fields"
+// @Failure 500 {object} errorResponse
+// @Router /slos [post]
 func createSLO(sloService *services.SLOService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var slo models.SLO
 		if err := c.ShouldBindJSON(&slo); err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			respondProblem(c, http.StatusBadRequest, invalidBody(err))
 			return
 		}
 		
 		if err := sloService.CreateSLO(&slo); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			respondServiceError(c, err)
 			return
 		}
 		
@@ -145,24 +379,112 @@ func createSLO(sloService *services.SLOService) gin.HandlerFunc {
 	}
 }
 
+// @Summary List a service's SLOs
+// @Tags slos
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param sli_type query string false "SLI type"
+// @Param name_prefix query string false "Name prefix"
+// @Param status query string false "Current status" Enums(healthy, degraded, breached, no_data)
+// @Param limit query int false "Page size" default(50) maximum(200)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Sort field, prefixed with - for descending" default(name)
+// @Param fields query string false "Comma-separated fields to return"
+// @Success 200 {object} listResponse[models.SLO]
+// @Header 200 {string} Link "next and first page links"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /services/{id}/slos [get]
 func listSLOs(sloService *services.SLOService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		serviceID, err := strconv.ParseUint(c.Param("serviceId"), 10, 32)
+		serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
 		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
 			return
 		}
 		
-		slos, err := sloService.ListSLOs(uint(serviceID))
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-			return
+		listSLOPage(c, sloService, uint(serviceID))
+	}
+}
+
+// listAllSLOs returns a page of SLOs across services, filtered by
+// service_id, owner_team, environment, sli_type, name_prefix and status.
+//
+// @Summary List SLOs
+// @Tags slos
+// @Produce json
+// @Param service_id query int false "Service ID"
+// @Param owner_team query string false "Owner team of the service"
+// @Param environment query string false "Environment of the service"
+// @Param sli_type query string false "SLI type"
+// @Param name_prefix query string false "Name prefix"
+// @Param status query string false "Current status" Enums(healthy, degraded, breached, no_data)
+// @Param limit query int false "Page size" default(50) maximum(200)
+// @Param cursor query string false "next_cursor of the previous page"
+// @Param sort query string false "Sort field, prefixed with - for descending" default(name)
+// @Param fields query string false "Comma-separated fields to return"
+// @Success 200 {object} listResponse[models.SLO]
+// @Header 200 {string} Link "next and first page links"
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /slos [get]
+func listAllSLOs(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var serviceID uint
+		if value := c.Query("service_id"); value != "" {
+			id, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+				return
+			}
+			serviceID = uint(id)
 		}
 		
-		c.JSON(http.StatusOK, slos)
+		listSLOPage(c, sloService, serviceID)
 	}
 }
 
+func listSLOPage(c *gin.Context, sloService *services.SLOService, serviceID uint) {
+	page, err := pageRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	fields, err := fieldSelection(c, models.SLO{})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	
+	filter := services.SLOFilter{
+		ServiceID:   serviceID,
+		OwnerTeam:   c.Query("owner_team"),
+		Environment: c.Query("environment"),
+		SLIType:     models.SLIType(c.Query("sli_type")),
+		NamePrefix:  c.Query("name_prefix"),
+		Status:      c.Query("status"),
+	}
+	slos, info, err := sloService.ListSLOsPage(filter, page)
+	if errors.Is(err, services.ErrInvalidPage) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	
+	respondPage(c, slos, info, fields)
+}
+
+// @Summary Get an SLO
+// @Tags slos
+// @Produce json
+// @Param id path int true "SLO ID"
+// @Success 200 {object} models.SLO
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Router /slos/{id} [get]
 func getSLO(sloService *services.SLOService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -177,10 +499,28 @@ func getSLO(sloService *services.SLOService) gin.HandlerFunc {
 			return
 		}
 		
+		setETag(c, slo.UpdatedAt)
 		c.JSON(http.StatusOK, slo)
 	}
 }
 
+// updateSLO replaces every field of the SLO; fields left out of the body are
+// cleared or reset to their defaults.
+//
+// @Summary Replace an SLO
+// @Tags slos
+// @Accept json
+// @Produce json
+// @Param id path int true "SLO ID"
+// @Param If-Match header string false "ETag from a previous read; the update fails if the SLO changed since"
+// @Param slo body models.SLO true "SLO"
+// @Success 200 {object} models.SLO
+// @Header 200 {string} ETag "Revision of the updated SLO"
+// @Failure 400 {object} problem "Invalid fields"
+// @Failure 404 {object} errorResponse
+// @Failure 412 {object} problem "The SLO changed since the If-Match revision"
+// @Failure 500 {object} errorResponse
+// @Router /slos/{id} [put]
 func updateSLO(sloService *services.SLOService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -191,20 +531,80 @@ func updateSLO(sloService *services.SLOService) gin.HandlerFunc {
 		
 		var slo models.SLO
 		if err := c.ShouldBindJSON(&slo); err != nil {
+			respondProblem(c, http.StatusBadRequest, invalidBody(err))
+			return
+		}
+		
+		updated, err := sloService.UpdateSLO(uint(id), &slo, ifMatch(c)...)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+			return
+		}
+		if err != nil {
+			respondServiceError(c, err)
+			return
+		}
+		
+		setETag(c, updated.UpdatedAt)
+		c.JSON(http.StatusOK, updated)
+	}
+}
+
+// patchSLO applies a JSON merge patch (RFC 7396): only the fields in the body
+// change, and null clears a field.
+//
+// @Summary Update some fields of an SLO
+// @Tags slos
+// @Accept json
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path int true "SLO ID"
+// @Param If-Match header string false "ETag from a previous read; the update fails if the SLO changed since"
+// @Param patch body object true "Fields to change"
+// @Success 200 {object} models.SLO
+// @Header 200 {string} ETag "Revision of the updated SLO"
+// @Failure 400 {object} problem "Invalid fields"
+// @Failure 404 {object} errorResponse
+// @Failure 412 {object} problem "The SLO changed since the If-Match revision"
+// @Failure 500 {object} errorResponse
+// @Router /slos/{id} [patch]
+func patchSLO(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO ID"})
+			return
+		}
+		
+		patch, err := c.GetRawData()
+		if err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
 		
-		slo.ID = uint(id)
-		if err := sloService.CreateSLO(&slo); err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		updated, err := sloService.PatchSLO(uint(id), patch, ifMatch(c)...)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+			return
+		}
+		if err != nil {
+			respondServiceError(c, err)
 			return
 		}
 		
-		c.JSON(http.StatusOK, slo)
+		setETag(c, updated.UpdatedAt)
+		c.JSON(http.StatusOK, updated)
 	}
 }
 
+// @Summary Delete an SLO
+// @Tags slos
+// @Param id path int true "SLO ID"
+// @Success 204
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /slos/{id} [delete]
 func deleteSLO(sloService *services.SLOService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -213,11 +613,62 @@ func deleteSLO(sloService *services.SLOService) gin.HandlerFunc {
 			return
 		}
 		
-		// Note: This would need to be implemented in SLOService
-		c.JSON(http.StatusNotImplemented, gin.H{"error": "Not implemented"})
+		err = sloService.DeleteSLO(uint(id))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		
+		c.JSON(http.StatusNoContent, nil)
 	}
 }
 
+// @Summary Restore a deleted SLO
+// @Description Restoring a live SLO returns it unchanged. An SLO deleted with its service is restored with the service.
+// @Tags slos
+// @Produce json
+// @Param id path int true "SLO ID"
+// @Success 200 {object} models.SLO
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 409 {object} problem "The SLO's service is deleted"
+// @Failure 500 {object} errorResponse
+// @Router /slos/{id}/restore [post]
+func restoreSLO(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO ID"})
+			return
+		}
+		
+		slo, err := sloService.RestoreSLO(uint(id))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+			return
+		}
+		if err != nil {
+			respondServiceError(c, err)
+			return
+		}
+		
+		setETag(c, slo.UpdatedAt)
+		c.JSON(http.StatusOK, slo)
+	}
+}
+
+// @Summary Get the status of a service's SLOs
+// @Tags status
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.SLOStatus
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /services/{id}/slo-status [get]
 func getSLOStatus(sloService *services.SLOService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -245,6 +696,14 @@ func getSLOStatus(sloService *services.SLOService) gin.HandlerFunc {
 	}
 }
 
+// @Summary Get the error budgets of a service's SLOs
+// @Tags status
+// @Produce json
+// @Param id path int true "Service ID"
+// @Success 200 {array} models.ErrorBudget
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /services/{id}/error-budget [get]
 func getErrorBudget(sloService *services.SLOService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
@@ -272,6 +731,165 @@ func getErrorBudget(sloService *services.SLOService) gin.HandlerFunc {
 	}
 }
 
+// @Summary Measure an SLO's current SLI
+// @Tags status
+// @Produce json
+// @Param id path int true "SLO ID"
+// @Success 200 {object} models.SLIMeasurement
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /slos/{id}/sli [get]
+func getCurrentSLI(sloService *services.SLOService, metricsService *services.MetricsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO ID"})
+			return
+		}
+		
+		slo, err := sloService.GetSLO(uint(id))
+		if err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+			return
+		}
+		
+		measurement, err := metricsService.MeasureSLI(slo)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		
+		c.JSON(http.StatusOK, measurement)
+	}
+}
+
+// @Summary Forecast when an SLO's budget runs out
+// @Tags status
+// @Produce json
+// @Param id path int true "SLO ID"
+// @Success 200 {object} models.BudgetForecast
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /slos/{id}/forecast [get]
+func getBudgetForecast(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO ID"})
+			return
+		}
+		
+		forecast, err := sloService.ForecastExhaustion(uint(id))
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		
+		c.JSON(http.StatusOK, forecast)
+	}
+}
+
+// @Summary Recommend an SLO target from history
+// @Tags status
+// @Accept json
+// @Produce json
+// @Param id path int true "Service ID"
+// @Param request body services.TargetRecommendationRequest true "SLI to backtest"
+// @Success 200 {object} models.TargetRecommendation
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /services/{id}/slo-recommendation [post]
+func recommendSLOTarget(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		serviceID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid service ID"})
+			return
+		}
+		
+		var req services.TargetRecommendationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		
+		recommendation, err := sloService.RecommendTarget(uint(serviceID), req)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidRecommendation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		
+		c.JSON(http.StatusOK, recommendation)
+	}
+}
+
+// @Summary Simulate SLO and deploy policy changes
+// @Tags status
+// @Accept json
+// @Produce json
+// @Param id path int true "SLO ID"
+// @Param request body services.SimulationRequest true "Proposed configuration"
+// @Success 200 {object} models.SimulationResult
+// @Failure 400 {object} errorResponse
+// @Failure 404 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /slos/{id}/simulate [post]
+func simulateSLOPolicy(sloService *services.SLOService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid SLO ID"})
+			return
+		}
+		
+		var req services.SimulationRequest
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		
+		result, err := sloService.SimulatePolicy(uint(id), req)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": "SLO not found"})
+			return
+		}
+		if errors.Is(err, services.ErrInvalidSimulation) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		
+		c.JSON(http.StatusOK, result)
+	}
+}
+
+// @Summary Check whether a deploy is safe
+// @Tags deploys
+// @Produce json
+// @Param service query string true "Service name"
+// @Param env query string true "Environment"
+// @Success 200 {object} models.DeployCheck
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /deploy-check [get]
 func checkDeploySafety(sloService *services.SLOService) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		serviceName := c.Query("service")
@@ -292,16 +910,27 @@ func checkDeploySafety(sloService *services.SLOService) gin.HandlerFunc {
 	}
 }
 
+type metricSample struct {
+	ServiceID  uint     `json:"service_id" binding:"required"`
+	SLOID      uint     `json:"slo_id" binding:"required"`
+	Timestamp  string   `json:"timestamp" binding:"required" example:"2024-01-15T10:30:00Z"` // RFC 3339
+	Value      *float64 `json:"value" binding:"required"` // pointer so 0 is accepted
+	MetricType string   `json:"metric_type" binding:"required,oneof=success total latency" enums:"success,total,latency"`
+}
+
+// @Summary Ingest a metric sample
+// @Tags metrics
+// @Accept json
+// @Produce json
+// @Param sample body metricSample true "Sample"
+// @Success 200 {object} ingestStatus "Duplicate sample, or late for a minute already rolled up"
+// @Success 202 {object} ingestStatus
+// @Failure 400 {object} errorResponse
+// @Failure 500 {object} errorResponse
+// @Router /metrics/ingest [post]
 func ingestMetrics(metricsService *services.MetricsService) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var metric struct {
-			ServiceID  uint    `json:"service_id" binding:"required"`
-			SLOID      uint    `json:"slo_id" binding:"required"`
-			Timestamp  string  `json:"timestamp" binding:"required"`
-			Value      float64 `json:"value" binding:"required"`
-			MetricType string  `json:"metric_type" binding:"required"`
-		}
-		
+		var metric metricSample
 		if err := c.ShouldBindJSON(&metric); err != nil {
 			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
@@ -314,15 +943,79 @@ func ingestMetrics(metricsService *services.MetricsService) gin.HandlerFunc {
 			return
 		}
 		
-		if err := metricsService.IngestMetric(metric.ServiceID, metric.SLOID, timestamp, metric.Value, metric.MetricType); err != nil {
+		status, err := metricsService.IngestMetric(metric.ServiceID, metric.SLOID, timestamp, *metric.Value, metric.MetricType)
+		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 			return
 		}
 		
-		c.JSON(http.StatusAccepted, gin.H{"status": "accepted"})
+		if status != services.IngestAccepted {
+			c.JSON(http.StatusOK, gin.H{"status": status})
+			return
+		}
+		c.JSON(http.StatusAccepted, gin.H{"status": status})
+	}
+}
+
+// ingestMetricsBatch accepts a JSON array (or {"samples": [...]}) or NDJSON
+// body of samples. Invalid samples are reported by index without failing the
+// batch; a request repeated with the same Idempotency-Key gets the original
+// response, and a key reused for different samples is refused.
+//
+// @Summary Ingest a batch of metric samples
+// @Tags metrics
+// @Accept json
+// @Accept application/x-ndjson
+// @Produce json
+// @Param samples body []metricSample true "Samples, as a JSON array or NDJSON"
+// @Param Idempotency-Key header string false "Replays the original response for a repeated key"
+// @Success 202 {object} services.IngestBatchResult
+// @Header 202 {string} Idempotent-Replayed "true when the response is a replay"
+// @Failure 400 {object} services.IngestBatchResult "Every sample was rejected"
+// @Failure 422 {object} errorResponse "Idempotency-Key was used for a different batch"
+// @Failure 500 {object} errorResponse
+// @Router /metrics/ingest/batch [post]
+func ingestMetricsBatch(metricsService *services.MetricsService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxIngestBody))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		
+		ndjson := c.ContentType() == "application/x-ndjson" || c.ContentType() == "application/jsonl"
+		records, err := services.DecodeIngestBatch(body, ndjson)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		
+		result, replayed, err := metricsService.IngestBatch(c.GetHeader("Idempotency-Key"), records)
+		if errors.Is(err, services.ErrIdempotencyKeyReused) {
+			c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		
+		if replayed {
+			c.Header("Idempotent-Replayed", "true")
+		}
+		status := http.StatusAccepted
+		if result.Received > 0 && result.Rejected == result.Received {
+			status = http.StatusBadRequest
+		}
+		c.JSON(status, result)
 	}
 }
 
+// @Summary Health check
+// @Tags health
+// @Produce json
+// @Success 200 {object} healthResponse
+// @Router /health [get]
 func healthCheck(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"status": "healthy",
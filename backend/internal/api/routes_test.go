@@ -0,0 +1,438 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"slo-platform/internal/database"
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestRouter serves the API from a migrated in-memory SQLite database,
+// wired up as main does but without a Prometheus server.
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+	return newTestRouterOn(newTestDB(t))
+}
+
+// newTestDB returns a migrated in-memory SQLite database.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.NewConnection("file::memory:")
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() {
+		if conn, err := db.DB(); err == nil {
+			conn.Close()
+		}
+	})
+	return db
+}
+
+// newTestRouterOn serves the API from db.
+func newTestRouterOn(db *gorm.DB) *gin.Engine {
+	eventHub := services.NewEventHub(db, time.Hour)
+	serviceRegistry := services.NewServiceRegistry(db)
+	metricsService := services.NewMetricsService(db, "")
+	incidentService := services.NewIncidentService(db)
+	sloService := services.NewSLOService(db, incidentService, metricsService, eventHub)
+	alertmanagerReceiver := services.NewAlertmanagerReceiver(db, incidentService, eventHub, services.AlertLabelMapping{
+		ServiceLabels:    []string{"service", "job"},
+		EnvironmentLabel: "environment",
+		SeverityLabel:    "severity",
+		SeverityMap:      map[string]string{"critical": "critical", "warning": "major", "info": "minor"},
+	})
+	remoteWriteReceiver := services.NewRemoteWriteReceiver(db)
+	otlpReceiver := services.NewOTLPReceiver(db)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	SetupRoutes(router, serviceRegistry, sloService, metricsService, incidentService, alertmanagerReceiver, remoteWriteReceiver, otlpReceiver, eventHub)
+	return router
+}
+
+// readTestdata reads a request body recorded for the services tests.
+func readTestdata(t *testing.T, name string) string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "services", "testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// TestRoutes walks the API through a service's life: it registers a service
+// and an SLO, ingests metrics, asks for status, forecasts and deploy checks,
+// handles an incident, streams the events and finally deletes and restores
+// everything. Every registered route must be requested.
+func TestRoutes(t *testing.T) {
+	router := newTestRouter(t)
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	now := time.Now().UTC().Truncate(time.Second)
+	sample := func(age time.Duration, metricType, value string) string {
+		return `{"service_id": 1, "slo_id": 1, "timestamp": "` + now.Add(-age).Format(time.RFC3339) +
+			`", "value": ` + value + `, "metric_type": "` + metricType + `"}`
+	}
+
+	steps := []struct {
+		method      string
+		route       string
+		path        string // route with the IDs filled in, if it has any
+		contentType string // default application/json
+		body        string
+		want        int
+	}{
+		{method: "GET", route: "/api/v1/health", want: 200},
+		{method: "POST", route: "/api/v1/environments", body: `{"name": "perf", "rank": 5}`, want: 201},
+		{method: "POST", route: "/api/v1/environments", body: `{"name": "perf"}`, want: 409},
+		{method: "GET", route: "/api/v1/environments", want: 200},
+
+		{method: "POST", route: "/api/v1/services", want: 201,
+			body: `{"name": "checkout", "owner_team": "payments", "environment": "prod"}`},
+		{method: "POST", route: "/api/v1/services", want: 400, body: `{"name": "checkout"}`},
+		{method: "GET", route: "/api/v1/services", want: 200},
+		{method: "GET", route: "/api/v1/services/:id", path: "/api/v1/services/1", want: 200},
+		{method: "GET", route: "/api/v1/services/:id", path: "/api/v1/services/99", want: 404},
+		{method: "PUT", route: "/api/v1/services/:id", path: "/api/v1/services/1", want: 200,
+			body: `{"name": "checkout", "owner_team": "payments", "environment": "prod", "description": "Checkout API"}`},
+		{method: "PATCH", route: "/api/v1/services/:id", path: "/api/v1/services/1", want: 200,
+			body: `{"description": "Checkout and payment API"}`},
+		{method: "GET", route: "/api/v1/services/:id/environments", path: "/api/v1/services/1/environments", want: 200},
+
+		{method: "POST", route: "/api/v1/slos", want: 201,
+			body: `{"name": "checkout availability", "service_id": 1, "sli_type": "availability", "target": 0.99,
+				"time_window_days": 30, "success_metric": "http_requests_ok_total", "total_metric": "http_requests_total"}`},
+		{method: "POST", route: "/api/v1/slos", want: 400,
+			body: `{"name": "checkout latency", "service_id": 1, "sli_type": "availability", "target": 0.99,
+				"time_window_days": 30, "prometheus_query": "rate(http_requests_total)"}`},
+		{method: "GET", route: "/api/v1/slos", want: 200},
+		{method: "GET", route: "/api/v1/services/:id/slos", path: "/api/v1/services/1/slos", want: 200},
+		{method: "GET", route: "/api/v1/slos/:id", path: "/api/v1/slos/1", want: 200},
+		{method: "PUT", route: "/api/v1/slos/:id", path: "/api/v1/slos/1", want: 200,
+			body: `{"name": "checkout availability", "service_id": 1, "sli_type": "availability", "target": 0.995,
+				"time_window_days": 30, "success_metric": "http_requests_ok_total", "total_metric": "http_requests_total"}`},
+		{method: "PATCH", route: "/api/v1/slos/:id", path: "/api/v1/slos/1", want: 200, body: `{"target": 0.99}`},
+
+		{method: "POST", route: "/api/v1/metrics/ingest", body: sample(time.Minute, "total", "1000"), want: 202},
+		{method: "POST", route: "/api/v1/metrics/ingest", body: sample(time.Minute, "total", "1000"), want: 200},
+		{method: "POST", route: "/api/v1/metrics/ingest", body: `{"service_id": 1, "slo_id": 1}`, want: 400},
+		{method: "POST", route: "/api/v1/metrics/ingest/batch", want: 202,
+			body: "[" + sample(time.Minute, "success", "998") + ", " + sample(2*time.Hour, "total", "900") + ", " +
+				sample(2*time.Hour, "success", "900") + "]"},
+		{method: "POST", route: "/api/v1/metrics/remote-write", contentType: "application/x-protobuf",
+			body: readTestdata(t, "remote_write/checkout.snappy"), want: 200},
+		{method: "POST", route: "/api/v1/metrics/remote-write", contentType: "application/x-protobuf",
+			body: "not snappy", want: 400},
+		{method: "POST", route: "/api/v1/otlp/v1/metrics", body: readTestdata(t, "otlp/export.json"), want: 200},
+		{method: "POST", route: "/api/v1/otlp/v1/metrics", contentType: "text/plain", body: "{}", want: 415},
+
+		{method: "GET", route: "/api/v1/services/:id/slo-status", path: "/api/v1/services/1/slo-status", want: 200},
+		{method: "GET", route: "/api/v1/services/:id/error-budget", path: "/api/v1/services/1/error-budget", want: 200},
+		{method: "GET", route: "/api/v1/slos/:id/sli", path: "/api/v1/slos/1/sli", want: 200},
+		{method: "GET", route: "/api/v1/slos/:id/forecast", path: "/api/v1/slos/1/forecast", want: 200},
+		{method: "GET", route: "/api/v1/slos/:id/forecast", path: "/api/v1/slos/99/forecast", want: 404},
+		{method: "POST", route: "/api/v1/services/:id/slo-recommendation", path: "/api/v1/services/1/slo-recommendation",
+			body: `{"slo_id": 1, "days": 60}`, want: 200},
+		{method: "POST", route: "/api/v1/services/:id/slo-recommendation", path: "/api/v1/services/1/slo-recommendation",
+			body: `{"slo_id": 1, "days": 1000}`, want: 400},
+		{method: "POST", route: "/api/v1/slos/:id/simulate", path: "/api/v1/slos/1/simulate", body: `{"days": 7}`, want: 200},
+		{method: "POST", route: "/api/v1/slos/:id/simulate", path: "/api/v1/slos/99/simulate", body: `{}`, want: 404},
+		{method: "GET", route: "/api/v1/deploy-check", path: "/api/v1/deploy-check?service=checkout&env=prod", want: 200},
+		{method: "GET", route: "/api/v1/overview", want: 200},
+		{method: "POST", route: "/api/v1/services/:id/promote", path: "/api/v1/services/1/promote",
+			body: `{"to_environment": "staging"}`, want: 200},
+
+		{method: "POST", route: "/api/v1/incidents", want: 201,
+			body: `{"title": "Checkout errors", "severity": "major", "service_ids": [1]}`},
+		{method: "POST", route: "/api/v1/incidents", body: `{"title": "Checkout errors", "severity": "low"}`, want: 400},
+		{method: "GET", route: "/api/v1/incidents", want: 200},
+		{method: "GET", route: "/api/v1/incidents/:id", path: "/api/v1/incidents/1", want: 200},
+		{method: "PUT", route: "/api/v1/incidents/:id", path: "/api/v1/incidents/1", want: 200,
+			body: `{"title": "Checkout errors", "severity": "critical", "service_ids": [1]}`},
+		{method: "POST", route: "/api/v1/incidents/:id/resolve", path: "/api/v1/incidents/1/resolve", want: 200},
+		{method: "GET", route: "/api/v1/services/:id/incidents", path: "/api/v1/services/1/incidents", want: 200},
+		{method: "DELETE", route: "/api/v1/incidents/:id", path: "/api/v1/incidents/1", want: 204},
+		{method: "POST", route: "/api/v1/integrations/alertmanager", body: readTestdata(t, "alertmanager/firing.json"), want: 200},
+
+		{method: "GET", route: "/swagger/*any", path: "/swagger/doc.json", want: 200},
+		{method: "GET", route: "/metrics", want: 200},
+
+		{method: "DELETE", route: "/api/v1/slos/:id", path: "/api/v1/slos/1", want: 204},
+		{method: "GET", route: "/api/v1/slos/:id", path: "/api/v1/slos/1", want: 404},
+		{method: "POST", route: "/api/v1/slos/:id/restore", path: "/api/v1/slos/1/restore", want: 200},
+		{method: "DELETE", route: "/api/v1/services/:id", path: "/api/v1/services/1", want: 204},
+		{method: "POST", route: "/api/v1/services/:id/restore", path: "/api/v1/services/1/restore", want: 200},
+	}
+
+	covered := map[string]bool{}
+	for _, step := range steps {
+		path := step.path
+		if path == "" {
+			path = step.route
+		}
+		req, err := http.NewRequest(step.method, server.URL+path, strings.NewReader(step.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		contentType := step.contentType
+		if contentType == "" {
+			contentType = "application/json"
+		}
+		req.Header.Set("Content-Type", contentType)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s: %v", step.method, path, err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if resp.StatusCode != step.want {
+			t.Errorf("%s %s: status %d, want %d: %s", step.method, path, resp.StatusCode, step.want, body)
+		}
+		covered[step.method+" "+step.route] = true
+	}
+
+	// The alert and the deploy check above were published; both streams
+	// replay them from the start.
+	t.Run("stream", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		req, _ := http.NewRequestWithContext(ctx, "GET", server.URL+"/api/v1/stream?last_event_id=0", nil)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			t.Fatalf("status %d, want 200", resp.StatusCode)
+		}
+		line, err := bufio.NewReader(resp.Body).ReadString('\n')
+		if err != nil || !strings.HasPrefix(line, "id: ") {
+			t.Errorf("first line %q (%v), want an event id", line, err)
+		}
+	})
+	covered["GET /api/v1/stream"] = true
+
+	t.Run("stream/ws", func(t *testing.T) {
+		url := "ws" + strings.TrimPrefix(server.URL, "http") + "/api/v1/stream/ws?last_event_id=0"
+		conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+		conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+		var event map[string]interface{}
+		if err := conn.ReadJSON(&event); err != nil {
+			t.Fatal(err)
+		}
+		if event["id"] == nil || event["type"] == nil {
+			t.Errorf("first message %v, want an event", event)
+		}
+	})
+	covered["GET /api/v1/stream/ws"] = true
+
+	for _, route := range router.Routes() {
+		if !covered[route.Method+" "+route.Path] {
+			t.Errorf("%s %s is not tested", route.Method, route.Path)
+		}
+	}
+}
+
+// serve sends one request to router; header holds name, value pairs.
+func serve(router http.Handler, method, path, body string, header ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// newTestSLO registers service 1 "checkout" in prod with availability SLO 1.
+func newTestSLO(t *testing.T, router http.Handler) {
+	t.Helper()
+	if rec := serve(router, "POST", "/api/v1/services",
+		`{"name": "checkout", "owner_team": "payments", "environment": "prod"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create service: status %d: %s", rec.Code, rec.Body)
+	}
+	if rec := serve(router, "POST", "/api/v1/slos",
+		`{"name": "checkout availability", "service_id": 1, "sli_type": "availability", "target": 0.99,
+			"time_window_days": 30, "success_metric": "http_requests_ok_total", "total_metric": "http_requests_total"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create SLO: status %d: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestIngestBatchIdempotency(t *testing.T) {
+	router := newTestRouter(t)
+	newTestSLO(t, router)
+
+	timestamp := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	batch := `{"service_id": 1, "slo_id": 1, "timestamp": "` + timestamp + `", "value": 1000, "metric_type": "total"}
+{"service_id": 1, "slo_id": 1, "timestamp": "` + timestamp + `", "value": 
+`
+	steps := []struct {
+		name     string
+		body     string
+		want     int
+		replayed string
+	}{
+		{"first request", batch, http.StatusAccepted, ""},
+		{"replay", batch, http.StatusAccepted, "true"},
+		{"key reused for other samples", strings.SplitAfter(batch, "\n")[0], http.StatusUnprocessableEntity, ""},
+	}
+	for _, step := range steps {
+		rec := serve(router, "POST", "/api/v1/metrics/ingest/batch", step.body,
+			"Content-Type", "application/x-ndjson", "Idempotency-Key", "batch-1")
+		if rec.Code != step.want {
+			t.Errorf("%s: status %d, want %d: %s", step.name, rec.Code, step.want, rec.Body)
+		}
+		if got := rec.Header().Get("Idempotent-Replayed"); got != step.replayed {
+			t.Errorf("%s: Idempotent-Replayed %q, want %q", step.name, got, step.replayed)
+		}
+		if step.want == http.StatusAccepted && !strings.Contains(rec.Body.String(), `"rejected":1`) {
+			t.Errorf("%s: body %s, want the malformed line rejected", step.name, rec.Body)
+		}
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	router := newTestRouter(t)
+	newTestSLO(t, router)
+
+	for _, path := range []string{"/api/v1/services/1", "/api/v1/slos/1"} {
+		read := serve(router, "GET", path, "")
+		etag := read.Header().Get("ETag")
+		if etag == "" {
+			t.Fatalf("GET %s: no ETag", path)
+		}
+
+		steps := []struct {
+			name    string
+			ifMatch string
+			want    int
+		}{
+			{"current revision", etag, http.StatusOK},
+			{"stale revision", etag, http.StatusPreconditionFailed},
+			{"any revision", "*", http.StatusOK},
+			{"one of several revisions", "", http.StatusOK},
+		}
+		for _, step := range steps {
+			ifMatch := step.ifMatch
+			if ifMatch == "" {
+				ifMatch = `"1", ` + serve(router, "GET", path, "").Header().Get("ETag")
+			}
+			rec := serve(router, "PATCH", path, `{"description": "`+step.name+`"}`, "If-Match", ifMatch)
+			if rec.Code != step.want {
+				t.Errorf("PATCH %s, %s: status %d, want %d: %s", path, step.name, rec.Code, step.want, rec.Body)
+			}
+			if rec.Code == http.StatusOK && rec.Header().Get("ETag") == etag {
+				t.Errorf("PATCH %s, %s: ETag unchanged", path, step.name)
+			}
+		}
+	}
+
+	stale := `"` + services.Revision(time.Unix(0, 0)) + `"`
+	rec := serve(router, "PUT", "/api/v1/services/1",
+		`{"name": "checkout", "owner_team": "payments", "environment": "prod"}`, "If-Match", stale)
+	if rec.Code != http.StatusPreconditionFailed {
+		t.Errorf("PUT with a stale revision: status %d, want 412: %s", rec.Code, rec.Body)
+	}
+}
+
+func TestMergePatch(t *testing.T) {
+	router := newTestRouter(t)
+	newTestSLO(t, router)
+
+	steps := []struct {
+		path  string
+		patch string
+		want  map[string]interface{}
+	}{
+		{"/api/v1/services/1", `{"description": "Checkout API"}`,
+			map[string]interface{}{"description": "Checkout API", "owner_team": "payments"}},
+		{"/api/v1/services/1", `{"description": null}`,
+			map[string]interface{}{"description": "", "owner_team": "payments"}},
+		{"/api/v1/slos/1", `{"description": "Successful requests", "group_by": "region"}`,
+			map[string]interface{}{"description": "Successful requests", "group_by": "region", "target": 0.99}},
+		{"/api/v1/slos/1", `{"group_by": null}`,
+			map[string]interface{}{"description": "Successful requests", "group_by": "", "target": 0.99}},
+	}
+	for _, step := range steps {
+		rec := serve(router, "PATCH", step.path, step.patch, "Content-Type", "application/merge-patch+json")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("PATCH %s %s: status %d: %s", step.path, step.patch, rec.Code, rec.Body)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal(rec.Body.Bytes(), &got); err != nil {
+			t.Fatal(err)
+		}
+		for field, want := range step.want {
+			if got[field] != want {
+				t.Errorf("PATCH %s %s: %s is %v, want %v", step.path, step.patch, field, got[field], want)
+			}
+		}
+	}
+
+	if rec := serve(router, "PATCH", "/api/v1/services/1", `["description"]`); rec.Code != http.StatusBadRequest {
+		t.Errorf("patch that is not an object: status %d, want 400", rec.Code)
+	}
+}
+
+// TestSoftDelete deletes a service with two SLOs, one of them deleted
+// before: restoring the service brings back only the SLO deleted with it.
+func TestSoftDelete(t *testing.T) {
+	router := newTestRouter(t)
+	newTestSLO(t, router)
+	if rec := serve(router, "POST", "/api/v1/slos",
+		`{"name": "checkout latency", "service_id": 1, "sli_type": "availability", "target": 0.99,
+			"time_window_days": 30, "success_metric": "http_requests_ok_total", "total_metric": "http_requests_total"}`); rec.Code != http.StatusCreated {
+		t.Fatalf("create SLO 2: status %d: %s", rec.Code, rec.Body)
+	}
+
+	steps := []struct {
+		method string
+		path   string
+		want   int
+	}{
+		{"DELETE", "/api/v1/slos/2", http.StatusNoContent},
+		{"DELETE", "/api/v1/services/1", http.StatusNoContent},
+		{"GET", "/api/v1/services/1", http.StatusNotFound},
+		{"GET", "/api/v1/slos/1", http.StatusNotFound},
+		{"POST", "/api/v1/slos/1/restore", http.StatusConflict},
+		{"POST", "/api/v1/services/1/restore", http.StatusOK},
+		{"GET", "/api/v1/slos/1", http.StatusOK},
+		{"GET", "/api/v1/slos/2", http.StatusNotFound},
+		{"POST", "/api/v1/slos/2/restore", http.StatusOK},
+		{"GET", "/api/v1/slos/2", http.StatusOK},
+		{"POST", "/api/v1/services/1/restore", http.StatusOK},
+		{"DELETE", "/api/v1/slos/99", http.StatusNotFound},
+		{"POST", "/api/v1/slos/99/restore", http.StatusNotFound},
+	}
+	for _, step := range steps {
+		if rec := serve(router, step.method, step.path, ""); rec.Code != step.want {
+			t.Errorf("%s %s: status %d, want %d: %s", step.method, step.path, rec.Code, step.want, rec.Body)
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package api
+
+import "slo-platform/internal/services"
+
+// Response shapes written with gin.H, named so the OpenAPI spec can
+// describe them.
+
+// errorResponse is the body of 4xx and 5xx responses, except the 400, 409
+// and 412 responses to service and SLO writes, which are problem details.
+type errorResponse struct {
+	Error string `json:"error" example:"Service not found"`
+}
+
+// listResponse is the envelope written by respondPage. With fields set,
+// items only carry the selected fields.
+type listResponse[T any] struct {
+	Data  []T               `json:"data"`
+	Meta  services.PageInfo `json:"meta"`
+	Links pageLinks         `json:"links"`
+}
+
+type ingestStatus struct {
+	Status string `json:"status" example:"accepted"` // "accepted", "duplicate" or "late"
+}
+
+type healthResponse struct {
+	Status  string `json:"status" example:"healthy"`
+	Service string `json:"service" example:"slo-platform"`
+}
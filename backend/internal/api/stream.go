@@ -0,0 +1,317 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"slo-platform/internal/models"
+	"slo-platform/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+// The stream endpoints push SLO status changes, alert transitions and deploy
+// check decisions as they happen. Both accept the filter query parameters
+// service_id, service, owner_team, environment and types (comma-separated),
+// and resume after an event ID given by the Last-Event-ID header or the
+// last_event_id parameter. Without one they start with live events.
+
+const (
+	streamHeartbeat = 15 * time.Second
+	wsWriteTimeout  = 10 * time.Second
+	wsPongTimeout   = 2 * streamHeartbeat
+)
+
+// The API does not restrict origins elsewhere either, so the dashboard can
+// connect from wherever it is served.
+var upgrader = websocket.Upgrader{
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// wsMessage is a control message on the WebSocket. Clients send
+// {"type": "subscribe", "filter": {...}} to change their filter; the server
+// answers with "subscribed" or "error".
+type wsMessage struct {
+	Type   string                `json:"type"`
+	Filter *services.EventFilter `json:"filter,omitempty"`
+	Error  string                `json:"error,omitempty"`
+}
+
+// streamEvents serves the event stream as Server-Sent Events. Each event's
+// SSE id is its event ID and its SSE event name is its type.
+//
+// @Summary Stream events (Server-Sent Events)
+// @Description Each SSE message has the event ID as id, the event type as event and a StreamEvent as data.
+// @Tags stream
+// @Produce text/event-stream
+// @Param service_id query int false "Service ID"
+// @Param service query string false "Service name"
+// @Param owner_team query string false "Owner team"
+// @Param environment query string false "Environment"
+// @Param types query string false "Comma-separated event types: slo_status, alert, deploy_check"
+// @Param Last-Event-ID header int false "Resume after this event ID"
+// @Param last_event_id query int false "Resume after this event ID"
+// @Success 200 {object} models.StreamEvent
+// @Failure 400 {object} errorResponse
+// @Router /stream [get]
+func streamEvents(eventHub *services.EventHub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := eventFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		lastID, resume, err := lastEventID(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		sub := eventHub.Subscribe(filter)
+		defer sub.Close()
+
+		c.Header("Content-Type", "text/event-stream")
+		c.Header("Cache-Control", "no-cache")
+		c.Header("Connection", "keep-alive")
+		c.Header("X-Accel-Buffering", "no")
+		c.Status(http.StatusOK)
+		c.Writer.Flush()
+
+		send := func(event *models.StreamEvent) error {
+			data, err := json.Marshal(event)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(c.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Type, data); err != nil {
+				return err
+			}
+			c.Writer.Flush()
+			return nil
+		}
+
+		// Live events up to the last replayed ID were already sent. Later
+		// ones are all new, even when they arrive out of ID order.
+		replayedID := lastID
+		if resume {
+			if replayedID, err = replayEvents(sub, lastID, send); err != nil {
+				fmt.Fprintf(c.Writer, "event: error\ndata: %q\n\n", err.Error())
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-c.Request.Context().Done():
+				return
+			case event, ok := <-sub.Events():
+				if !ok {
+					// Fell behind; the client reconnects with Last-Event-ID.
+					return
+				}
+				if event.ID <= replayedID {
+					continue
+				}
+				if err := send(&event); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(c.Writer, ": keepalive\n\n"); err != nil {
+					return
+				}
+				c.Writer.Flush()
+			}
+		}
+	}
+}
+
+// streamEventsWebSocket serves the event stream over a WebSocket. Events are
+// sent as JSON text messages; clients may change their filter at any time.
+//
+// @Summary Stream events (WebSocket)
+// @Description Upgrades to a WebSocket that sends each StreamEvent as a JSON text message. Send {"type": "subscribe", "filter": {...}} to change the filter.
+// @Tags stream
+// @Param service_id query int false "Service ID"
+// @Param service query string false "Service name"
+// @Param owner_team query string false "Owner team"
+// @Param environment query string false "Environment"
+// @Param types query string false "Comma-separated event types: slo_status, alert, deploy_check"
+// @Param last_event_id query int false "Resume after this event ID"
+// @Success 101 {object} models.StreamEvent "Switching protocols"
+// @Failure 400 {object} errorResponse
+// @Router /stream/ws [get]
+func streamEventsWebSocket(eventHub *services.EventHub) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		filter, err := eventFilter(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		lastID, resume, err := lastEventID(c)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+
+		conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+		if err != nil {
+			// The upgrader has already written an error response.
+			return
+		}
+		defer conn.Close()
+
+		sub := eventHub.Subscribe(filter)
+		defer sub.Close()
+
+		// Only this goroutine writes; the reader hands control messages over.
+		replies := make(chan wsMessage)
+		done := make(chan struct{})
+		stop := make(chan struct{})
+		defer close(stop)
+		go readWebSocket(conn, sub, replies, done, stop)
+
+		send := func(message interface{}) error {
+			conn.SetWriteDeadline(time.Now().Add(wsWriteTimeout))
+			return conn.WriteJSON(message)
+		}
+
+		replayedID := lastID
+		if resume {
+			sendEvent := func(event *models.StreamEvent) error { return send(event) }
+			if replayedID, err = replayEvents(sub, lastID, sendEvent); err != nil {
+				send(wsMessage{Type: "error", Error: err.Error()})
+				return
+			}
+		}
+
+		heartbeat := time.NewTicker(streamHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case reply := <-replies:
+				if err := send(reply); err != nil {
+					return
+				}
+			case event, ok := <-sub.Events():
+				if !ok {
+					conn.WriteControl(websocket.CloseMessage,
+						websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "subscriber fell behind; resume with last_event_id"),
+						time.Now().Add(wsWriteTimeout))
+					return
+				}
+				if event.ID <= replayedID {
+					continue
+				}
+				if err := send(&event); err != nil {
+					return
+				}
+			case <-heartbeat.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteTimeout)); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// readWebSocket applies subscribe messages until the connection fails,
+// then closes done. It stops early when the writer closes stop.
+func readWebSocket(conn *websocket.Conn, sub *services.Subscription, replies chan<- wsMessage, done, stop chan struct{}) {
+	defer close(done)
+	conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		conn.SetReadDeadline(time.Now().Add(wsPongTimeout))
+
+		reply := wsMessage{Type: "error"}
+		var message wsMessage
+		if err := json.Unmarshal(data, &message); err != nil || message.Type != "subscribe" || message.Filter == nil {
+			reply.Error = `expected {"type": "subscribe", "filter": {...}}`
+		} else if err := message.Filter.Validate(); err != nil {
+			reply.Error = err.Error()
+		} else {
+			sub.SetFilter(*message.Filter)
+			reply = wsMessage{Type: "subscribed", Filter: message.Filter}
+		}
+
+		select {
+		case replies <- reply:
+		case <-stop:
+			return
+		}
+	}
+}
+
+// replayEvents sends the stored events after afterID and returns the ID of
+// the last one sent.
+func replayEvents(sub *services.Subscription, afterID uint, send func(*models.StreamEvent) error) (uint, error) {
+	for {
+		events, err := sub.Replay(afterID)
+		if err != nil {
+			return afterID, err
+		}
+		for i := range events {
+			if err := send(&events[i]); err != nil {
+				return afterID, err
+			}
+			afterID = events[i].ID
+		}
+		if len(events) < services.MaxReplayEvents {
+			return afterID, nil
+		}
+	}
+}
+
+func eventFilter(c *gin.Context) (services.EventFilter, error) {
+	filter := services.EventFilter{
+		Service:     c.Query("service"),
+		OwnerTeam:   c.Query("owner_team"),
+		Environment: c.Query("environment"),
+	}
+	if serviceID := c.Query("service_id"); serviceID != "" {
+		id, err := strconv.ParseUint(serviceID, 10, 32)
+		if err != nil {
+			return filter, errors.New("Invalid service ID")
+		}
+		filter.ServiceID = uint(id)
+	}
+	for _, eventType := range strings.Split(c.Query("types"), ",") {
+		if eventType = strings.TrimSpace(eventType); eventType != "" {
+			filter.Types = append(filter.Types, eventType)
+		}
+	}
+	return filter, filter.Validate()
+}
+
+// lastEventID reads the event ID to resume after and reports whether one
+// was given.
+func lastEventID(c *gin.Context) (uint, bool, error) {
+	value := c.GetHeader("Last-Event-ID")
+	if value == "" {
+		value = c.Query("last_event_id")
+	}
+	if value == "" {
+		return 0, false, nil
+	}
+	id, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, false, errors.New("last event ID must be a number")
+	}
+	return uint(id), true, nil
+}
@@ -0,0 +1,67 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	"slo-platform/docs"
+
+	"github.com/getkin/kin-openapi/openapi2"
+	"github.com/getkin/kin-openapi/openapi2conv"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/gin-gonic/gin"
+	swaggerFiles "github.com/swaggo/files"
+	ginSwagger "github.com/swaggo/gin-swagger"
+)
+
+// The handler annotations are compiled into the docs package by swag (see
+// go:generate in main.go). swag writes Swagger 2.0, so the spec is converted
+// to OpenAPI 3 once, on first request, and the UI is pointed at that.
+
+const openAPIPath = "/swagger/openapi.json"
+
+var (
+	openAPIOnce sync.Once
+	openAPISpec []byte
+	openAPIErr  error
+)
+
+// serveSwagger serves the OpenAPI 3 spec at /swagger/openapi.json and the
+// Swagger UI under /swagger/index.html.
+func serveSwagger() gin.HandlerFunc {
+	ui := ginSwagger.WrapHandler(swaggerFiles.Handler, ginSwagger.URL(openAPIPath))
+	return func(c *gin.Context) {
+		if c.Request.URL.Path != openAPIPath {
+			ui(c)
+			return
+		}
+		spec, err := openAPIDocument()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "application/json; charset=utf-8", spec)
+	}
+}
+
+func openAPIDocument() ([]byte, error) {
+	openAPIOnce.Do(func() {
+		var doc2 openapi2.T
+		if openAPIErr = json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &doc2); openAPIErr != nil {
+			return
+		}
+		doc3, err := openapi2conv.ToV3(&doc2)
+		if err != nil {
+			openAPIErr = err
+			return
+		}
+		// Without a host the converter drops the base path; a relative
+		// server URL keeps the UI calling the API on whatever host serves it.
+		if len(doc3.Servers) == 0 {
+			doc3.AddServer(&openapi3.Server{URL: doc2.BasePath})
+		}
+		openAPISpec, openAPIErr = json.Marshal(doc3)
+	})
+	return openAPISpec, openAPIErr
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"slo-platform/docs"
+
+	"github.com/getkin/kin-openapi/openapi3"
+)
+
+var pathParam = regexp.MustCompile(`:(\w+)`)
+
+// TestSwaggerCoversRoutes fails when a route under /api/v1 lacks swag
+// annotations or docs was not regenerated after adding it.
+func TestSwaggerCoversRoutes(t *testing.T) {
+	var spec struct {
+		BasePath string                                `json:"basePath"`
+		Paths    map[string]map[string]json.RawMessage `json:"paths"`
+	}
+	if err := json.Unmarshal([]byte(docs.SwaggerInfo.ReadDoc()), &spec); err != nil {
+		t.Fatalf("decode spec: %v", err)
+	}
+	if spec.BasePath != "/api/v1" {
+		t.Fatalf("basePath %q, want /api/v1", spec.BasePath)
+	}
+
+	documented := 0
+	for _, route := range newTestRouter(t).Routes() {
+		if !strings.HasPrefix(route.Path, spec.BasePath+"/") {
+			continue
+		}
+		path := pathParam.ReplaceAllString(strings.TrimPrefix(route.Path, spec.BasePath), "{$1}")
+		if _, ok := spec.Paths[path][strings.ToLower(route.Method)]; !ok {
+			t.Errorf("%s %s is missing from the spec as %s", route.Method, route.Path, path)
+			continue
+		}
+		documented++
+	}
+	if documented == 0 {
+		t.Error("no /api/v1 routes found")
+	}
+}
+
+// TestOpenAPIDocument checks that the converted spec the UI loads is valid
+// OpenAPI 3 and keeps the base path.
+func TestOpenAPIDocument(t *testing.T) {
+	rec := serve(newTestRouter(t), "GET", openAPIPath, "")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d: %s", rec.Code, rec.Body)
+	}
+	doc, err := openapi3.NewLoader().LoadFromData(rec.Body.Bytes())
+	if err != nil {
+		t.Fatalf("load: %v", err)
+	}
+	if err := doc.Validate(context.Background()); err != nil {
+		t.Errorf("invalid OpenAPI 3 document: %v", err)
+	}
+	if len(doc.Servers) != 1 || doc.Servers[0].URL != "/api/v1" {
+		t.Errorf("servers %v, want /api/v1", doc.Servers)
+	}
+	if doc.Paths.Find("/slos/{id}") == nil {
+		t.Error("/slos/{id} is missing")
+	}
+}
@@ -0,0 +1,30 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadSeverityMap(t *testing.T) {
+	tests := []struct {
+		value string
+		err   string // a substring of the error, empty if the map is valid
+	}{
+		{"critical=critical,page=critical,warning=major,info=minor", ""},
+		{"warning=major, info = minor", ""},
+		{"critical=critical,warning=warning", `maps "warning" to "warning"`},
+		{"info=", `maps "info" to ""`},
+	}
+	for _, tt := range tests {
+		t.Setenv("SLO_ALERTMANAGER_SEVERITY_MAP", tt.value)
+		cfg, err := Load()
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: %v", tt.value, err)
+		case tt.err == "" && len(cfg.AlertmanagerSeverityMap) == 0:
+			t.Errorf("%s: empty severity map", tt.value)
+		case tt.err != "" && (err == nil || !strings.Contains(err.Error(), tt.err)):
+			t.Errorf("%s: got %v, want an error containing %q", tt.value, err, tt.err)
+		}
+	}
+}
@@ -1,25 +1,30 @@
 package database
 
 import (
-	"slo-platform/internal/models"
+	"fmt"
+	"strings"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
+// NewConnection opens the database named by databaseURL. The scheme selects
+// the driver: sqlite:// and file: open SQLite, anything else (postgres://,
+// postgresql:// or a key=value DSN) opens PostgreSQL.
 func NewConnection(databaseURL string) (*gorm.DB, error) {
-	db, err := gorm.Open(postgres.Open(databaseURL), &gorm.Config{})
+	dialector := postgres.Open(databaseURL)
+	if strings.HasPrefix(databaseURL, "sqlite://") || strings.HasPrefix(databaseURL, "file:") {
+		var err error
+		if dialector, err = openSQLite(databaseURL); err != nil {
+			return nil, err
+		}
+	} else if scheme, _, ok := strings.Cut(databaseURL, "://"); ok && scheme != "postgres" && scheme != "postgresql" {
+		return nil, fmt.Errorf("unsupported database scheme %q", scheme)
+	}
+
+	db, err := gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
 		return nil, err
 	}
 	return db, nil
 }
-
-func Migrate(db *gorm.DB) error {
-	return db.AutoMigrate(
-		&models.Service{},
-		&models.ServiceDependency{},
-		&models.SLO{},
-		&models.MetricIngest{},
-	)
-}
@@ -0,0 +1,291 @@
+package database
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// Migrations are SQL files embedded from migrations/<dialect>, named
+// <version>_<name>.up.sql and <version>_<name>.down.sql. Applied versions are
+// recorded in schema_migrations; each migration and its record are committed
+// in one transaction.
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// ErrSchemaTooNew is returned when the database has migrations applied that
+// this binary does not know, i.e. it was migrated by a newer release.
+var ErrSchemaTooNew = errors.New("database schema is newer than this binary")
+
+// migrationLockID serializes migrations across instances on Postgres.
+const migrationLockID = 7_151_042
+
+type Migration struct {
+	Version int
+	Name    string
+	up      string
+	down    string
+}
+
+// MigrationState is a known or applied migration. AppliedAt is nil for
+// pending migrations; Known is false for applied migrations this binary
+// does not have.
+type MigrationState struct {
+	Version   int        `json:"version"`
+	Name      string     `json:"name"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Known     bool       `json:"known"`
+}
+
+type schemaMigration struct {
+	Version   int
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// Migrate brings the database up to the latest schema. It refuses to run
+// against a schema migrated by a newer release.
+func Migrate(db *gorm.DB) error {
+	applied, err := MigrateUp(db, 0)
+	if err != nil {
+		return err
+	}
+	if len(applied) > 0 {
+		zap.L().Info("Database migrated", zap.Int("version", applied[len(applied)-1].Version))
+	}
+	now := time.Now()
+	return EnsurePartitions(db, now, now.AddDate(0, 0, partitionsAhead))
+}
+
+// MigrateUp applies up to steps pending migrations, all of them when steps
+// is 0, and returns those applied.
+func MigrateUp(db *gorm.DB, steps int) ([]Migration, error) {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, err
+	}
+	if err := checkSchemaVersion(db, migrations); err != nil {
+		return nil, err
+	}
+
+	var applied []Migration
+	for _, migration := range migrations {
+		if steps > 0 && len(applied) == steps {
+			break
+		}
+		ran := false
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if err := lockMigrations(tx); err != nil {
+				return err
+			}
+			// Another instance may have applied it while we waited.
+			var count int64
+			if err := tx.Model(&schemaMigration{}).Where("version = ?", migration.Version).Count(&count).Error; err != nil {
+				return err
+			}
+			if count > 0 {
+				return nil
+			}
+			if err := tx.Exec(migration.up).Error; err != nil {
+				return err
+			}
+			ran = true
+			return tx.Create(&schemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				AppliedAt: time.Now().UTC(),
+			}).Error
+		})
+		if err != nil {
+			return applied, fmt.Errorf("migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		if ran {
+			zap.L().Info("Applied migration", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+			applied = append(applied, migration)
+		}
+	}
+	return applied, nil
+}
+
+// MigrateDown reverts the last steps applied migrations, newest first, and
+// returns those reverted.
+func MigrateDown(db *gorm.DB, steps int) ([]Migration, error) {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, err
+	}
+	known := make(map[int]Migration, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Version] = migration
+	}
+
+	var reverted []Migration
+	for len(reverted) < steps {
+		var last schemaMigration
+		err := db.Order("version DESC").Limit(1).Find(&last).Error
+		if err != nil {
+			return reverted, err
+		}
+		if last.Version == 0 {
+			break
+		}
+		migration, ok := known[last.Version]
+		if !ok {
+			return reverted, fmt.Errorf("%w: cannot revert unknown migration %04d_%s", ErrSchemaTooNew, last.Version, last.Name)
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			if err := lockMigrations(tx); err != nil {
+				return err
+			}
+			if err := tx.Exec(migration.down).Error; err != nil {
+				return err
+			}
+			return tx.Where("version = ?", migration.Version).Delete(&schemaMigration{}).Error
+		})
+		if err != nil {
+			return reverted, fmt.Errorf("reverting migration %04d_%s: %w", migration.Version, migration.Name, err)
+		}
+		zap.L().Info("Reverted migration", zap.Int("version", migration.Version), zap.String("name", migration.Name))
+		reverted = append(reverted, migration)
+	}
+	return reverted, nil
+}
+
+// MigrationStatus lists known and applied migrations by version.
+func MigrationStatus(db *gorm.DB) ([]MigrationState, error) {
+	migrations, err := loadMigrations(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureMigrationsTable(db); err != nil {
+		return nil, err
+	}
+	var rows []schemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	states := make(map[int]*MigrationState)
+	for _, migration := range migrations {
+		states[migration.Version] = &MigrationState{Version: migration.Version, Name: migration.Name, Known: true}
+	}
+	for _, row := range rows {
+		appliedAt := row.AppliedAt
+		state, ok := states[row.Version]
+		if !ok {
+			state = &MigrationState{Version: row.Version, Name: row.Name}
+			states[row.Version] = state
+		}
+		state.AppliedAt = &appliedAt
+	}
+
+	result := make([]MigrationState, 0, len(states))
+	for _, state := range states {
+		result = append(result, *state)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// checkSchemaVersion fails when an applied version is newer than every
+// migration this binary has.
+func checkSchemaVersion(db *gorm.DB, migrations []Migration) error {
+	var current int
+	if err := db.Model(&schemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&current).Error; err != nil {
+		return err
+	}
+	latest := 0
+	if len(migrations) > 0 {
+		latest = migrations[len(migrations)-1].Version
+	}
+	if current > latest {
+		return fmt.Errorf("%w: database is at version %d, latest known is %d", ErrSchemaTooNew, current, latest)
+	}
+	return nil
+}
+
+func ensureMigrationsTable(db *gorm.DB) error {
+	return db.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version bigint PRIMARY KEY,
+		name text NOT NULL,
+		applied_at timestamp NOT NULL
+	)`).Error
+}
+
+// lockMigrations takes a transaction-scoped lock on Postgres. SQLite
+// serializes writers on its own.
+func lockMigrations(tx *gorm.DB) error {
+	if tx.Dialector.Name() != "postgres" {
+		return nil
+	}
+	return tx.Exec(`SELECT pg_advisory_xact_lock(?)`, migrationLockID).Error
+}
+
+// loadMigrations reads the embedded migrations for a dialect, ordered by
+// version. Every version needs both an up and a down file.
+func loadMigrations(dialect string) ([]Migration, error) {
+	dir := path.Join("migrations", dialect)
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for database %q", dialect)
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, entry := range entries {
+		match := migrationFileName.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %s", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		content, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %04d has two names: %s and %s", version, migration.Name, match[2])
+		}
+		if match[3] == "up" {
+			migration.up = string(content)
+		} else {
+			migration.down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.up == "" || migration.down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both up and down files", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
@@ -0,0 +1,181 @@
+-- Baseline schema. Every statement is idempotent so databases created by
+-- the AutoMigrate-based releases are adopted in place: missing columns and
+-- indexes are added and an unpartitioned metric_ingests is converted.
+
+CREATE TABLE IF NOT EXISTS services (
+    id bigserial PRIMARY KEY,
+    name text NOT NULL,
+    owner_team text NOT NULL,
+    environment text NOT NULL,
+    version text,
+    description text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_services_name ON services (name);
+CREATE INDEX IF NOT EXISTS idx_services_deleted_at ON services (deleted_at);
+
+CREATE TABLE IF NOT EXISTS service_dependencies (
+    id bigserial PRIMARY KEY,
+    service_id bigint NOT NULL,
+    depends_on_id bigint NOT NULL,
+    type text,
+    critical boolean DEFAULT false,
+    CONSTRAINT fk_services_dependencies FOREIGN KEY (service_id) REFERENCES services (id),
+    CONSTRAINT fk_service_dependencies_depends_on FOREIGN KEY (depends_on_id) REFERENCES services (id)
+);
+
+CREATE TABLE IF NOT EXISTS slos (
+    id bigserial PRIMARY KEY,
+    service_id bigint NOT NULL,
+    name text NOT NULL,
+    description text,
+    sli_type text NOT NULL,
+    target decimal NOT NULL,
+    time_window_days bigint NOT NULL,
+    prometheus_query text,
+    success_metric text,
+    total_metric text,
+    latency_threshold decimal,
+    fast_burn_threshold decimal DEFAULT 2,
+    slow_burn_threshold decimal DEFAULT 1,
+    hard_budget_policy boolean DEFAULT false,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz,
+    CONSTRAINT fk_services_sl_os FOREIGN KEY (service_id) REFERENCES services (id)
+);
+ALTER TABLE slos
+    ADD COLUMN IF NOT EXISTS latency_metric text,
+    ADD COLUMN IF NOT EXISTS latency_mode text,
+    ADD COLUMN IF NOT EXISTS series_aggregation text,
+    ADD COLUMN IF NOT EXISTS budget_query_mode text,
+    ADD COLUMN IF NOT EXISTS range_step_seconds bigint,
+    ADD COLUMN IF NOT EXISTS sli_mode text DEFAULT 'request',
+    ADD COLUMN IF NOT EXISTS window_interval_seconds bigint DEFAULT 60,
+    ADD COLUMN IF NOT EXISTS window_threshold decimal,
+    ADD COLUMN IF NOT EXISTS window_comparison text,
+    ADD COLUMN IF NOT EXISTS freshness_metric text,
+    ADD COLUMN IF NOT EXISTS freshness_threshold_seconds decimal,
+    ADD COLUMN IF NOT EXISTS throughput_metric text,
+    ADD COLUMN IF NOT EXISTS min_throughput_per_minute decimal,
+    ADD COLUMN IF NOT EXISTS group_by text,
+    ADD COLUMN IF NOT EXISTS max_slices bigint,
+    ADD COLUMN IF NOT EXISTS ingest_matchers text;
+CREATE INDEX IF NOT EXISTS idx_slos_deleted_at ON slos (deleted_at);
+
+CREATE TABLE IF NOT EXISTS incidents (
+    id bigserial PRIMARY KEY,
+    title text NOT NULL,
+    description text,
+    severity text NOT NULL,
+    status text NOT NULL,
+    source text,
+    external_id text,
+    slo_id bigint,
+    started_at timestamptz NOT NULL,
+    resolved_at timestamptz,
+    postmortem_url text,
+    created_at timestamptz,
+    updated_at timestamptz,
+    deleted_at timestamptz
+);
+CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents (status);
+CREATE INDEX IF NOT EXISTS idx_incidents_external_id ON incidents (external_id);
+CREATE INDEX IF NOT EXISTS idx_incidents_slo_id ON incidents (slo_id);
+CREATE INDEX IF NOT EXISTS idx_incidents_deleted_at ON incidents (deleted_at);
+
+CREATE TABLE IF NOT EXISTS incident_services (
+    incident_id bigint,
+    service_id bigint,
+    PRIMARY KEY (incident_id, service_id),
+    CONSTRAINT fk_incident_services_incident FOREIGN KEY (incident_id) REFERENCES incidents (id),
+    CONSTRAINT fk_incident_services_service FOREIGN KEY (service_id) REFERENCES services (id)
+);
+
+CREATE TABLE IF NOT EXISTS metric_rollups (
+    id bigserial PRIMARY KEY,
+    service_id bigint NOT NULL,
+    slo_id bigint NOT NULL,
+    resolution bigint NOT NULL,
+    timestamp timestamptz NOT NULL,
+    metric_type text NOT NULL,
+    value decimal NOT NULL,
+    count bigint NOT NULL,
+    good decimal,
+    created_at timestamptz,
+    updated_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_metric_rollups_bucket ON metric_rollups (slo_id, resolution, timestamp, metric_type);
+
+CREATE TABLE IF NOT EXISTS ingest_requests (
+    id bigserial PRIMARY KEY,
+    idempotency_key text NOT NULL,
+    response text,
+    created_at timestamptz
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingest_requests_idempotency_key ON ingest_requests (idempotency_key);
+
+-- metric_ingests is range-partitioned by day so old raw samples can be
+-- dropped a partition at a time. An unpartitioned table left by AutoMigrate
+-- is deduplicated and set aside, then copied into the new one below.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM pg_class WHERE oid = to_regclass('metric_ingests') AND relkind = 'r') THEN
+        ALTER TABLE metric_ingests ADD COLUMN IF NOT EXISTS series text NOT NULL DEFAULT '';
+        DELETE FROM metric_ingests WHERE id NOT IN (
+            SELECT MIN(id) FROM metric_ingests GROUP BY slo_id, timestamp, metric_type, series);
+        DROP INDEX IF EXISTS idx_metric_ingests_dedup;
+        DROP INDEX IF EXISTS idx_metric_ingest_slo_timestamp;
+        ALTER TABLE metric_ingests RENAME TO metric_ingests_unpartitioned;
+        ALTER TABLE metric_ingests_unpartitioned RENAME CONSTRAINT metric_ingests_pkey TO metric_ingests_unpartitioned_pkey;
+        ALTER SEQUENCE IF EXISTS metric_ingests_id_seq RENAME TO metric_ingests_unpartitioned_id_seq;
+    END IF;
+END $$;
+
+-- Unique constraints on a partitioned table must include the partition key,
+-- hence the (id, timestamp) primary key.
+CREATE TABLE IF NOT EXISTS metric_ingests (
+    id bigserial,
+    service_id bigint NOT NULL,
+    slo_id bigint NOT NULL,
+    timestamp timestamptz NOT NULL,
+    value decimal NOT NULL,
+    metric_type text,
+    series text NOT NULL DEFAULT '',
+    created_at timestamptz,
+    PRIMARY KEY (id, timestamp)
+) PARTITION BY RANGE (timestamp);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_metric_ingests_dedup ON metric_ingests (slo_id, timestamp, metric_type, series);
+CREATE TABLE IF NOT EXISTS metric_ingests_default PARTITION OF metric_ingests DEFAULT;
+
+DO $$
+DECLARE
+    partition_day date;
+BEGIN
+    IF to_regclass('metric_ingests_unpartitioned') IS NULL THEN
+        RETURN;
+    END IF;
+
+    -- Daily partitions must exist before the copy: rows landing in the
+    -- default partition would block creating their day's partition later.
+    FOR partition_day IN
+        SELECT generate_series(
+            (SELECT COALESCE(MIN(timestamp), now()) FROM metric_ingests_unpartitioned) AT TIME ZONE 'UTC',
+            now() AT TIME ZONE 'UTC',
+            interval '1 day')::date
+    LOOP
+        EXECUTE format('CREATE TABLE IF NOT EXISTS %I PARTITION OF metric_ingests FOR VALUES FROM (%L) TO (%L)',
+            'metric_ingests_p' || to_char(partition_day, 'YYYYMMDD'),
+            to_char(partition_day, 'YYYY-MM-DD') || ' 00:00:00+00',
+            to_char(partition_day + 1, 'YYYY-MM-DD') || ' 00:00:00+00');
+    END LOOP;
+
+    INSERT INTO metric_ingests (id, service_id, slo_id, timestamp, value, metric_type, series, created_at)
+        SELECT id, service_id, slo_id, timestamp, value, metric_type, series, created_at
+        FROM metric_ingests_unpartitioned;
+    PERFORM setval(pg_get_serial_sequence('metric_ingests', 'id'),
+        COALESCE((SELECT MAX(id) FROM metric_ingests), 0) + 1, false);
+    DROP TABLE metric_ingests_unpartitioned;
+END $$;
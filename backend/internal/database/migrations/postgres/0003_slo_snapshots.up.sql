@@ -0,0 +1,18 @@
+-- Latest evaluated status per SLO, written by the evaluation loop and read
+-- by the overview.
+
+CREATE TABLE slo_snapshots (
+    slo_id bigint PRIMARY KEY,
+    service_id bigint NOT NULL,
+    status text NOT NULL,
+    current_sli decimal,
+    target decimal,
+    remaining_budget decimal,
+    consumed_budget decimal,
+    current_burn_rate decimal,
+    fast_burn_rate decimal,
+    slow_burn_rate decimal,
+    time_to_exhaustion bigint,
+    evaluated_at timestamptz NOT NULL
+);
+CREATE INDEX idx_slo_snapshots_service_id ON slo_snapshots (service_id);
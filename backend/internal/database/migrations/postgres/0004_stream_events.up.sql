@@ -0,0 +1,14 @@
+-- Events pushed to /stream subscribers, kept so clients can resume.
+
+CREATE TABLE stream_events (
+    id bigserial PRIMARY KEY,
+    type text NOT NULL,
+    service_id bigint,
+    service_name text,
+    owner_team text,
+    environment text,
+    payload text NOT NULL,
+    created_at timestamptz
+);
+CREATE INDEX idx_stream_events_service_id ON stream_events (service_id);
+CREATE INDEX idx_stream_events_created_at ON stream_events (created_at);
@@ -0,0 +1,4 @@
+-- Without the column soft-deleted dependencies would come back to life.
+DELETE FROM service_dependencies WHERE deleted_at IS NOT NULL;
+DROP INDEX IF EXISTS idx_service_dependencies_deleted_at;
+ALTER TABLE service_dependencies DROP COLUMN deleted_at;
@@ -0,0 +1,4 @@
+-- Dependencies are soft-deleted with their service so that restoring the
+-- service brings them back.
+ALTER TABLE service_dependencies ADD COLUMN deleted_at timestamptz;
+CREATE INDEX idx_service_dependencies_deleted_at ON service_dependencies (deleted_at);
@@ -0,0 +1 @@
+ALTER TABLE ingest_requests DROP COLUMN request_hash;
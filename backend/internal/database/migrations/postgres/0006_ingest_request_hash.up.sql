@@ -0,0 +1,4 @@
+-- Idempotency keys remember a hash of their request so that a key reused
+-- with different samples is refused rather than answered with a replay.
+-- Keys stored before this migration have no hash and still replay.
+ALTER TABLE ingest_requests ADD COLUMN request_hash text NOT NULL DEFAULT '';
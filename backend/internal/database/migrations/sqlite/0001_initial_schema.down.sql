@@ -0,0 +1,8 @@
+DROP TABLE IF EXISTS ingest_requests;
+DROP TABLE IF EXISTS metric_rollups;
+DROP TABLE IF EXISTS metric_ingests;
+DROP TABLE IF EXISTS incident_services;
+DROP TABLE IF EXISTS incidents;
+DROP TABLE IF EXISTS slos;
+DROP TABLE IF EXISTS service_dependencies;
+DROP TABLE IF EXISTS services;
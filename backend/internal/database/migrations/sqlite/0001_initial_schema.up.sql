@@ -0,0 +1,128 @@
+-- Baseline schema. Tables and indexes use IF NOT EXISTS so databases created
+-- by the AutoMigrate-based release are adopted in place.
+
+CREATE TABLE IF NOT EXISTS services (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL,
+    owner_team text NOT NULL,
+    environment text NOT NULL,
+    version text,
+    description text,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_services_name ON services (name);
+CREATE INDEX IF NOT EXISTS idx_services_deleted_at ON services (deleted_at);
+
+CREATE TABLE IF NOT EXISTS service_dependencies (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    service_id integer NOT NULL,
+    depends_on_id integer NOT NULL,
+    type text,
+    critical numeric DEFAULT false,
+    CONSTRAINT fk_services_dependencies FOREIGN KEY (service_id) REFERENCES services (id),
+    CONSTRAINT fk_service_dependencies_depends_on FOREIGN KEY (depends_on_id) REFERENCES services (id)
+);
+
+CREATE TABLE IF NOT EXISTS slos (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    service_id integer NOT NULL,
+    name text NOT NULL,
+    description text,
+    sli_type text NOT NULL,
+    target real NOT NULL,
+    time_window_days integer NOT NULL,
+    prometheus_query text,
+    success_metric text,
+    total_metric text,
+    latency_threshold real,
+    latency_metric text,
+    latency_mode text,
+    series_aggregation text,
+    budget_query_mode text,
+    range_step_seconds integer,
+    sli_mode text DEFAULT 'request',
+    window_interval_seconds integer DEFAULT 60,
+    window_threshold real,
+    window_comparison text,
+    freshness_metric text,
+    freshness_threshold_seconds real,
+    throughput_metric text,
+    min_throughput_per_minute real,
+    group_by text,
+    max_slices integer,
+    ingest_matchers text,
+    fast_burn_threshold real DEFAULT 2,
+    slow_burn_threshold real DEFAULT 1,
+    hard_budget_policy numeric DEFAULT false,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime,
+    CONSTRAINT fk_services_sl_os FOREIGN KEY (service_id) REFERENCES services (id)
+);
+CREATE INDEX IF NOT EXISTS idx_slos_deleted_at ON slos (deleted_at);
+
+CREATE TABLE IF NOT EXISTS incidents (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    title text NOT NULL,
+    description text,
+    severity text NOT NULL,
+    status text NOT NULL,
+    source text,
+    external_id text,
+    slo_id integer,
+    started_at datetime NOT NULL,
+    resolved_at datetime,
+    postmortem_url text,
+    created_at datetime,
+    updated_at datetime,
+    deleted_at datetime
+);
+CREATE INDEX IF NOT EXISTS idx_incidents_status ON incidents (status);
+CREATE INDEX IF NOT EXISTS idx_incidents_external_id ON incidents (external_id);
+CREATE INDEX IF NOT EXISTS idx_incidents_slo_id ON incidents (slo_id);
+CREATE INDEX IF NOT EXISTS idx_incidents_deleted_at ON incidents (deleted_at);
+
+CREATE TABLE IF NOT EXISTS incident_services (
+    incident_id integer,
+    service_id integer,
+    PRIMARY KEY (incident_id, service_id),
+    CONSTRAINT fk_incident_services_incident FOREIGN KEY (incident_id) REFERENCES incidents (id),
+    CONSTRAINT fk_incident_services_service FOREIGN KEY (service_id) REFERENCES services (id)
+);
+
+CREATE TABLE IF NOT EXISTS metric_ingests (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    service_id integer NOT NULL,
+    slo_id integer NOT NULL,
+    timestamp datetime NOT NULL,
+    value real NOT NULL,
+    metric_type text,
+    series text NOT NULL DEFAULT '',
+    created_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_metric_ingests_dedup ON metric_ingests (slo_id, timestamp, metric_type, series);
+
+CREATE TABLE IF NOT EXISTS metric_rollups (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    service_id integer NOT NULL,
+    slo_id integer NOT NULL,
+    resolution integer NOT NULL,
+    timestamp datetime NOT NULL,
+    metric_type text NOT NULL,
+    value real NOT NULL,
+    count integer NOT NULL,
+    good real,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_metric_rollups_bucket ON metric_rollups (slo_id, resolution, timestamp, metric_type);
+
+CREATE TABLE IF NOT EXISTS ingest_requests (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    idempotency_key text NOT NULL,
+    response text,
+    created_at datetime
+);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_ingest_requests_idempotency_key ON ingest_requests (idempotency_key);
@@ -0,0 +1,5 @@
+-- Fails while a service name is used in more than one environment.
+DROP INDEX IF EXISTS idx_services_name_environment;
+CREATE UNIQUE INDEX idx_services_name ON services (name);
+
+DROP TABLE IF EXISTS environments;
@@ -0,0 +1,25 @@
+-- Environments are first class, and a service name is unique per
+-- environment instead of globally. Soft-deleted services do not count.
+
+CREATE TABLE environments (
+    id integer PRIMARY KEY AUTOINCREMENT,
+    name text NOT NULL,
+    description text,
+    rank integer NOT NULL DEFAULT 0,
+    created_at datetime,
+    updated_at datetime
+);
+CREATE UNIQUE INDEX idx_environments_name ON environments (name);
+
+INSERT INTO environments (name, description, rank, created_at, updated_at) VALUES
+    ('dev', 'Development', 10, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+    ('staging', 'Staging', 20, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP),
+    ('prod', 'Production', 30, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);
+
+-- Keep environments already used by services valid.
+INSERT INTO environments (name, rank, created_at, updated_at)
+    SELECT DISTINCT environment, 0, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM services
+    WHERE environment NOT IN (SELECT name FROM environments);
+
+DROP INDEX IF EXISTS idx_services_name;
+CREATE UNIQUE INDEX idx_services_name_environment ON services (name, environment) WHERE deleted_at IS NULL;
@@ -0,0 +1,18 @@
+-- Latest evaluated status per SLO, written by the evaluation loop and read
+-- by the overview.
+
+CREATE TABLE slo_snapshots (
+    slo_id integer PRIMARY KEY,
+    service_id integer NOT NULL,
+    status text NOT NULL,
+    current_sli real,
+    target real,
+    remaining_budget real,
+    consumed_budget real,
+    current_burn_rate real,
+    fast_burn_rate real,
+    slow_burn_rate real,
+    time_to_exhaustion integer,
+    evaluated_at datetime NOT NULL
+);
+CREATE INDEX idx_slo_snapshots_service_id ON slo_snapshots (service_id);
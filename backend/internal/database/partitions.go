@@ -0,0 +1,109 @@
+package database
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// metric_ingests is range-partitioned by day on Postgres so old raw samples
+// can be dropped a partition at a time. A default partition catches samples
+// outside the pre-created range; they move to their day's partition when it
+// is created. The table itself is created by the migrations; partitions are
+// added here as days go by.
+
+const (
+	partitionedTable = "metric_ingests"
+	defaultPartition = "metric_ingests_default"
+	partitionsAhead  = 7 // days of partitions created in advance
+)
+
+// EnsurePartitions creates the daily metric_ingests partitions covering
+// [from, to]. It does nothing on databases without partitioning.
+func EnsurePartitions(db *gorm.DB, from, to time.Time) error {
+	if db.Dialector.Name() != "postgres" {
+		return nil
+	}
+
+	for day := truncateDay(from); !day.After(to); day = day.AddDate(0, 0, 1) {
+		var exists bool
+		if err := db.Raw(`SELECT to_regclass(?) IS NOT NULL`, partitionName(day)).Scan(&exists).Error; err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+		if err := createPartition(db, day); err != nil {
+			return fmt.Errorf("creating partition %s: %w", partitionName(day), err)
+		}
+	}
+	return nil
+}
+
+// createPartition adds the partition for day. Postgres refuses to create a
+// partition while the default partition holds rows in its range, as it does
+// for samples that arrived before their day's partition existed (backfills,
+// clock skew), so those rows are moved out first and inserted again once the
+// partition exists. The default partition is locked for the move so that no
+// new rows for the day land in it meanwhile.
+func createPartition(db *gorm.DB, day time.Time) error {
+	from := day.Format(time.RFC3339)
+	to := day.AddDate(0, 0, 1).Format(time.RFC3339)
+	statements := []string{
+		fmt.Sprintf(`LOCK TABLE %s IN EXCLUSIVE MODE`, defaultPartition),
+		fmt.Sprintf(`CREATE TEMP TABLE metric_ingests_moving (LIKE %s) ON COMMIT DROP`, partitionedTable),
+		fmt.Sprintf(`WITH moved AS (DELETE FROM %s WHERE timestamp >= '%s' AND timestamp < '%s' RETURNING *)
+			INSERT INTO metric_ingests_moving SELECT * FROM moved`, defaultPartition, from, to),
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')`,
+			partitionName(day), partitionedTable, from, to),
+		fmt.Sprintf(`INSERT INTO %s SELECT * FROM metric_ingests_moving`, partitionedTable),
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DropPartitionsBefore drops the daily metric_ingests partitions that end at
+// or before cutoff and returns how many were dropped.
+func DropPartitionsBefore(db *gorm.DB, cutoff time.Time) (int, error) {
+	if db.Dialector.Name() != "postgres" {
+		return 0, nil
+	}
+
+	var partitions []string
+	err := db.Raw(`SELECT c.relname FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = to_regclass(?) AND c.relname LIKE ?`, partitionedTable, partitionedTable+"_p%").
+		Scan(&partitions).Error
+	if err != nil {
+		return 0, err
+	}
+
+	dropped := 0
+	for _, partition := range partitions {
+		day, err := time.Parse("20060102", partition[len(partitionedTable)+2:])
+		if err != nil || day.AddDate(0, 0, 1).After(cutoff) {
+			continue
+		}
+		if err := db.Exec(fmt.Sprintf(`DROP TABLE IF EXISTS %s`, partition)).Error; err != nil {
+			return dropped, err
+		}
+		dropped++
+	}
+	return dropped, nil
+}
+
+func partitionName(day time.Time) string {
+	return fmt.Sprintf("%s_p%s", partitionedTable, day.Format("20060102"))
+}
+
+func truncateDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+)
+
+// openSQLite opens a SQLite database from a sqlite:// URL or a file: DSN,
+// e.g. sqlite://slo.db, sqlite:///var/lib/slo/slo.db or file::memory:.
+func openSQLite(databaseURL string) (gorm.Dialector, error) {
+	dsn := databaseURL
+	if strings.HasPrefix(dsn, "sqlite://") {
+		dsn = "file:" + strings.TrimPrefix(dsn, "sqlite://")
+	}
+	separator := "?"
+	if strings.Contains(dsn, "?") {
+		separator = "&"
+	}
+	dsn += separator + "_foreign_keys=on&_busy_timeout=5000"
+
+	conn, err := sql.Open(sqlite.DriverName, dsn)
+	if err != nil {
+		return nil, err
+	}
+	// A single connection serializes writes and keeps in-memory databases,
+	// which exist per connection, alive.
+	conn.SetMaxOpenConns(1)
+	conn.SetConnMaxLifetime(0)
+
+	return sqlite.Dialector{Conn: &utcConnPool{db: conn}}, nil
+}
+
+// utcConnPool converts time arguments to UTC before they reach SQLite. SQLite
+// stores times as text, so range comparisons are only correct when every
+// value has the same offset.
+type utcConnPool struct {
+	db *sql.DB
+}
+
+func (p *utcConnPool) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return p.db.PrepareContext(ctx, query)
+}
+
+func (p *utcConnPool) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return p.db.ExecContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcConnPool) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return p.db.QueryContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcConnPool) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return p.db.QueryRowContext(ctx, query, utcArgs(args)...)
+}
+
+func (p *utcConnPool) BeginTx(ctx context.Context, opts *sql.TxOptions) (gorm.ConnPool, error) {
+	tx, err := p.db.BeginTx(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	return &utcTx{Tx: tx}, nil
+}
+
+func (p *utcConnPool) GetDBConn() (*sql.DB, error) {
+	return p.db, nil
+}
+
+type utcTx struct {
+	*sql.Tx
+}
+
+func (tx *utcTx) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return tx.Tx.ExecContext(ctx, query, utcArgs(args)...)
+}
+
+func (tx *utcTx) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return tx.Tx.QueryContext(ctx, query, utcArgs(args)...)
+}
+
+func (tx *utcTx) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return tx.Tx.QueryRowContext(ctx, query, utcArgs(args)...)
+}
+
+func utcArgs(args []interface{}) []interface{} {
+	for i, arg := range args {
+		switch value := arg.(type) {
+		case time.Time:
+			args[i] = value.UTC()
+		case *time.Time:
+			if value != nil {
+				args[i] = value.UTC()
+			}
+		case gorm.DeletedAt:
+			if value.Valid {
+				args[i] = value.Time.UTC()
+			}
+		case sql.NullTime:
+			if value.Valid {
+				args[i] = value.Time.UTC()
+			}
+		}
+	}
+	return args
+}
@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Environment is a deployment stage such as dev, staging or prod. A service
+// is registered once per environment it runs in, each with its own SLOs.
+type Environment struct {
+	ID          uint      `json:"id" gorm:"primaryKey"`
+	Name        string    `json:"name" gorm:"uniqueIndex;not null"`
+	Description string    `json:"description"`
+	Rank        int       `json:"rank" gorm:"not null;default:0"` // promotion order, lowest first: dev 10, staging 20, prod 30
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// PromotionResult describes the SLO definitions copied from a service in one
+// environment to the same service in another.
+type PromotionResult struct {
+	SourceServiceID uint     `json:"source_service_id"`
+	FromEnvironment string   `json:"from_environment"`
+	ToEnvironment   string   `json:"to_environment"`
+	Service         Service  `json:"service"`         // the service in the target environment
+	ServiceCreated  bool     `json:"service_created"` // registered in the target environment by the promotion
+	Created         []SLO    `json:"created"`
+	Updated         []SLO    `json:"updated"`
+	Skipped         []string `json:"skipped,omitempty"` // names of SLOs already defined in the target environment
+}
@@ -0,0 +1,36 @@
+package models
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Stream event types.
+const (
+	EventSLOStatus   = "slo_status"   // an SLO's evaluated status changed
+	EventAlert       = "alert"        // an Alertmanager alert fired or resolved
+	EventDeployCheck = "deploy_check" // a deploy check was decided
+)
+
+// StreamEvent is an event pushed to /stream subscribers. Events are kept for
+// a while so clients can resume after the last event ID they saw.
+type StreamEvent struct {
+	ID          uint            `json:"id" gorm:"primaryKey"`
+	Type        string          `json:"type" gorm:"not null"`
+	ServiceID   uint            `json:"service_id" gorm:"index"`
+	ServiceName string          `json:"service_name"`
+	OwnerTeam   string          `json:"owner_team"`
+	Environment string          `json:"environment"`
+	Payload     string          `json:"-" gorm:"not null"`
+	Data        json.RawMessage `json:"data" gorm:"-" swaggertype:"object"` // SLOStatus, AlertEvent or DeployCheck
+	CreatedAt   time.Time       `json:"created_at" gorm:"index"`
+}
+
+// AlertEvent is the data of an alert event.
+type AlertEvent struct {
+	IncidentID  uint             `json:"incident_id"`
+	AlertName   string           `json:"alertname"`
+	Fingerprint string           `json:"fingerprint"`
+	Status      string           `json:"status"` // "firing" or "resolved"
+	Severity    IncidentSeverity `json:"severity,omitempty"`
+}
@@ -0,0 +1,45 @@
+package models
+
+import "time"
+
+// SLISample is the good/total event count for one step of SLI history.
+type SLISample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Good      float64   `json:"good"`
+	Total     float64   `json:"total"`
+}
+
+type ForecastModel string
+
+const (
+	ForecastModelLinear ForecastModel = "linear"
+	ForecastModelEWMA   ForecastModel = "ewma"
+)
+
+type BudgetForecast struct {
+	SLOID       uint      `json:"slo_id"`
+	WindowStart time.Time `json:"window_start"` // start of the rolling window ending now
+	WindowEnd   time.Time `json:"window_end"`   // end of the projection, one window length ahead
+
+	RemainingBudget  float64 `json:"remaining_budget"`  // 0.87 of the window's budget left
+	ProjectedTraffic float64 `json:"projected_traffic"` // events expected until window end
+
+	// Exhaustion estimates from the primary (EWMA) model, widened by the
+	// linear model's range. Nil means the budget lasts past WindowEnd.
+	ExpectedExhaustion    *time.Time `json:"expected_exhaustion,omitempty"`
+	OptimisticExhaustion  *time.Time `json:"optimistic_exhaustion,omitempty"`
+	PessimisticExhaustion *time.Time `json:"pessimistic_exhaustion,omitempty"`
+	WillNotExhaust        bool       `json:"will_not_exhaust"` // true when budget survives until WindowEnd
+
+	Models      []ForecastModelResult `json:"models"`
+	DataPoints  int                   `json:"data_points"`
+	GeneratedAt time.Time             `json:"generated_at"`
+}
+
+type ForecastModelResult struct {
+	Model                 ForecastModel `json:"model"`
+	ErrorRatio            float64       `json:"error_ratio"` // projected bad/total ratio at the start of the horizon
+	ExpectedExhaustion    *time.Time    `json:"expected_exhaustion,omitempty"`
+	OptimisticExhaustion  *time.Time    `json:"optimistic_exhaustion,omitempty"`
+	PessimisticExhaustion *time.Time    `json:"pessimistic_exhaustion,omitempty"`
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type IncidentSeverity string
+
+const (
+	IncidentSeverityCritical IncidentSeverity = "critical"
+	IncidentSeverityMajor    IncidentSeverity = "major"
+	IncidentSeverityMinor    IncidentSeverity = "minor"
+)
+
+// Valid reports whether s is one of the incident severities.
+func (s IncidentSeverity) Valid() bool {
+	return s == IncidentSeverityCritical || s == IncidentSeverityMajor || s == IncidentSeverityMinor
+}
+
+type IncidentStatus string
+
+const (
+	IncidentStatusOpen      IncidentStatus = "open"
+	IncidentStatusMitigated IncidentStatus = "mitigated"
+	IncidentStatusResolved  IncidentStatus = "resolved"
+)
+
+const (
+	IncidentSourceManual       = "manual"
+	IncidentSourceSLOBreach    = "slo_breach"
+	IncidentSourceAlertmanager = "alertmanager"
+)
+
+type Incident struct {
+	ID            uint             `json:"id" gorm:"primaryKey"`
+	Title         string           `json:"title" gorm:"not null"`
+	Description   string           `json:"description"`
+	Severity      IncidentSeverity `json:"severity" gorm:"not null"`
+	Status        IncidentStatus   `json:"status" gorm:"not null;index"`
+	Source        string           `json:"source"`                             // "manual", "slo_breach", "alertmanager"
+	ExternalID    string           `json:"external_id,omitempty" gorm:"index"` // e.g. Alertmanager fingerprint
+	SLOID         *uint            `json:"slo_id,omitempty" gorm:"index"`      // SLO that triggered the incident, if any
+	StartedAt     time.Time        `json:"started_at" gorm:"not null"`
+	ResolvedAt    *time.Time       `json:"resolved_at,omitempty"`
+	PostmortemURL string           `json:"postmortem_url"`
+	CreatedAt     time.Time        `json:"created_at"`
+	UpdatedAt     time.Time        `json:"updated_at"`
+	DeletedAt     gorm.DeletedAt   `json:"-" gorm:"index"`
+
+	Services []Service `json:"services,omitempty" gorm:"many2many:incident_services"`
+
+	// Runtime fields (not persisted)
+	BudgetImpact []IncidentBudgetImpact `json:"budget_impact,omitempty" gorm:"-"`
+}
+
+// IsActive reports whether the incident is still affecting its services.
+func (i *Incident) IsActive() bool {
+	return i.Status != IncidentStatusResolved
+}
+
+// IncidentBudgetImpact is the share of an SLO's error budget consumed while
+// the incident was active.
+type IncidentBudgetImpact struct {
+	SLOID           uint    `json:"slo_id"`
+	SLOName         string  `json:"slo_name"`
+	ServiceName     string  `json:"service_name"`
+	ConsumedBudget  float64 `json:"consumed_budget"`  // 0.00004 of the 0.001 total budget
+	ConsumedPercent float64 `json:"consumed_percent"` // 4.0 (% of the window's budget)
+	Method          string  `json:"method"`           // "events" or "duration"
+}
@@ -0,0 +1,64 @@
+package models
+
+import "time"
+
+// SLOSnapshot is the latest evaluated status of an SLO. The evaluation loop
+// writes one row per SLO so fleet-wide views read stored results instead of
+// querying Prometheus for every SLO.
+type SLOSnapshot struct {
+	SLOID            uint      `json:"slo_id" gorm:"primaryKey;autoIncrement:false"`
+	ServiceID        uint      `json:"service_id" gorm:"not null;index"`
+	Status           string    `json:"status" gorm:"not null"` // "healthy", "degraded", "breached", "no_data"
+	CurrentSLI       float64   `json:"current_sli"`
+	Target           float64   `json:"target"`
+	RemainingBudget  float64   `json:"remaining_budget"` // percent
+	ConsumedBudget   float64   `json:"consumed_budget"`  // percent
+	CurrentBurnRate  float64   `json:"current_burn_rate"`
+	FastBurnRate     float64   `json:"fast_burn_rate"`
+	SlowBurnRate     float64   `json:"slow_burn_rate"`
+	TimeToExhaustion int       `json:"time_to_exhaustion"` // hours, -1 when not burning
+	EvaluatedAt      time.Time `json:"evaluated_at" gorm:"not null"`
+}
+
+// StatusCounts counts SLOs by their last evaluated status.
+type StatusCounts struct {
+	Total       int `json:"total"`
+	Healthy     int `json:"healthy"`
+	Degraded    int `json:"degraded"`
+	Breached    int `json:"breached"`
+	NoData      int `json:"no_data"`
+	Unevaluated int `json:"unevaluated"` // no snapshot yet
+}
+
+// StatusRollup is the status of the SLOs of one owner team or environment.
+type StatusRollup struct {
+	Name string `json:"name"`
+	StatusCounts
+	MinRemainingBudget *float64 `json:"min_remaining_budget,omitempty"` // percent, over SLOs with data
+	MaxBurnRate        *float64 `json:"max_burn_rate,omitempty"`
+}
+
+// OverviewSLO is one SLO in an overview's worst offender lists.
+type OverviewSLO struct {
+	SLOID           uint      `json:"slo_id"`
+	SLOName         string    `json:"slo_name"`
+	ServiceID       uint      `json:"service_id"`
+	ServiceName     string    `json:"service_name"`
+	Environment     string    `json:"environment"`
+	OwnerTeam       string    `json:"owner_team"`
+	Status          string    `json:"status"`
+	RemainingBudget float64   `json:"remaining_budget"`
+	CurrentBurnRate float64   `json:"current_burn_rate"`
+	EvaluatedAt     time.Time `json:"evaluated_at"`
+}
+
+// Overview is the fleet-wide SLO health computed from stored snapshots.
+type Overview struct {
+	Counts           StatusCounts   `json:"counts"`
+	WorstByBudget    []OverviewSLO  `json:"worst_by_budget"`    // lowest remaining budget first
+	WorstByBurnRate  []OverviewSLO  `json:"worst_by_burn_rate"` // fastest burning first
+	Teams            []StatusRollup `json:"teams"`
+	Environments     []StatusRollup `json:"environments"`
+	OldestEvaluation *time.Time     `json:"oldest_evaluation,omitempty"` // the stalest snapshot included
+	GeneratedAt      time.Time      `json:"generated_at"`
+}
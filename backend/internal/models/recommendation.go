@@ -0,0 +1,38 @@
+package models
+
+import "time"
+
+// CandidateTargets are the SLO targets evaluated by target recommendations.
+var CandidateTargets = []float64{0.99, 0.995, 0.999, 0.9995, 0.9999}
+
+type TargetRecommendation struct {
+	ServiceID  uint `json:"service_id"`
+	SLOID      uint `json:"slo_id,omitempty"`
+	Days       int  `json:"days"`        // backtest period
+	WindowDays int  `json:"window_days"` // compliance window length
+
+	Windows    []WindowSLI       `json:"windows"`
+	Candidates []CandidateTarget `json:"candidates"`
+
+	MinSLI    float64 `json:"min_sli"`
+	MedianSLI float64 `json:"median_sli"`
+
+	RecommendedTarget float64   `json:"recommended_target"` // 0 when no candidate qualifies
+	Reasoning         string    `json:"reasoning"`
+	GeneratedAt       time.Time `json:"generated_at"`
+}
+
+// WindowSLI is the SLI achieved over one rolling compliance window.
+type WindowSLI struct {
+	Start  time.Time `json:"start"`
+	End    time.Time `json:"end"`
+	SLI    float64   `json:"sli"`
+	Events float64   `json:"events"`
+}
+
+type CandidateTarget struct {
+	Target         float64 `json:"target"`
+	WindowsMet     int     `json:"windows_met"`
+	ComplianceRate float64 `json:"compliance_rate"`  // 95.0 (% of windows meeting the target)
+	WorstBudgetUse float64 `json:"worst_budget_use"` // 1.4 = worst window used 140% of its budget
+}
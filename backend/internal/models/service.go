@@ -8,9 +8,9 @@ import (
 
 type Service struct {
 	ID          uint      `json:"id" gorm:"primaryKey"`
-	Name        string    `json:"name" gorm:"uniqueIndex;not null"`
+	Name        string    `json:"name" gorm:"not null;uniqueIndex:idx_services_name_environment,priority:1,where:deleted_at IS NULL"` // unique per environment
 	OwnerTeam   string    `json:"owner_team" gorm:"not null"`
-	Environment string    `json:"environment" gorm:"not null"`
+	Environment string    `json:"environment" gorm:"not null;uniqueIndex:idx_services_name_environment,priority:2,where:deleted_at IS NULL"` // name of an Environment
 	Version     string    `json:"version"`
 	Description string    `json:"description"`
 	CreatedAt   time.Time `json:"created_at"`
@@ -27,6 +27,7 @@ type ServiceDependency struct {
 	DependsOnID uint   `json:"depends_on_id" gorm:"not null"`
 	Type         string `json:"type"` // "api", "database", "queue", etc.
 	Critical     bool   `json:"critical" gorm:"default:false"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"` // set when either service is deleted
 	
 	Service      Service `json:"-" gorm:"foreignKey:ServiceID"`
 	DependsOn    Service `json:"-" gorm:"foreignKey:DependsOnID"`
@@ -39,6 +40,48 @@ const (
 	SLITypeLatency      SLIType = "latency"
 	SLITypeErrorRate    SLIType = "error_rate"
 	SLITypeCustom       SLIType = "custom"
+	SLITypeFreshness    SLIType = "freshness"   // data is younger than FreshnessThresholdSeconds
+	SLITypeThroughput   SLIType = "throughput"  // at least MinThroughputPerMinute items processed
+	SLITypeCorrectness  SLIType = "correctness" // validated outputs / total outputs
+	SLITypeDurability   SLIType = "durability"  // intact objects / objects checked
+)
+
+// SLIMode selects whether an SLI counts requests or time.
+type SLIMode string
+
+const (
+	SLIModeRequest SLIMode = "request" // good events / total events
+	SLIModeWindow  SLIMode = "window"  // good intervals / total intervals
+)
+
+// SeriesAggregation combines a query result with several series into one
+// value.
+type SeriesAggregation string
+
+const (
+	SeriesAggregationMin   SeriesAggregation = "min"   // worst series, the default
+	SeriesAggregationMax   SeriesAggregation = "max"
+	SeriesAggregationAvg   SeriesAggregation = "avg"
+	SeriesAggregationSum   SeriesAggregation = "sum"
+	SeriesAggregationError SeriesAggregation = "error" // reject results with several series
+)
+
+// BudgetQueryMode selects how a request-based SLI is read from Prometheus.
+type BudgetQueryMode string
+
+const (
+	BudgetQueryInstant       BudgetQueryMode = "instant"        // current 5m rate ratio (default)
+	BudgetQueryRange         BudgetQueryMode = "range"          // increase() per step over the compliance window, summed
+	BudgetQueryRecordingRule BudgetQueryMode = "recording_rule" // per-step counts pre-aggregated by recording rules, summed
+)
+
+// LatencyMode selects how a latency SLI is read from a histogram.
+type LatencyMode string
+
+const (
+	LatencyModeInterpolate   LatencyMode = "interpolate"    // classic buckets, interpolated at the threshold
+	LatencyModeNearestBucket LatencyMode = "nearest_bucket" // classic buckets, nearest bucket boundary
+	LatencyModeNative        LatencyMode = "native"         // native histogram via histogram_fraction
 )
 
 type SLO struct {
@@ -54,7 +97,35 @@ type SLO struct {
 	PrometheusQuery string  `json:"prometheus_query"`
 	SuccessMetric   string  `json:"success_metric"`
 	TotalMetric     string  `json:"total_metric"`
-	LatencyThreshold float64 `json:"latency_threshold"` // for latency SLOs
+	LatencyThreshold float64 `json:"latency_threshold"` // for latency SLOs, in seconds
+	LatencyMetric    string  `json:"latency_metric"`    // histogram name, default http_request_duration_seconds
+	LatencyMode      LatencyMode `json:"latency_mode"`   // how the threshold is read from the histogram
+	SeriesAggregation SeriesAggregation `json:"series_aggregation"` // for prometheus_query results with several series
+	BudgetQueryMode  BudgetQueryMode `json:"budget_query_mode"`  // how the SLI is computed over the window
+	RangeStepSeconds int             `json:"range_step_seconds"` // step for range and recording_rule modes, default 3600
+	
+	// Window-based SLIs: each interval is good when the query result
+	// compares to WindowThreshold with WindowComparison (e.g. up >= 1)
+	SLIMode                 SLIMode `json:"sli_mode" gorm:"default:request"`
+	WindowIntervalSeconds   int     `json:"window_interval_seconds" gorm:"default:60"`
+	WindowThreshold         float64 `json:"window_threshold"`
+	WindowComparison        string  `json:"window_comparison"` // ">=" (default), ">", "<=", "<"
+	
+	// Freshness and throughput SLIs are always window-based
+	FreshnessMetric           string  `json:"freshness_metric"`            // gauge holding the last update as a unix timestamp
+	FreshnessThresholdSeconds float64 `json:"freshness_threshold_seconds"` // maximum acceptable data age
+	ThroughputMetric          string  `json:"throughput_metric"`           // counter of processed items
+	MinThroughputPerMinute    float64 `json:"min_throughput_per_minute"`
+	
+	// Multi-dimensional SLOs: the SLI and budget are also computed per value
+	// of these labels, keeping at most MaxSlices (worst first)
+	GroupBy   string `json:"group_by"`   // comma-separated labels, e.g. "endpoint,region"
+	MaxSlices int    `json:"max_slices"` // default 50
+	
+	// Pushed metrics (remote_write, OTLP) feed the SLO when their name is
+	// SuccessMetric or TotalMetric and their labels match this selector,
+	// e.g. {job="checkout",env="prod"}. Default: {service="<service name>"}
+	IngestMatchers string `json:"ingest_matchers"`
 	
 	// Burn rate thresholds
 	FastBurnThreshold  float64 `json:"fast_burn_threshold" gorm:"default:2.0"`
@@ -80,7 +151,7 @@ type SLOStatus struct {
 	SLOName            string  `json:"slo_name"`
 	CurrentSLI         float64 `json:"current_sli"`        // 0.9987
 	Target             float64 `json:"target"`            // 0.999
-	Status             string  `json:"status"`             // "healthy", "degraded", "breached"
+	Status             string  `json:"status"`             // "healthy", "degraded", "breached", "no_data"
 	RemainingBudget    float64 `json:"remaining_budget"`   // 0.87 (87%)
 	ConsumedBudget     float64 `json:"consumed_budget"`    // 0.13 (13%)
 	CurrentBurnRate    float64 `json:"current_burn_rate"`  // 1.2
@@ -88,6 +159,52 @@ type SLOStatus struct {
 	SlowBurnRate       float64 `json:"slow_burn_rate"`     // 1.1
 	TimeToExhaustion   int     `json:"time_to_exhaustion"` // hours until budget exhausted
 	LastUpdated        time.Time `json:"last_updated"`
+	
+	// Per-slice results for SLOs with group_by labels, worst first
+	Slices          []SLOSlice `json:"slices,omitempty"`
+	WorstSlice      *SLOSlice  `json:"worst_slice,omitempty"`
+	SlicesTruncated bool       `json:"slices_truncated,omitempty"` // more slices than max_slices were returned
+	
+	DataCoverage float64  `json:"data_coverage,omitempty"` // % of the compliance window with data
+	Warnings     []string `json:"warnings,omitempty"`      // from Prometheus
+}
+
+// SLOSlice is the SLI and error budget for one combination of an SLO's
+// group_by label values.
+type SLOSlice struct {
+	Labels          map[string]string `json:"labels"`           // {"endpoint": "/login"}
+	CurrentSLI      float64           `json:"current_sli"`
+	Good            float64           `json:"good,omitempty"`
+	Total           float64           `json:"total,omitempty"`
+	Status          string            `json:"status"`
+	RemainingBudget float64           `json:"remaining_budget"` // percent
+	ConsumedBudget  float64           `json:"consumed_budget"`  // percent
+}
+
+const (
+	SLISourcePrometheus = "prometheus"
+	SLISourceIngested   = "ingested"
+	SLISourceMock       = "mock"
+)
+
+// SLIMeasurement is a current SLI value with details of how it was computed.
+type SLIMeasurement struct {
+	SLOID  uint    `json:"slo_id"`
+	Value  float64 `json:"value"`            // 0.9987
+	Good   float64 `json:"good,omitempty"`   // good events (or rate) behind Value
+	Total  float64 `json:"total,omitempty"`  // total events (or rate) behind Value
+	Source string  `json:"source"`           // "prometheus", "ingested", "mock"
+	Method string  `json:"method,omitempty"` // latency: "exact_bucket", "interpolated", "nearest_bucket", "native_histogram", "samples", "query"
+	// Histogram bucket boundaries (le) the latency threshold was read from
+	Buckets []float64 `json:"buckets,omitempty"`
+	NoData   bool     `json:"no_data,omitempty"`  // the queries returned no series; Value is meaningless
+	Warnings []string `json:"warnings,omitempty"` // from Prometheus
+	
+	// Window SLIs only: % of the compliance window with data, and the
+	// evaluated range (shorter than the window when retention is)
+	DataCoverage float64    `json:"data_coverage,omitempty"`
+	WindowStart  *time.Time `json:"window_start,omitempty"`
+	WindowEnd    *time.Time `json:"window_end,omitempty"`
 }
 
 type ErrorBudget struct {
@@ -104,6 +221,15 @@ type ErrorBudget struct {
 	SixHourBurn     float64 `json:"six_hour_burn"`     // burn rate over last 6 hours
 	TwentyFourHourBurn float64 `json:"twenty_four_hour_burn"` // burn rate over last 24h
 	
+	// Window-based SLOs only: budget as minutes of allowed bad time
+	TotalBudgetMinutes     float64 `json:"total_budget_minutes,omitempty"`     // 43.2 for 99.9% over 30d
+	ConsumedBudgetMinutes  float64 `json:"consumed_budget_minutes,omitempty"`
+	RemainingBudgetMinutes float64 `json:"remaining_budget_minutes,omitempty"`
+	
+	NoData       bool     `json:"no_data,omitempty"`
+	DataCoverage float64  `json:"data_coverage,omitempty"` // % of the compliance window with data
+	Warnings     []string `json:"warnings,omitempty"`      // from Prometheus
+	
 	LastUpdated time.Time `json:"last_updated"`
 }
 
@@ -115,6 +241,22 @@ const (
 	DeployDecisionBlocked DeployDecision = "BLOCKED"
 )
 
+// DeployPolicy holds the deploy gate thresholds. Budgets are remaining
+// percentages, burn rates are multiples of the sustainable rate.
+type DeployPolicy struct {
+	BlockBudgetBelow float64 `json:"block_budget_below"` // block when budget is below this...
+	BlockBurnAbove   float64 `json:"block_burn_above"`   // ...and burning faster than this
+	RiskyBurnAbove   float64 `json:"risky_burn_above"`
+	RiskyBudgetBelow float64 `json:"risky_budget_below"`
+}
+
+var DefaultDeployPolicy = DeployPolicy{
+	BlockBudgetBelow: 10,
+	BlockBurnAbove:   1,
+	RiskyBurnAbove:   2,
+	RiskyBudgetBelow: 20,
+}
+
 type DeployCheck struct {
 	ServiceName    string        `json:"service_name"`
 	Environment    string        `json:"environment"`
@@ -124,16 +266,49 @@ type DeployCheck struct {
 	BurnRate       float64       `json:"burn_rate"`
 	RecentIncidents bool          `json:"recent_incidents"`
 	LastSLOBreach  *time.Time    `json:"last_slo_breach,omitempty"`
+	WorstSlice     map[string]string `json:"worst_slice,omitempty"` // labels of the slice that decided the check
 	CheckedAt      time.Time     `json:"checked_at"`
 }
 
+// MetricIngest rows are unique per SLO, timestamp, type and series, so
+// retried ingests are dropped instead of counted twice.
 type MetricIngest struct {
 	ID        uint      `json:"id" gorm:"primaryKey"`
 	ServiceID uint      `json:"service_id" gorm:"not null"`
-	SLOID     uint      `json:"slo_id" gorm:"not null"`
-	Timestamp time.Time `json:"timestamp" gorm:"not null"`
+	SLOID     uint      `json:"slo_id" gorm:"not null;uniqueIndex:idx_metric_ingests_dedup,priority:1"`
+	Timestamp time.Time `json:"timestamp" gorm:"not null;uniqueIndex:idx_metric_ingests_dedup,priority:2"`
 	Value     float64   `json:"value" gorm:"not null"`
-	MetricType string   `json:"metric_type"` // "success", "total", "latency"
+	MetricType string   `json:"metric_type" gorm:"uniqueIndex:idx_metric_ingests_dedup,priority:3"` // "success", "total", "latency"
+	Series    string    `json:"series,omitempty" gorm:"not null;default:'';uniqueIndex:idx_metric_ingests_dedup,priority:4"` // set when several series feed the same SLO
 	
 	CreatedAt time.Time `json:"created_at"`
 }
+
+// MetricRollup aggregates the MetricIngest samples of one SLO and type over
+// a 1m or 1h bucket once raw samples age out. Value is the sum of the sample
+// values and Count the number of samples; for latency samples Good counts
+// those within the SLO's latency threshold at the time of the rollup.
+type MetricRollup struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ServiceID  uint      `json:"service_id" gorm:"not null"`
+	SLOID      uint      `json:"slo_id" gorm:"not null;uniqueIndex:idx_metric_rollups_bucket,priority:1"`
+	Resolution int       `json:"resolution" gorm:"not null;uniqueIndex:idx_metric_rollups_bucket,priority:2"` // bucket size in seconds: 60 or 3600
+	Timestamp  time.Time `json:"timestamp" gorm:"not null;uniqueIndex:idx_metric_rollups_bucket,priority:3"` // bucket start
+	MetricType string    `json:"metric_type" gorm:"not null;uniqueIndex:idx_metric_rollups_bucket,priority:4"`
+	Value      float64   `json:"value" gorm:"not null"`
+	Count      int64     `json:"count" gorm:"not null"`
+	Good       float64   `json:"good"`
+	
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// IngestRequest stores the result of a batch ingest sent with an
+// Idempotency-Key, so a retried request gets the original response.
+type IngestRequest struct {
+	ID             uint      `json:"id" gorm:"primaryKey"`
+	IdempotencyKey string    `json:"idempotency_key" gorm:"not null;uniqueIndex"`
+	Response       string    `json:"response" gorm:"type:text"` // JSON-encoded result
+	RequestHash    string    `json:"request_hash" gorm:"not null;default:''"` // SHA-256 of the samples, empty for keys stored before it was recorded
+	CreatedAt      time.Time `json:"created_at"`
+}
@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type SimulationResult struct {
+	SLOID       uint      `json:"slo_id"`
+	Start       time.Time `json:"start"`
+	End         time.Time `json:"end"`
+	DataPoints  int       `json:"data_points"`
+	GeneratedAt time.Time `json:"generated_at"`
+
+	Current  SimulationOutcome `json:"current"`
+	Proposed SimulationOutcome `json:"proposed"`
+}
+
+// SimulationOutcome is what a replay of history through one SLO
+// configuration and deploy policy would have produced.
+type SimulationOutcome struct {
+	Target            float64      `json:"target"`
+	TimeWindowDays    int          `json:"time_window_days"`
+	FastBurnThreshold float64      `json:"fast_burn_threshold"`
+	SlowBurnThreshold float64      `json:"slow_burn_threshold"`
+	Policy            DeployPolicy `json:"policy"`
+
+	DeployChecks   int `json:"deploy_checks"`
+	SafeDeploys    int `json:"safe_deploys"`
+	RiskyDeploys   int `json:"risky_deploys"`
+	BlockedDeploys int `json:"blocked_deploys"`
+
+	BudgetExhaustedDays int     `json:"budget_exhausted_days"`
+	MinRemainingBudget  float64 `json:"min_remaining_budget"` // lowest remaining % seen
+	FastBurnAlerts      int     `json:"fast_burn_alerts"`
+	SlowBurnAlerts      int     `json:"slow_burn_alerts"`
+}
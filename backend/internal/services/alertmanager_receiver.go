@@ -0,0 +1,311 @@
+package services
+
+import (
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+// AlertmanagerWebhook is the payload Alertmanager POSTs to webhook receivers
+// (version 4 of the webhook format).
+type AlertmanagerWebhook struct {
+	Version           string              `json:"version"`
+	GroupKey          string              `json:"groupKey"`
+	TruncatedAlerts   int                 `json:"truncatedAlerts"`
+	Status            string              `json:"status"`
+	Receiver          string              `json:"receiver"`
+	GroupLabels       map[string]string   `json:"groupLabels"`
+	CommonLabels      map[string]string   `json:"commonLabels"`
+	CommonAnnotations map[string]string   `json:"commonAnnotations"`
+	ExternalURL       string              `json:"externalURL"`
+	Alerts            []AlertmanagerAlert `json:"alerts"`
+}
+
+type AlertmanagerAlert struct {
+	Status       string            `json:"status"` // "firing" or "resolved"
+	Labels       map[string]string `json:"labels"`
+	Annotations  map[string]string `json:"annotations"`
+	StartsAt     time.Time         `json:"startsAt"`
+	EndsAt       time.Time         `json:"endsAt"`
+	GeneratorURL string            `json:"generatorURL"`
+	Fingerprint  string            `json:"fingerprint"`
+}
+
+// AlertLabelMapping describes how alert labels map onto services and
+// incident severities.
+type AlertLabelMapping struct {
+	// ServiceLabels are checked in order; the first one present names the service.
+	ServiceLabels []string
+	// EnvironmentLabel narrows the service lookup when present on the alert.
+	EnvironmentLabel string
+	SeverityLabel    string
+	// SeverityMap maps alert severity label values to incident severities.
+	// Alerts whose severity is not in the map are skipped.
+	SeverityMap map[string]string
+}
+
+type AlertmanagerResult struct {
+	Opened   []uint         `json:"opened"`
+	Updated  []uint         `json:"updated"`
+	Resolved []uint         `json:"resolved"`
+	Skipped  []SkippedAlert `json:"skipped"`
+}
+
+type SkippedAlert struct {
+	Fingerprint string `json:"fingerprint"`
+	AlertName   string `json:"alertname"`
+	Reason      string `json:"reason"`
+}
+
+// AlertmanagerReceiver turns Alertmanager notifications into incidents so
+// firing alerts are reflected in deploy checks.
+type AlertmanagerReceiver struct {
+	db              *gorm.DB
+	incidentService *IncidentService
+	events          *EventHub
+	mapping         AlertLabelMapping
+}
+
+func NewAlertmanagerReceiver(db *gorm.DB, incidentService *IncidentService, events *EventHub, mapping AlertLabelMapping) *AlertmanagerReceiver {
+	return &AlertmanagerReceiver{
+		db:              db,
+		incidentService: incidentService,
+		events:          events,
+		mapping:         mapping,
+	}
+}
+
+func (ar *AlertmanagerReceiver) HandleWebhook(payload *AlertmanagerWebhook) (*AlertmanagerResult, error) {
+	result := &AlertmanagerResult{
+		Opened:   []uint{},
+		Updated:  []uint{},
+		Resolved: []uint{},
+		Skipped:  []SkippedAlert{},
+	}
+
+	for _, alert := range payload.Alerts {
+		labels := mergeLabels(payload.CommonLabels, alert.Labels)
+		fingerprint := alert.Fingerprint
+		if fingerprint == "" {
+			fingerprint = labelsFingerprint(labels)
+		}
+
+		if alert.Status == "resolved" {
+			incident, err := ar.resolveAlert(fingerprint, alert.EndsAt)
+			if err != nil {
+				return nil, err
+			}
+			if incident != nil {
+				result.Resolved = append(result.Resolved, incident.ID)
+				ar.publishAlert(incident, fingerprint, labels, "resolved")
+			}
+			continue
+		}
+
+		severity, ok := ar.mapping.SeverityMap[labels[ar.mapping.SeverityLabel]]
+		if !ok {
+			result.Skipped = append(result.Skipped, SkippedAlert{
+				Fingerprint: fingerprint,
+				AlertName:   labels["alertname"],
+				Reason:      fmt.Sprintf("severity %q is not mapped", labels[ar.mapping.SeverityLabel]),
+			})
+			continue
+		}
+
+		services, err := ar.matchServices(labels)
+		if err != nil {
+			return nil, err
+		}
+		if len(services) == 0 {
+			result.Skipped = append(result.Skipped, SkippedAlert{
+				Fingerprint: fingerprint,
+				AlertName:   labels["alertname"],
+				Reason:      "no registered service matches the alert labels",
+			})
+			continue
+		}
+
+		incident, created, err := ar.fireAlert(fingerprint, alert, labels, models.IncidentSeverity(severity), services)
+		if err != nil {
+			return nil, err
+		}
+		if created {
+			result.Opened = append(result.Opened, incident.ID)
+			ar.publishAlert(incident, fingerprint, labels, "firing")
+		} else {
+			result.Updated = append(result.Updated, incident.ID)
+		}
+	}
+
+	return result, nil
+}
+
+func (ar *AlertmanagerReceiver) fireAlert(fingerprint string, alert AlertmanagerAlert, labels map[string]string, severity models.IncidentSeverity, services []models.Service) (*models.Incident, bool, error) {
+	serviceIDs := make([]uint, 0, len(services))
+	for _, service := range services {
+		serviceIDs = append(serviceIDs, service.ID)
+	}
+
+	existing, err := ar.findActive(fingerprint)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		existing.Severity = severity
+		return existing, false, ar.incidentService.UpdateIncident(existing, serviceIDs)
+	}
+
+	startedAt := alert.StartsAt
+	if startedAt.IsZero() {
+		startedAt = time.Now()
+	}
+
+	incident := &models.Incident{
+		Title:       alertTitle(labels, alert.Annotations),
+		Description: alertDescription(alert),
+		Severity:    severity,
+		Status:      models.IncidentStatusOpen,
+		Source:      models.IncidentSourceAlertmanager,
+		ExternalID:  fingerprint,
+		StartedAt:   startedAt,
+	}
+	if err := ar.incidentService.CreateIncident(incident, serviceIDs); err != nil {
+		return nil, false, err
+	}
+	return incident, true, nil
+}
+
+func (ar *AlertmanagerReceiver) resolveAlert(fingerprint string, endsAt time.Time) (*models.Incident, error) {
+	incident, err := ar.findActive(fingerprint)
+	if err != nil || incident == nil {
+		return nil, err
+	}
+
+	if endsAt.IsZero() {
+		endsAt = time.Now()
+	}
+	incident.Status = models.IncidentStatusResolved
+	incident.ResolvedAt = &endsAt
+	if err := ar.incidentService.UpdateIncident(incident, nil); err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+// publishAlert sends an alert transition to the stream once per affected
+// service, so subscribers filtering by service or team receive it.
+func (ar *AlertmanagerReceiver) publishAlert(incident *models.Incident, fingerprint string, labels map[string]string, status string) {
+	if ar.events == nil {
+		return
+	}
+	var services []models.Service
+	if err := ar.db.Model(incident).Association("Services").Find(&services); err != nil {
+		zap.L().Warn("Failed to load alert services", zap.Uint("incident_id", incident.ID), zap.Error(err))
+		return
+	}
+	for i := range services {
+		ar.events.Publish(models.EventAlert, &services[i], models.AlertEvent{
+			IncidentID:  incident.ID,
+			AlertName:   labels["alertname"],
+			Fingerprint: fingerprint,
+			Status:      status,
+			Severity:    incident.Severity,
+		})
+	}
+}
+
+func (ar *AlertmanagerReceiver) findActive(fingerprint string) (*models.Incident, error) {
+	var incident models.Incident
+	err := ar.db.
+		Where("source = ? AND external_id = ? AND status <> ?", models.IncidentSourceAlertmanager, fingerprint, models.IncidentStatusResolved).
+		Limit(1).
+		Find(&incident).Error
+	if err != nil || incident.ID == 0 {
+		return nil, err
+	}
+	return &incident, nil
+}
+
+func (ar *AlertmanagerReceiver) matchServices(labels map[string]string) ([]models.Service, error) {
+	for _, label := range ar.mapping.ServiceLabels {
+		name := labels[label]
+		if name == "" {
+			continue
+		}
+
+		query := ar.db.Where("name = ?", name)
+		if env := labels[ar.mapping.EnvironmentLabel]; ar.mapping.EnvironmentLabel != "" && env != "" {
+			query = query.Where("environment = ?", env)
+		}
+
+		var services []models.Service
+		if err := query.Find(&services).Error; err != nil {
+			return nil, err
+		}
+		if len(services) > 0 {
+			return services, nil
+		}
+	}
+	return nil, nil
+}
+
+func mergeLabels(common, labels map[string]string) map[string]string {
+	merged := make(map[string]string, len(common)+len(labels))
+	for k, v := range common {
+		merged[k] = v
+	}
+	for k, v := range labels {
+		merged[k] = v
+	}
+	return merged
+}
+
+// labelsFingerprint derives a stable identifier for alerts sent without one.
+func labelsFingerprint(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	h := fnv.New64a()
+	for _, k := range keys {
+		h.Write([]byte(k))
+		h.Write([]byte{0})
+		h.Write([]byte(labels[k]))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%016x", h.Sum64())
+}
+
+func alertTitle(labels, annotations map[string]string) string {
+	title := labels["alertname"]
+	if title == "" {
+		title = "Alertmanager alert"
+	}
+	if summary := annotations["summary"]; summary != "" {
+		title += ": " + summary
+	}
+	return title
+}
+
+func alertDescription(alert AlertmanagerAlert) string {
+	var parts []string
+	if description := alert.Annotations["description"]; description != "" {
+		parts = append(parts, description)
+	}
+	if runbook := alert.Annotations["runbook_url"]; runbook != "" {
+		parts = append(parts, "Runbook: "+runbook)
+	}
+	if alert.GeneratorURL != "" {
+		parts = append(parts, "Source: "+alert.GeneratorURL)
+	}
+	return strings.Join(parts, "\n")
+}
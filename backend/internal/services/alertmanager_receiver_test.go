@@ -0,0 +1,146 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"slo-platform/internal/models"
+)
+
+func newTestAlertmanagerReceiver(t *testing.T) (*AlertmanagerReceiver, *IncidentService) {
+	t.Helper()
+	db := newTestDB(t)
+	incidents := NewIncidentService(db)
+	receiver := NewAlertmanagerReceiver(db, incidents, nil, AlertLabelMapping{
+		ServiceLabels:    []string{"service", "job"},
+		EnvironmentLabel: "environment",
+		SeverityLabel:    "severity",
+		SeverityMap:      map[string]string{"critical": "critical", "warning": "major", "info": "minor"},
+	})
+	return receiver, incidents
+}
+
+func handleWebhookFile(t *testing.T, receiver *AlertmanagerReceiver, name string) *AlertmanagerResult {
+	t.Helper()
+	var payload AlertmanagerWebhook
+	readJSON(t, "alertmanager/"+name, &payload)
+	result, err := receiver.HandleWebhook(&payload)
+	if err != nil {
+		t.Fatalf("HandleWebhook(%s): %v", name, err)
+	}
+	return result
+}
+
+func TestAlertmanagerWebhookLifecycle(t *testing.T) {
+	receiver, incidents := newTestAlertmanagerReceiver(t)
+	payments := createService(t, receiver.db, "payment-service", "prod")
+	createService(t, receiver.db, "payment-service", "staging")
+
+	firing := handleWebhookFile(t, receiver, "firing.json")
+	if len(firing.Opened) != 1 || len(firing.Updated) != 0 || len(firing.Resolved) != 0 {
+		t.Fatalf("firing: got opened %v, updated %v, resolved %v; want one opened", firing.Opened, firing.Updated, firing.Resolved)
+	}
+	if len(firing.Skipped) != 2 {
+		t.Fatalf("firing: got %d skipped alerts, want 2: %+v", len(firing.Skipped), firing.Skipped)
+	}
+	skipped := map[string]string{}
+	for _, alert := range firing.Skipped {
+		skipped[alert.AlertName] = alert.Reason
+	}
+	if reason := skipped["DiskAlmostFull"]; reason != "no registered service matches the alert labels" {
+		t.Errorf("DiskAlmostFull skipped with %q, want unknown service", reason)
+	}
+	if reason := skipped["Watchdog"]; reason != `severity "none" is not mapped` {
+		t.Errorf("Watchdog skipped with %q, want unmapped severity", reason)
+	}
+
+	incident, err := incidents.GetIncident(firing.Opened[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if incident.Title != "HighErrorRate: Error rate above 5%" {
+		t.Errorf("title = %q", incident.Title)
+	}
+	if incident.Severity != models.IncidentSeverityCritical || incident.Status != models.IncidentStatusOpen {
+		t.Errorf("severity %s, status %s; want critical, open", incident.Severity, incident.Status)
+	}
+	if incident.Source != models.IncidentSourceAlertmanager || incident.ExternalID != "4c2d1e7a9b8f6d51" {
+		t.Errorf("source %s, external ID %s", incident.Source, incident.ExternalID)
+	}
+	if want := time.Date(2024, 3, 12, 9, 14, 30, 123000000, time.UTC); !incident.StartedAt.Equal(want) {
+		t.Errorf("started at %s, want %s", incident.StartedAt, want)
+	}
+	if len(incident.Services) != 1 || incident.Services[0].ID != payments.ID {
+		t.Errorf("services = %+v, want only payment-service in prod", incident.Services)
+	}
+
+	escalated := handleWebhookFile(t, receiver, "escalated.json")
+	if len(escalated.Opened) != 0 || len(escalated.Updated) != 1 || escalated.Updated[0] != incident.ID {
+		t.Fatalf("escalated: got opened %v, updated %v; want incident %d updated", escalated.Opened, escalated.Updated, incident.ID)
+	}
+	if incident, _ = incidents.GetIncident(incident.ID); incident.Severity != models.IncidentSeverityMajor {
+		t.Errorf("severity after update = %s, want major", incident.Severity)
+	}
+
+	resolved := handleWebhookFile(t, receiver, "resolved.json")
+	if len(resolved.Resolved) != 1 || resolved.Resolved[0] != incident.ID {
+		t.Fatalf("resolved: got %v, want [%d]; an alert that never fired resolves nothing", resolved.Resolved, incident.ID)
+	}
+	incident, _ = incidents.GetIncident(incident.ID)
+	if incident.Status != models.IncidentStatusResolved || incident.ResolvedAt == nil {
+		t.Fatalf("status %s, resolved at %v; want resolved", incident.Status, incident.ResolvedAt)
+	}
+	if want := time.Date(2024, 3, 12, 9, 42, 0, 0, time.UTC); !incident.ResolvedAt.Equal(want) {
+		t.Errorf("resolved at %s, want the alert's endsAt %s", incident.ResolvedAt, want)
+	}
+
+	// The same alert firing again after it resolved is a new incident.
+	again := handleWebhookFile(t, receiver, "escalated.json")
+	if len(again.Opened) != 1 || again.Opened[0] == incident.ID {
+		t.Errorf("refiring: got opened %v, want a new incident", again.Opened)
+	}
+}
+
+func TestAlertmanagerServiceMatching(t *testing.T) {
+	receiver, _ := newTestAlertmanagerReceiver(t)
+	checkout := createService(t, receiver.db, "checkout", "prod")
+	createService(t, receiver.db, "checkout", "staging")
+
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   int // matched services, 0 for skipped
+	}{
+		{"service label wins over job", map[string]string{"service": "checkout", "job": "other", "environment": "prod"}, 1},
+		{"falls back to job", map[string]string{"job": "checkout", "environment": "prod"}, 1},
+		{"no environment matches every environment", map[string]string{"service": "checkout"}, 2},
+		{"unknown environment", map[string]string{"service": "checkout", "environment": "dev"}, 0},
+		{"unknown service", map[string]string{"service": "billing"}, 0},
+		{"no service labels", map[string]string{"alertname": "X"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			services, err := receiver.matchServices(tt.labels)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(services) != tt.want {
+				t.Fatalf("matched %d services, want %d", len(services), tt.want)
+			}
+			if tt.want == 1 && services[0].ID != checkout.ID {
+				t.Errorf("matched service %d, want %d", services[0].ID, checkout.ID)
+			}
+		})
+	}
+}
+
+func TestLabelsFingerprintIsStable(t *testing.T) {
+	a := labelsFingerprint(map[string]string{"alertname": "X", "job": "api"})
+	b := labelsFingerprint(map[string]string{"job": "api", "alertname": "X"})
+	if a != b {
+		t.Errorf("fingerprints differ by map order: %s, %s", a, b)
+	}
+	if c := labelsFingerprint(map[string]string{"alertname": "X", "job": "web"}); c == a {
+		t.Errorf("different labels share fingerprint %s", a)
+	}
+}
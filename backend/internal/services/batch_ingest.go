@@ -0,0 +1,376 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	maxIngestBatch = 10000
+	// How long idempotency keys are remembered.
+	idempotencyKeyTTL = 24 * time.Hour
+)
+
+// ErrIdempotencyKeyReused is returned when an idempotency key is sent again
+// with different samples.
+var ErrIdempotencyKeyReused = errors.New("idempotency key was already used for a different batch")
+
+// IngestSample is one sample of a batch ingest. The service and SLO are
+// addressed by ID or by name; an SLO name is looked up within the service.
+type IngestSample struct {
+	ServiceID   uint     `json:"service_id"`
+	Service     string   `json:"service"`
+	Environment string   `json:"environment"` // narrows a service name lookup
+	SLOID       uint     `json:"slo_id"`
+	SLO         string   `json:"slo"`
+	Timestamp   string   `json:"timestamp"` // RFC 3339
+	Value       *float64 `json:"value"`
+	MetricType  string   `json:"metric_type"` // "success", "total", "latency"
+	Series      string   `json:"series"`      // optional, when several series feed one SLO
+}
+
+type IngestSampleError struct {
+	Index int    `json:"index"`
+	Error string `json:"error"`
+}
+
+type IngestBatchResult struct {
+	Received   int                 `json:"received"`
+	Accepted   int                 `json:"accepted"`
+	Duplicates int                 `json:"duplicates"`
+	Late       int                 `json:"late"` // in or before a rolled-up minute, not stored
+	Rejected   int                 `json:"rejected"`
+	Errors     []IngestSampleError `json:"errors,omitempty"`
+}
+
+// DecodeIngestBatch splits a batch body into raw samples. JSON bodies may be
+// an array of samples or an object with a samples array; NDJSON bodies hold
+// one sample per line. Samples are decoded individually so one malformed
+// sample does not reject the batch.
+func DecodeIngestBatch(body []byte, ndjson bool) ([]json.RawMessage, error) {
+	var records []json.RawMessage
+	if ndjson {
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+		for scanner.Scan() {
+			line := bytes.TrimSpace(scanner.Bytes())
+			if len(line) == 0 {
+				continue
+			}
+			records = append(records, json.RawMessage(append([]byte(nil), line...)))
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+	} else {
+		trimmed := bytes.TrimSpace(body)
+		if len(trimmed) > 0 && trimmed[0] == '{' {
+			var wrapper struct {
+				Samples []json.RawMessage `json:"samples"`
+			}
+			if err := json.Unmarshal(trimmed, &wrapper); err != nil {
+				return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+			}
+			records = wrapper.Samples
+		} else if err := json.Unmarshal(trimmed, &records); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+	}
+
+	if len(records) > maxIngestBatch {
+		return nil, fmt.Errorf("%w: batch has %d samples, the limit is %d", ErrInvalidPayload, len(records), maxIngestBatch)
+	}
+	return records, nil
+}
+
+// IngestBatch validates and stores a batch of samples. Samples already stored
+// for the same SLO, timestamp, type and series are counted as duplicates.
+// When idempotencyKey is set and was seen before, the stored result of that
+// request is returned with replayed set instead, or ErrIdempotencyKeyReused
+// when the key was sent with different samples.
+func (ms *MetricsService) IngestBatch(idempotencyKey string, records []json.RawMessage) (*IngestBatchResult, bool, error) {
+	requestHash := hashIngestRecords(records)
+	if idempotencyKey != "" {
+		previous, previousHash, err := ms.findIngestRequest(idempotencyKey)
+		if err != nil {
+			return nil, false, err
+		}
+		if previous != nil && previousHash != "" && previousHash != requestHash {
+			return nil, false, ErrIdempotencyKeyReused
+		}
+		if previous != nil {
+			return previous, true, nil
+		}
+	}
+
+	result := &IngestBatchResult{Received: len(records)}
+	resolver := newIngestResolver(ms.db)
+	rows := make([]models.MetricIngest, 0, len(records))
+	for i, record := range records {
+		row, err := resolver.resolve(record)
+		if err != nil {
+			result.Rejected++
+			result.Errors = append(result.Errors, IngestSampleError{Index: i, Error: err.Error()})
+			continue
+		}
+		rows = append(rows, *row)
+	}
+
+	if len(rows) > 0 {
+		stored, late, err := insertMetricIngests(ms.db, rows)
+		if err != nil {
+			return nil, false, err
+		}
+		result.Accepted = stored
+		result.Late = late
+		result.Duplicates = len(rows) - stored - late
+	}
+
+	if idempotencyKey != "" {
+		if err := ms.saveIngestRequest(idempotencyKey, requestHash, result); err != nil {
+			return nil, false, err
+		}
+	}
+	return result, false, nil
+}
+
+// insertMetricIngests stores rows, skipping those that already exist, and
+// returns how many were inserted. The unique index only covers raw rows, so
+// rows falling in or before the newest rollup bucket of their SLO are skipped
+// as well, and counted as late: a sample resent after its minute was rolled
+// up would otherwise be counted twice.
+func insertMetricIngests(db *gorm.DB, rows []models.MetricIngest) (stored, late int, err error) {
+	watermarks := make(map[uint]time.Time)
+	fresh := make([]models.MetricIngest, 0, len(rows))
+	for _, row := range rows {
+		watermark, ok := watermarks[row.SLOID]
+		if !ok {
+			if watermark, err = rollupWatermark(db, row.SLOID); err != nil {
+				return 0, 0, err
+			}
+			watermarks[row.SLOID] = watermark
+		}
+		if row.Timestamp.Before(watermark) {
+			late++
+			continue
+		}
+		fresh = append(fresh, row)
+	}
+	if len(fresh) == 0 {
+		return 0, late, nil
+	}
+
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(fresh, ingestBatchSize)
+	return int(result.RowsAffected), late, result.Error
+}
+
+// hashIngestRecords identifies a batch by its samples, so the same samples
+// sent as a JSON array or as NDJSON hash alike.
+func hashIngestRecords(records []json.RawMessage) string {
+	hash := sha256.New()
+	for _, record := range records {
+		hash.Write(bytes.TrimSpace(record))
+		hash.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// findIngestRequest returns the stored result for an idempotency key and the
+// hash of the request that stored it.
+func (ms *MetricsService) findIngestRequest(key string) (*IngestBatchResult, string, error) {
+	var request models.IngestRequest
+	err := ms.db.Where("idempotency_key = ? AND created_at >= ?", key, time.Now().Add(-idempotencyKeyTTL)).
+		Limit(1).Find(&request).Error
+	if err != nil || request.ID == 0 {
+		return nil, "", err
+	}
+
+	var result IngestBatchResult
+	if err := json.Unmarshal([]byte(request.Response), &result); err != nil {
+		return nil, "", err
+	}
+	return &result, request.RequestHash, nil
+}
+
+func (ms *MetricsService) saveIngestRequest(key, requestHash string, result *IngestBatchResult) error {
+	response, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	// Expired keys are removed here rather than by a background job.
+	if err := ms.db.Where("created_at < ?", time.Now().Add(-idempotencyKeyTTL)).Delete(&models.IngestRequest{}).Error; err != nil {
+		return err
+	}
+	request := models.IngestRequest{IdempotencyKey: key, Response: string(response), RequestHash: requestHash}
+	return ms.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&request).Error
+}
+
+// ingestResolver validates samples and resolves service and SLO references,
+// caching lookups across the batch.
+type ingestResolver struct {
+	db       *gorm.DB
+	services map[string]*models.Service
+	slos     map[string]*models.SLO
+}
+
+func newIngestResolver(db *gorm.DB) *ingestResolver {
+	return &ingestResolver{
+		db:       db,
+		services: make(map[string]*models.Service),
+		slos:     make(map[string]*models.SLO),
+	}
+}
+
+func (r *ingestResolver) resolve(record json.RawMessage) (*models.MetricIngest, error) {
+	var sample IngestSample
+	if err := json.Unmarshal(record, &sample); err != nil {
+		return nil, fmt.Errorf("invalid sample: %v", err)
+	}
+
+	switch sample.MetricType {
+	case "success", "total", "latency":
+	case "":
+		return nil, errors.New("metric_type is required")
+	default:
+		return nil, fmt.Errorf("metric_type must be success, total or latency, got %q", sample.MetricType)
+	}
+	if sample.Value == nil {
+		return nil, errors.New("value is required")
+	}
+	if math.IsNaN(*sample.Value) || math.IsInf(*sample.Value, 0) || *sample.Value < 0 {
+		return nil, fmt.Errorf("value must be a finite non-negative number, got %v", *sample.Value)
+	}
+	if sample.Timestamp == "" {
+		return nil, errors.New("timestamp is required")
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, sample.Timestamp)
+	if err != nil {
+		return nil, errors.New("timestamp must be in RFC 3339 format")
+	}
+
+	slo, err := r.resolveSLO(&sample)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MetricIngest{
+		ServiceID:  slo.ServiceID,
+		SLOID:      slo.ID,
+		Timestamp:  timestamp,
+		Value:      *sample.Value,
+		MetricType: sample.MetricType,
+		Series:     sample.Series,
+	}, nil
+}
+
+func (r *ingestResolver) resolveSLO(sample *IngestSample) (*models.SLO, error) {
+	var service *models.Service
+	if sample.ServiceID != 0 || sample.Service != "" {
+		var err error
+		if service, err = r.resolveService(sample); err != nil {
+			return nil, err
+		}
+	}
+
+	if sample.SLOID != 0 {
+		slo, err := r.lookupSLO(fmt.Sprintf("id:%d", sample.SLOID), r.db.Where("id = ?", sample.SLOID))
+		if err != nil {
+			return nil, err
+		}
+		if slo == nil {
+			return nil, fmt.Errorf("SLO %d not found", sample.SLOID)
+		}
+		if service != nil && slo.ServiceID != service.ID {
+			return nil, fmt.Errorf("SLO %d does not belong to service %s", sample.SLOID, service.Name)
+		}
+		if sample.SLO != "" && slo.Name != sample.SLO {
+			return nil, fmt.Errorf("SLO %d is named %q, not %q", sample.SLOID, slo.Name, sample.SLO)
+		}
+		return slo, nil
+	}
+
+	if sample.SLO == "" {
+		return nil, errors.New("slo_id or slo is required")
+	}
+	if service == nil {
+		return nil, errors.New("service_id or service is required to look up an SLO by name")
+	}
+	slo, err := r.lookupSLO(fmt.Sprintf("name:%d:%s", service.ID, sample.SLO),
+		r.db.Where("service_id = ? AND name = ?", service.ID, sample.SLO))
+	if err != nil {
+		return nil, err
+	}
+	if slo == nil {
+		return nil, fmt.Errorf("SLO %q not found for service %s", sample.SLO, service.Name)
+	}
+	return slo, nil
+}
+
+func (r *ingestResolver) resolveService(sample *IngestSample) (*models.Service, error) {
+	key := fmt.Sprintf("id:%d", sample.ServiceID)
+	query := r.db.Where("id = ?", sample.ServiceID)
+	if sample.ServiceID == 0 {
+		key = "name:" + sample.Service + "/" + sample.Environment
+		query = r.db.Where("name = ?", sample.Service)
+		if sample.Environment != "" {
+			query = query.Where("environment = ?", sample.Environment)
+		}
+	}
+
+	service, cached := r.services[key]
+	if !cached {
+		var services []models.Service
+		if err := query.Limit(2).Find(&services).Error; err != nil {
+			return nil, err
+		}
+		switch len(services) {
+		case 0:
+		case 1:
+			service = &services[0]
+		default:
+			return nil, fmt.Errorf("service %q exists in several environments, set environment", sample.Service)
+		}
+		r.services[key] = service
+	}
+
+	if service == nil {
+		if sample.ServiceID != 0 {
+			return nil, fmt.Errorf("service %d not found", sample.ServiceID)
+		}
+		return nil, fmt.Errorf("service %q not found", sample.Service)
+	}
+	if sample.Service != "" && service.Name != sample.Service {
+		return nil, fmt.Errorf("service %d is named %q, not %q", service.ID, service.Name, sample.Service)
+	}
+	return service, nil
+}
+
+func (r *ingestResolver) lookupSLO(key string, query *gorm.DB) (*models.SLO, error) {
+	if slo, cached := r.slos[key]; cached {
+		return slo, nil
+	}
+
+	var slos []models.SLO
+	if err := query.Limit(1).Find(&slos).Error; err != nil {
+		return nil, err
+	}
+	var slo *models.SLO
+	if len(slos) > 0 {
+		slo = &slos[0]
+	}
+	r.slos[key] = slo
+	return slo, nil
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"slo-platform/internal/models"
+)
+
+func TestIngestBatch(t *testing.T) {
+	db := newTestDB(t)
+	service := createService(t, db, "checkout", "prod")
+	slo := &models.SLO{Name: "checkout availability", ServiceID: service.ID, SLIType: models.SLITypeAvailability,
+		Target: 0.99, TimeWindowDays: 30}
+	if err := db.Create(slo).Error; err != nil {
+		t.Fatal(err)
+	}
+	ms := NewMetricsService(db, "")
+
+	timestamp := time.Now().UTC().Add(-time.Minute).Format(time.RFC3339)
+	body := `{"service": "checkout", "slo": "checkout availability", "timestamp": "` + timestamp + `", "value": 1000, "metric_type": "total"}
+{"service": "checkout", "slo": "checkout availability", "timestamp": "` + timestamp + `", "value": 998,
+{"service": "checkout", "slo": "checkout availability", "timestamp": "` + timestamp + `", "value": 998, "metric_type": "success"}
+`
+	records, err := DecodeIngestBatch([]byte(body), true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, replayed, err := ms.IngestBatch("batch-1", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if replayed || result.Received != 3 || result.Accepted != 2 || result.Rejected != 1 {
+		t.Fatalf("got %+v (replayed %v), want 3 received, 2 accepted and 1 rejected", result, replayed)
+	}
+	if len(result.Errors) != 1 || result.Errors[0].Index != 1 {
+		t.Errorf("errors %+v, want the malformed line at index 1", result.Errors)
+	}
+
+	replay, replayed, err := ms.IngestBatch("batch-1", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !replayed || replay.Accepted != 2 || replay.Duplicates != 0 || replay.Rejected != 1 {
+		t.Errorf("replay got %+v (replayed %v), want the stored result", replay, replayed)
+	}
+	var stored int64
+	db.Model(&models.MetricIngest{}).Count(&stored)
+	if stored != 2 {
+		t.Errorf("%d samples stored after the replay, want 2", stored)
+	}
+
+	if _, _, err := ms.IngestBatch("batch-1", records[:1]); !errors.Is(err, ErrIdempotencyKeyReused) {
+		t.Errorf("key reused with different samples: got %v, want ErrIdempotencyKeyReused", err)
+	}
+	if _, replayed, err := ms.IngestBatch("batch-2", records); err != nil || replayed {
+		t.Errorf("same samples under a new key: replayed %v, err %v", replayed, err)
+	}
+}
+
+func TestIngestBatchCountsLateSamples(t *testing.T) {
+	db := newTestDB(t)
+	service := createService(t, db, "checkout", "prod")
+	slo := &models.SLO{Name: "checkout availability", ServiceID: service.ID, SLIType: models.SLITypeAvailability,
+		Target: 0.99, TimeWindowDays: 30}
+	if err := db.Create(slo).Error; err != nil {
+		t.Fatal(err)
+	}
+	rolledUp := time.Now().UTC().Add(-3 * time.Hour).Truncate(time.Minute)
+	rollup := models.MetricRollup{ServiceID: service.ID, SLOID: slo.ID, Resolution: minuteResolution,
+		Timestamp: rolledUp, MetricType: "total", Value: 100, Count: 1}
+	if err := db.Create(&rollup).Error; err != nil {
+		t.Fatal(err)
+	}
+	ms := NewMetricsService(db, "")
+
+	sample := func(timestamp time.Time) json.RawMessage {
+		return json.RawMessage(`{"service_id": 1, "slo_id": 1, "timestamp": "` + timestamp.Format(time.RFC3339) +
+			`", "value": 100, "metric_type": "total"}`)
+	}
+	recent := time.Now().UTC().Add(-time.Minute).Truncate(time.Second)
+	records := []json.RawMessage{sample(rolledUp), sample(rolledUp.Add(-time.Hour)), sample(recent), sample(recent)}
+	result, _, err := ms.IngestBatch("", records)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Accepted != 1 || result.Duplicates != 1 || result.Late != 2 {
+		t.Errorf("got %+v, want 1 accepted, 1 duplicate and 2 late", result)
+	}
+
+	if status, err := ms.IngestMetric(service.ID, slo.ID, rolledUp.Add(30*time.Second), 1, "total"); err != nil || status != IngestLate {
+		t.Errorf("sample in a rolled-up minute: %q, %v; want late", status, err)
+	}
+	if status, err := ms.IngestMetric(service.ID, slo.ID, recent, 100, "total"); err != nil || status != IngestDuplicate {
+		t.Errorf("resent sample: %q, %v; want duplicate", status, err)
+	}
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"slo-platform/internal/database"
+	"slo-platform/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newTestDB returns a migrated in-memory SQLite database.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := database.NewConnection("file::memory:")
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	db = db.Session(&gorm.Session{Logger: logger.Default.LogMode(logger.Silent)})
+	if err := database.Migrate(db); err != nil {
+		t.Fatalf("migrate: %v", err)
+	}
+	t.Cleanup(func() {
+		if conn, err := db.DB(); err == nil {
+			conn.Close()
+		}
+	})
+	return db
+}
+
+// createService registers a service directly, without validation.
+func createService(t *testing.T, db *gorm.DB, name, environment string) *models.Service {
+	t.Helper()
+	service := &models.Service{Name: name, OwnerTeam: "payments", Environment: environment}
+	if err := db.Create(service).Error; err != nil {
+		t.Fatalf("create service %s: %v", name, err)
+	}
+	return service
+}
+
+// readJSON decodes testdata/<name> into v.
+func readJSON(t *testing.T, name string, v interface{}) {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("decode %s: %v", name, err)
+	}
+}
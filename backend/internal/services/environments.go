@@ -0,0 +1,83 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"slo-platform/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnknownEnvironment is returned when a service or promotion names an
+// environment that is not registered.
+var ErrUnknownEnvironment = errors.New("unknown environment")
+
+// ErrEnvironmentExists is returned when an environment is registered twice.
+var ErrEnvironmentExists = errors.New("environment already exists")
+
+func (sr *ServiceRegistry) ListEnvironments() ([]models.Environment, error) {
+	var environments []models.Environment
+	err := sr.db.Order("rank, name").Find(&environments).Error
+	return environments, err
+}
+
+func (sr *ServiceRegistry) CreateEnvironment(environment *models.Environment) error {
+	environment.Name = strings.TrimSpace(environment.Name)
+
+	var count int64
+	if err := sr.db.Model(&models.Environment{}).Where("name = ?", environment.Name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s", ErrEnvironmentExists, environment.Name)
+	}
+
+	err := sr.db.Create(environment).Error
+	if isDuplicateKey(sr.db, err) {
+		// Registered concurrently since the check above.
+		return fmt.Errorf("%w: %s", ErrEnvironmentExists, environment.Name)
+	}
+	return err
+}
+
+// isDuplicateKey reports whether err is a unique constraint violation.
+func isDuplicateKey(db *gorm.DB, err error) bool {
+	if err == nil {
+		return false
+	}
+	if translator, ok := db.Dialector.(gorm.ErrorTranslator); ok {
+		err = translator.Translate(err)
+	}
+	return errors.Is(err, gorm.ErrDuplicatedKey)
+}
+
+// ListServiceEnvironments returns the service with the given ID and its
+// registrations in other environments, in promotion order.
+func (sr *ServiceRegistry) ListServiceEnvironments(id uint) ([]models.Service, error) {
+	var service models.Service
+	if err := sr.db.First(&service, id).Error; err != nil {
+		return nil, err
+	}
+
+	var services []models.Service
+	err := sr.db.Preload("SLOs").
+		Joins("LEFT JOIN environments ON environments.name = services.environment").
+		Where("services.name = ?", service.Name).
+		Order("environments.rank, services.environment").
+		Find(&services).Error
+	return services, err
+}
+
+// validateEnvironment checks that a service's environment is registered.
+func validateEnvironment(db *gorm.DB, name string) error {
+	var count int64
+	if err := db.Model(&models.Environment{}).Where("name = ?", name).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return fmt.Errorf("%w %q", ErrUnknownEnvironment, name)
+	}
+	return nil
+}
@@ -0,0 +1,30 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"slo-platform/internal/models"
+)
+
+func TestCreateEnvironmentRejectsDuplicates(t *testing.T) {
+	db := newTestDB(t)
+	registry := NewServiceRegistry(db)
+
+	if err := registry.CreateEnvironment(&models.Environment{Name: "perf", Rank: 15}); err != nil {
+		t.Fatal(err)
+	}
+	err := registry.CreateEnvironment(&models.Environment{Name: " perf ", Rank: 16})
+	if !errors.Is(err, ErrEnvironmentExists) {
+		t.Errorf("second create: got %v, want ErrEnvironmentExists", err)
+	}
+
+	// A concurrent registration only trips the unique index.
+	err = db.Create(&models.Environment{Name: "perf"}).Error
+	if !isDuplicateKey(db, err) {
+		t.Errorf("isDuplicateKey(%v) = false", err)
+	}
+	if isDuplicateKey(db, nil) {
+		t.Error("isDuplicateKey(nil) = true")
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EvaluationResult struct {
+	Evaluated int `json:"evaluated"`
+	Failed    int `json:"failed"`
+	Removed   int `json:"removed"` // snapshots of deleted SLOs
+	Changed   int `json:"changed"` // SLOs whose status changed
+}
+
+// SLOEvaluator periodically computes the status of every SLO and stores it
+// as an SLOSnapshot, so fleet-wide views such as the overview read one row
+// per SLO instead of querying Prometheus for each of them. Status changes
+// are published to the event stream.
+type SLOEvaluator struct {
+	db         *gorm.DB
+	sloService *SLOService
+	events     *EventHub
+	interval   time.Duration
+}
+
+func NewSLOEvaluator(db *gorm.DB, sloService *SLOService, events *EventHub, interval time.Duration) (*SLOEvaluator, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("evaluation interval must be positive, got %s", interval)
+	}
+	return &SLOEvaluator{db: db, sloService: sloService, events: events, interval: interval}, nil
+}
+
+// Start runs the evaluator in the background every interval.
+func (e *SLOEvaluator) Start() {
+	go func() {
+		ticker := time.NewTicker(e.interval)
+		defer ticker.Stop()
+		for {
+			result, err := e.Run(time.Now())
+			if err != nil {
+				zap.L().Error("SLO evaluation failed", zap.Error(err))
+			} else {
+				zap.L().Debug("SLO evaluation complete",
+					zap.Int("evaluated", result.Evaluated),
+					zap.Int("failed", result.Failed),
+					zap.Int("removed", result.Removed),
+					zap.Int("changed", result.Changed))
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// Run evaluates every SLO once, refreshing budget forecasts that are an hour
+// old, opens or resolves breach incidents and stores the snapshots. An SLO
+// that fails to evaluate keeps its previous snapshot. A status differing from
+// the previous snapshot, or a first evaluation, is published as a change.
+func (e *SLOEvaluator) Run(now time.Time) (*EvaluationResult, error) {
+	start := time.Now()
+	defer func() {
+		evaluationDuration.observe(time.Since(start).Seconds())
+	}()
+	result := &EvaluationResult{}
+
+	var slos []models.SLO
+	err := e.db.Preload("Service").
+		Joins("JOIN services ON services.id = slos.service_id AND services.deleted_at IS NULL").
+		Find(&slos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var previous []models.SLOSnapshot
+	if err := e.db.Select("slo_id", "status").Find(&previous).Error; err != nil {
+		return nil, err
+	}
+	previousStatus := make(map[uint]string, len(previous))
+	for _, snapshot := range previous {
+		previousStatus[snapshot.SLOID] = snapshot.Status
+	}
+
+	for _, slo := range slos {
+		e.sloService.refreshForecast(&slo, now)
+		status, err := e.sloService.CalculateSLOStatus(slo.ID)
+		if err != nil {
+			zap.L().Warn("Failed to evaluate SLO", zap.Uint("slo_id", slo.ID), zap.Error(err))
+			result.Failed++
+			continue
+		}
+		e.sloService.syncBreachIncident(&slo, status)
+		if err := e.store(snapshotOf(slo.ServiceID, status, now)); err != nil {
+			return nil, err
+		}
+		result.Evaluated++
+		if status.Status != previousStatus[slo.ID] {
+			e.events.Publish(models.EventSLOStatus, &slo.Service, status)
+			result.Changed++
+		}
+	}
+
+	removed := e.db.Where("slo_id NOT IN (?)",
+		e.db.Model(&models.SLO{}).Select("slos.id").
+			Joins("JOIN services ON services.id = slos.service_id AND services.deleted_at IS NULL")).
+		Delete(&models.SLOSnapshot{})
+	if removed.Error != nil {
+		return nil, removed.Error
+	}
+	result.Removed = int(removed.RowsAffected)
+
+	evaluatedSLOs.add(float64(result.Evaluated), "evaluated")
+	evaluatedSLOs.add(float64(result.Failed), "failed")
+	lastEvaluation.setValue(float64(time.Now().Unix()))
+	return result, nil
+}
+
+func (e *SLOEvaluator) store(snapshot *models.SLOSnapshot) error {
+	return e.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "slo_id"}},
+		UpdateAll: true,
+	}).Create(snapshot).Error
+}
+
+func snapshotOf(serviceID uint, status *models.SLOStatus, now time.Time) *models.SLOSnapshot {
+	return &models.SLOSnapshot{
+		SLOID:            status.SLOID,
+		ServiceID:        serviceID,
+		Status:           status.Status,
+		CurrentSLI:       status.CurrentSLI,
+		Target:           status.Target,
+		RemainingBudget:  status.RemainingBudget,
+		ConsumedBudget:   status.ConsumedBudget,
+		CurrentBurnRate:  status.CurrentBurnRate,
+		FastBurnRate:     status.FastBurnRate,
+		SlowBurnRate:     status.SlowBurnRate,
+		TimeToExhaustion: status.TimeToExhaustion,
+		EvaluatedAt:      now,
+	}
+}
@@ -0,0 +1,244 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	// MaxReplayEvents caps the events replayed to a resuming subscriber.
+	MaxReplayEvents = 1000
+	// subscriptionBuffer is how many live events a subscriber may fall
+	// behind before it is disconnected; it can then resume from its last ID.
+	subscriptionBuffer = 256
+)
+
+// ErrInvalidEventFilter is returned for filters naming unknown event types.
+var ErrInvalidEventFilter = errors.New("invalid event filter")
+
+var eventTypes = map[string]bool{
+	models.EventSLOStatus:   true,
+	models.EventAlert:       true,
+	models.EventDeployCheck: true,
+}
+
+// EventFilter selects stream events. Empty fields match everything.
+type EventFilter struct {
+	ServiceID   uint     `json:"service_id,omitempty"`
+	Service     string   `json:"service,omitempty"` // service name, across environments
+	OwnerTeam   string   `json:"owner_team,omitempty"`
+	Environment string   `json:"environment,omitempty"`
+	Types       []string `json:"types,omitempty"`
+}
+
+func (f EventFilter) Validate() error {
+	for _, eventType := range f.Types {
+		if !eventTypes[eventType] {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidEventFilter, eventType)
+		}
+	}
+	return nil
+}
+
+func (f EventFilter) matches(event *models.StreamEvent) bool {
+	if f.ServiceID != 0 && event.ServiceID != f.ServiceID {
+		return false
+	}
+	if f.Service != "" && event.ServiceName != f.Service {
+		return false
+	}
+	if f.OwnerTeam != "" && event.OwnerTeam != f.OwnerTeam {
+		return false
+	}
+	if f.Environment != "" && event.Environment != f.Environment {
+		return false
+	}
+	if len(f.Types) == 0 {
+		return true
+	}
+	for _, eventType := range f.Types {
+		if eventType == event.Type {
+			return true
+		}
+	}
+	return false
+}
+
+func (f EventFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.ServiceID != 0 {
+		query = query.Where("service_id = ?", f.ServiceID)
+	}
+	if f.Service != "" {
+		query = query.Where("service_name = ?", f.Service)
+	}
+	if f.OwnerTeam != "" {
+		query = query.Where("owner_team = ?", f.OwnerTeam)
+	}
+	if f.Environment != "" {
+		query = query.Where("environment = ?", f.Environment)
+	}
+	if len(f.Types) > 0 {
+		query = query.Where("type IN ?", f.Types)
+	}
+	return query
+}
+
+// EventHub records stream events and fans them out to subscribers in this
+// process. Events are stored before they are sent so a subscriber can
+// replay what it missed after reconnecting. A nil hub discards events.
+type EventHub struct {
+	db        *gorm.DB
+	retention time.Duration
+
+	mu          sync.Mutex
+	subscribers map[*Subscription]struct{}
+}
+
+func NewEventHub(db *gorm.DB, retention time.Duration) *EventHub {
+	if retention <= 0 {
+		retention = 24 * time.Hour
+	}
+	return &EventHub{
+		db:          db,
+		retention:   retention,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Start deletes events older than the retention in the background.
+func (h *EventHub) Start() {
+	go func() {
+		ticker := time.NewTicker(time.Hour)
+		defer ticker.Stop()
+		for {
+			result := h.db.Where("created_at < ?", time.Now().Add(-h.retention)).Delete(&models.StreamEvent{})
+			if result.Error != nil {
+				zap.L().Error("Failed to expire stream events", zap.Error(result.Error))
+			} else if result.RowsAffected > 0 {
+				zap.L().Debug("Expired stream events", zap.Int64("deleted", result.RowsAffected))
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// Publish stores an event of the given type about service and sends it to
+// matching subscribers. Failures are logged; publishing never fails the
+// operation that caused the event.
+func (h *EventHub) Publish(eventType string, service *models.Service, data interface{}) {
+	if h == nil {
+		return
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		zap.L().Error("Failed to encode stream event", zap.String("type", eventType), zap.Error(err))
+		return
+	}
+	event := &models.StreamEvent{
+		Type:        eventType,
+		ServiceID:   service.ID,
+		ServiceName: service.Name,
+		OwnerTeam:   service.OwnerTeam,
+		Environment: service.Environment,
+		Payload:     string(payload),
+		Data:        payload,
+	}
+
+	// Stored before it is sent, so a subscriber registered before the
+	// fan-out receives it live and one registered after it finds it when
+	// replaying. Concurrent publishes may fan out out of ID order.
+	if err := h.db.Create(event).Error; err != nil {
+		zap.L().Error("Failed to store stream event", zap.String("type", eventType), zap.Error(err))
+		return
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for sub := range h.subscribers {
+		if !sub.Filter().matches(event) {
+			continue
+		}
+		select {
+		case sub.events <- *event:
+		default:
+			// Too far behind: drop the subscriber so it resumes from the
+			// store instead of silently missing events.
+			delete(h.subscribers, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// Subscribe registers a subscriber for events matching filter. Call
+// Replay after subscribing to fetch stored events the subscriber missed, and
+// Close when done.
+func (h *EventHub) Subscribe(filter EventFilter) *Subscription {
+	sub := &Subscription{
+		hub:    h,
+		events: make(chan models.StreamEvent, subscriptionBuffer),
+		filter: filter,
+	}
+	h.mu.Lock()
+	h.subscribers[sub] = struct{}{}
+	h.mu.Unlock()
+	return sub
+}
+
+// Subscription is one subscriber's view of the stream.
+type Subscription struct {
+	hub    *EventHub
+	events chan models.StreamEvent
+
+	mu     sync.Mutex
+	filter EventFilter
+}
+
+// Events delivers live events, in the order they were published; events
+// published concurrently may arrive out of ID order. It is closed when the
+// subscription is closed or falls too far behind.
+func (s *Subscription) Events() <-chan models.StreamEvent {
+	return s.events
+}
+
+func (s *Subscription) Filter() EventFilter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.filter
+}
+
+// SetFilter changes which live events are delivered.
+func (s *Subscription) SetFilter(filter EventFilter) {
+	s.mu.Lock()
+	s.filter = filter
+	s.mu.Unlock()
+}
+
+// Replay returns stored events after the given ID that match the filter,
+// oldest first, up to MaxReplayEvents. Live events already replayed may
+// also arrive on Events; skip IDs at or below the last one replayed.
+func (s *Subscription) Replay(afterID uint) ([]models.StreamEvent, error) {
+	var events []models.StreamEvent
+	err := s.Filter().apply(s.hub.db.Where("id > ?", afterID)).
+		Order("id").Limit(MaxReplayEvents).Find(&events).Error
+	for i := range events {
+		events[i].Data = json.RawMessage(events[i].Payload)
+	}
+	return events, err
+}
+
+func (s *Subscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	if _, ok := s.hub.subscribers[s]; ok {
+		delete(s.hub.subscribers, s)
+		close(s.events)
+	}
+}
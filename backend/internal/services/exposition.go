@@ -0,0 +1,238 @@
+package services
+
+import (
+	"bufio"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// A small writer for the Prometheus text exposition format (version 0.0.4),
+// enough for the counters, gauges and histograms the platform exports about
+// itself on /metrics.
+
+type sample struct {
+	suffix string   // appended to the family name, e.g. "_bucket"
+	labels []string // name, value pairs
+	value  float64
+}
+
+type metricFamily struct {
+	name    string
+	help    string
+	kind    string // "counter", "gauge" or "histogram"
+	samples []sample
+}
+
+func writeFamilies(w io.Writer, families []metricFamily) error {
+	out := bufio.NewWriter(w)
+	for _, family := range families {
+		if len(family.samples) == 0 {
+			continue
+		}
+		out.WriteString("# HELP " + family.name + " " + escapeHelp(family.help) + "\n")
+		out.WriteString("# TYPE " + family.name + " " + family.kind + "\n")
+		for _, s := range family.samples {
+			out.WriteString(family.name + s.suffix)
+			if len(s.labels) > 0 {
+				out.WriteByte('{')
+				for i := 0; i+1 < len(s.labels); i += 2 {
+					if i > 0 {
+						out.WriteByte(',')
+					}
+					out.WriteString(s.labels[i] + `="` + escapeLabelValue(s.labels[i+1]) + `"`)
+				}
+				out.WriteByte('}')
+			}
+			out.WriteString(" " + formatSampleValue(s.value) + "\n")
+		}
+	}
+	return out.Flush()
+}
+
+var (
+	helpEscaper       = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelValueEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(help string) string {
+	return helpEscaper.Replace(help)
+}
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
+
+func formatSampleValue(value float64) string {
+	switch {
+	case math.IsInf(value, 1):
+		return "+Inf"
+	case math.IsInf(value, -1):
+		return "-Inf"
+	case math.IsNaN(value):
+		return "NaN"
+	default:
+		return strconv.FormatFloat(value, 'g', -1, 64)
+	}
+}
+
+// pairLabels zips label names with values into name, value pairs.
+func pairLabels(names, values []string) []string {
+	labels := make([]string, 0, 2*len(names))
+	for i, name := range names {
+		labels = append(labels, name, values[i])
+	}
+	return labels
+}
+
+// counterVec is a counter with one series per combination of label values.
+type counterVec struct {
+	name   string
+	help   string
+	labels []string
+
+	mu     sync.Mutex
+	series map[string]*counterSeries
+}
+
+type counterSeries struct {
+	labelValues []string
+	value       float64
+}
+
+func newCounterVec(name, help string, labels ...string) *counterVec {
+	return &counterVec{name: name, help: help, labels: labels, series: make(map[string]*counterSeries)}
+}
+
+func (c *counterVec) add(delta float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	series, ok := c.series[key]
+	if !ok {
+		series = &counterSeries{labelValues: labelValues}
+		c.series[key] = series
+	}
+	series.value += delta
+}
+
+func (c *counterVec) inc(labelValues ...string) {
+	c.add(1, labelValues...)
+}
+
+func (c *counterVec) family() metricFamily {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	family := metricFamily{name: c.name, help: c.help, kind: "counter"}
+	for _, key := range sortedKeys(c.series) {
+		series := c.series[key]
+		family.samples = append(family.samples, sample{
+			labels: pairLabels(c.labels, series.labelValues),
+			value:  series.value,
+		})
+	}
+	return family
+}
+
+// histogramVec is a histogram with one series per combination of label
+// values.
+type histogramVec struct {
+	name    string
+	help    string
+	labels  []string
+	buckets []float64 // upper bounds, ascending
+
+	mu     sync.Mutex
+	series map[string]*histogramSeries
+}
+
+type histogramSeries struct {
+	labelValues []string
+	counts      []uint64 // per bucket, not cumulative
+	sum         float64
+	count       uint64
+}
+
+func newHistogramVec(name, help string, buckets []float64, labels ...string) *histogramVec {
+	return &histogramVec{name: name, help: help, labels: labels, buckets: buckets, series: make(map[string]*histogramSeries)}
+}
+
+func (h *histogramVec) observe(value float64, labelValues ...string) {
+	key := strings.Join(labelValues, "\xff")
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	series, ok := h.series[key]
+	if !ok {
+		series = &histogramSeries{labelValues: labelValues, counts: make([]uint64, len(h.buckets))}
+		h.series[key] = series
+	}
+	if i := sort.SearchFloat64s(h.buckets, value); i < len(h.buckets) {
+		series.counts[i]++
+	}
+	series.sum += value
+	series.count++
+}
+
+func (h *histogramVec) family() metricFamily {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	family := metricFamily{name: h.name, help: h.help, kind: "histogram"}
+	for _, key := range sortedKeys(h.series) {
+		series := h.series[key]
+		labels := pairLabels(h.labels, series.labelValues)
+		var cumulative uint64
+		for i, upper := range h.buckets {
+			cumulative += series.counts[i]
+			family.samples = append(family.samples, sample{
+				suffix: "_bucket",
+				labels: append(append([]string{}, labels...), "le", formatSampleValue(upper)),
+				value:  float64(cumulative),
+			})
+		}
+		family.samples = append(family.samples,
+			sample{suffix: "_bucket", labels: append(append([]string{}, labels...), "le", "+Inf"), value: float64(series.count)},
+			sample{suffix: "_sum", labels: labels, value: series.sum},
+			sample{suffix: "_count", labels: labels, value: float64(series.count)},
+		)
+	}
+	return family
+}
+
+// gaugeValue is a gauge without labels.
+type gaugeValue struct {
+	name string
+	help string
+
+	mu    sync.Mutex
+	value float64
+	set   bool
+}
+
+func (g *gaugeValue) setValue(value float64) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.value, g.set = value, true
+}
+
+// family is empty until the gauge is first set.
+func (g *gaugeValue) family() metricFamily {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	family := metricFamily{name: g.name, help: g.help, kind: "gauge"}
+	if g.set {
+		family.samples = []sample{{value: g.value}}
+	}
+	return family
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
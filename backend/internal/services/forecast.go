@@ -0,0 +1,347 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"go.uber.org/zap"
+)
+
+const (
+	forecastStep     = time.Hour
+	forecastLookback = 7 * 24 * time.Hour // minimum history for hour-of-week seasonality
+	ewmaHalfLife     = 6.0                // hours
+	forecastZ        = 1.645              // 90% interval
+	hoursPerWeek     = 7 * 24
+)
+
+// ForecastExhaustion projects when the SLO's error budget over its rolling
+// compliance window will run out, based on its SLI history. The result
+// replaces the SLO's cached forecast.
+func (s *SLOService) ForecastExhaustion(sloID uint) (*models.BudgetForecast, error) {
+	slo, err := s.GetSLO(sloID)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	forecast, err := s.forecast(slo, now)
+	s.forecasts.store(slo, forecast, now)
+	return forecast, err
+}
+
+// refreshForecast recomputes the SLO's cached forecast once it is a step old
+// or the SLO has changed since. The evaluator calls it before each status
+// calculation, which then reads the cached forecast.
+func (s *SLOService) refreshForecast(slo *models.SLO, now time.Time) {
+	if s.forecasts.fresh(slo, now, forecastStep) {
+		return
+	}
+	forecast, err := s.forecast(slo, now)
+	if err != nil {
+		zap.L().Debug("No budget forecast", zap.Uint("slo_id", slo.ID), zap.Error(err))
+	}
+	s.forecasts.store(slo, forecast, now)
+}
+
+func (s *SLOService) forecast(slo *models.SLO, now time.Time) (*models.BudgetForecast, error) {
+	historyStart := now.Add(-complianceWindow(slo))
+	if lookback := now.Add(-forecastLookback); lookback.Before(historyStart) {
+		historyStart = lookback
+	}
+
+	history, err := s.metricsService.GetSLIHistory(slo, historyStart, now, forecastStep)
+	if err != nil {
+		return nil, err
+	}
+	return forecastBudget(slo, history, now)
+}
+
+func complianceWindow(slo *models.SLO) time.Duration {
+	return time.Duration(slo.TimeWindowDays) * 24 * time.Hour
+}
+
+// forecastCache holds the latest forecast of each SLO, so that reading a
+// status does not fetch a week of hourly history. A nil forecast records
+// that there was not enough history.
+type forecastCache struct {
+	mu      sync.Mutex
+	entries map[uint]cachedForecast
+}
+
+type cachedForecast struct {
+	forecast   *models.BudgetForecast
+	sloUpdated time.Time
+	at         time.Time
+}
+
+func newForecastCache() *forecastCache {
+	return &forecastCache{entries: make(map[uint]cachedForecast)}
+}
+
+func (c *forecastCache) store(slo *models.SLO, forecast *models.BudgetForecast, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[slo.ID] = cachedForecast{forecast: forecast, sloUpdated: slo.UpdatedAt, at: now}
+}
+
+// get returns the SLO's forecast if one was made in the last maxAge and the
+// SLO has not changed since.
+func (c *forecastCache) get(slo *models.SLO, now time.Time, maxAge time.Duration) (*models.BudgetForecast, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[slo.ID]
+	if !ok || !entry.sloUpdated.Equal(slo.UpdatedAt) || now.Sub(entry.at) >= maxAge {
+		return nil, false
+	}
+	return entry.forecast, true
+}
+
+func (c *forecastCache) fresh(slo *models.SLO, now time.Time, maxAge time.Duration) bool {
+	_, ok := c.get(slo, now, maxAge)
+	return ok
+}
+
+func (c *forecastCache) remove(sloID uint) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, sloID)
+}
+
+// forecastBudget projects the budget over the same rolling window that the
+// status measures the SLI over. Looking ahead one window length, events
+// older than the window at each step drop out of it, freeing their share of
+// the budget, while projected events are added.
+func forecastBudget(slo *models.SLO, history []models.SLISample, now time.Time) (*models.BudgetForecast, error) {
+	var observed []models.SLISample
+	for _, sample := range history {
+		if sample.Total > 0 {
+			observed = append(observed, sample)
+		}
+	}
+	if len(observed) < 2 {
+		return nil, fmt.Errorf("not enough SLI history to forecast")
+	}
+
+	window := complianceWindow(slo)
+	windowStart, horizon := now.Add(-window), now.Add(window)
+	profile := buildTrafficProfile(observed)
+
+	var inWindow []models.SLISample
+	var windowTotal, windowBad float64
+	for _, sample := range observed {
+		if sample.Timestamp.Before(windowStart) {
+			continue
+		}
+		inWindow = append(inWindow, sample)
+		windowTotal += sample.Total
+		windowBad += sample.Total - sample.Good
+	}
+
+	var projectedTraffic float64
+	for t := now; t.Before(horizon); t = t.Add(forecastStep) {
+		projectedTraffic += profile[hourOfWeek(t)]
+	}
+
+	forecast := &models.BudgetForecast{
+		SLOID:            slo.ID,
+		WindowStart:      windowStart,
+		WindowEnd:        horizon,
+		ProjectedTraffic: projectedTraffic,
+		DataPoints:       len(observed),
+		GeneratedAt:      now,
+	}
+	if allowedBad := (1.0 - slo.Target) * windowTotal; allowedBad > 0 {
+		forecast.RemainingBudget = (allowedBad - windowBad) / allowedBad
+	}
+
+	project := func(ratioAt func(hour int) float64, sd float64) models.ForecastModelResult {
+		return projectExhaustion(now, window, slo.Target, inWindow, profile, ratioAt, sd)
+	}
+
+	ewmaMean, ewmaSD := ewmaRatio(observed)
+	ewma := project(func(int) float64 { return ewmaMean }, ewmaSD)
+	ewma.Model = models.ForecastModelEWMA
+	ewma.ErrorRatio = ewmaMean
+
+	origin := observed[0].Timestamp
+	intercept, slope, linearSD := linearRatio(observed, origin)
+	offset := now.Sub(origin).Hours()
+	linear := project(func(h int) float64 {
+		return intercept + slope*(offset+float64(h))
+	}, linearSD)
+	linear.Model = models.ForecastModelLinear
+	linear.ErrorRatio = clampRatio(intercept + slope*offset)
+
+	forecast.Models = []models.ForecastModelResult{ewma, linear}
+	forecast.ExpectedExhaustion = ewma.ExpectedExhaustion
+	forecast.OptimisticExhaustion = laterTime(ewma.OptimisticExhaustion, linear.OptimisticExhaustion)
+	forecast.PessimisticExhaustion = earlierTime(ewma.PessimisticExhaustion, linear.PessimisticExhaustion)
+	forecast.WillNotExhaust = forecast.ExpectedExhaustion == nil
+
+	return forecast, nil
+}
+
+// projectExhaustion walks forward hour by hour for one window length. At
+// each step the rolling window holds the observed samples that have not yet
+// aged out plus the projected events (ratio × seasonal traffic); the budget
+// is exhausted once the window's bad events reach its allowance. The
+// variance of the projected bad events widens the estimate.
+func projectExhaustion(now time.Time, window time.Duration, target float64, observed []models.SLISample, profile [hoursPerWeek]float64, ratioAt func(hour int) float64, sd float64) models.ForecastModelResult {
+	var result models.ForecastModelResult
+
+	var observedTotal, observedBad float64
+	for _, sample := range observed {
+		observedTotal += sample.Total
+		observedBad += sample.Total - sample.Good
+	}
+	if observedBad >= (1.0-target)*observedTotal {
+		exhausted := now
+		result.ExpectedExhaustion = &exhausted
+		result.OptimisticExhaustion = &exhausted
+		result.PessimisticExhaustion = &exhausted
+		return result
+	}
+
+	var projectedTotal, projectedBad, variance float64
+	expired, hour := 0, 0
+	end := now.Add(window)
+	for t := now; t.Before(end); t = t.Add(forecastStep) {
+		traffic := profile[hourOfWeek(t)]
+		projectedTotal += traffic
+		projectedBad += clampRatio(ratioAt(hour)) * traffic
+		variance += (sd * traffic) * (sd * traffic)
+		deviation := forecastZ * math.Sqrt(variance)
+		hour++
+
+		at := t.Add(forecastStep)
+		if at.After(end) {
+			at = end
+		}
+		for expired < len(observed) && observed[expired].Timestamp.Before(at.Add(-window)) {
+			observedTotal -= observed[expired].Total
+			observedBad -= observed[expired].Total - observed[expired].Good
+			expired++
+		}
+		remainingBad := (1.0-target)*(observedTotal+projectedTotal) - observedBad
+
+		if result.PessimisticExhaustion == nil && projectedBad+deviation >= remainingBad {
+			result.PessimisticExhaustion = &at
+		}
+		if result.ExpectedExhaustion == nil && projectedBad >= remainingBad {
+			result.ExpectedExhaustion = &at
+		}
+		if result.OptimisticExhaustion == nil && projectedBad-deviation >= remainingBad {
+			result.OptimisticExhaustion = &at
+			break
+		}
+	}
+	return result
+}
+
+// buildTrafficProfile averages event counts per hour-of-week. Hours with no
+// history fall back to the overall average.
+func buildTrafficProfile(history []models.SLISample) [hoursPerWeek]float64 {
+	var sums, counts [hoursPerWeek]float64
+	var total float64
+	for _, sample := range history {
+		h := hourOfWeek(sample.Timestamp)
+		sums[h] += sample.Total
+		counts[h]++
+		total += sample.Total
+	}
+	overall := total / float64(len(history))
+
+	var profile [hoursPerWeek]float64
+	for h := range profile {
+		if counts[h] > 0 {
+			profile[h] = sums[h] / counts[h]
+		} else {
+			profile[h] = overall
+		}
+	}
+	return profile
+}
+
+// ewmaRatio returns the exponentially weighted mean and standard deviation
+// of the hourly error ratio.
+func ewmaRatio(history []models.SLISample) (float64, float64) {
+	alpha := 1 - math.Pow(2, -1/ewmaHalfLife)
+
+	mean := errorRatio(history[0])
+	var variance float64
+	for _, sample := range history[1:] {
+		diff := errorRatio(sample) - mean
+		mean += alpha * diff
+		variance = (1 - alpha) * (variance + alpha*diff*diff)
+	}
+	return mean, math.Sqrt(variance)
+}
+
+// linearRatio fits error ratio = intercept + slope × hours since origin using
+// traffic-weighted least squares, returning the residual standard deviation.
+func linearRatio(history []models.SLISample, origin time.Time) (float64, float64, float64) {
+	var sw, sx, sy, sxx, sxy float64
+	for _, sample := range history {
+		w := sample.Total
+		x := sample.Timestamp.Sub(origin).Hours()
+		y := errorRatio(sample)
+		sw += w
+		sx += w * x
+		sy += w * y
+		sxx += w * x * x
+		sxy += w * x * y
+	}
+
+	var intercept, slope float64
+	if denom := sw*sxx - sx*sx; denom != 0 {
+		slope = (sw*sxy - sx*sy) / denom
+		intercept = (sy - slope*sx) / sw
+	} else {
+		intercept = sy / sw
+	}
+
+	var residual float64
+	for _, sample := range history {
+		x := sample.Timestamp.Sub(origin).Hours()
+		diff := errorRatio(sample) - (intercept + slope*x)
+		residual += sample.Total * diff * diff
+	}
+	return intercept, slope, math.Sqrt(residual / sw)
+}
+
+func errorRatio(sample models.SLISample) float64 {
+	return clampRatio((sample.Total - sample.Good) / sample.Total)
+}
+
+func clampRatio(ratio float64) float64 {
+	return math.Max(0, math.Min(1, ratio))
+}
+
+func hourOfWeek(t time.Time) int {
+	t = t.UTC()
+	return int(t.Weekday())*24 + t.Hour()
+}
+
+func earlierTime(a, b *time.Time) *time.Time {
+	if a == nil {
+		return b
+	}
+	if b == nil || a.Before(*b) {
+		return a
+	}
+	return b
+}
+
+func laterTime(a, b *time.Time) *time.Time {
+	if a == nil || b == nil {
+		return nil
+	}
+	if a.After(*b) {
+		return a
+	}
+	return b
+}
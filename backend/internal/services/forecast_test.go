@@ -0,0 +1,100 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// hourlyHistory returns one sample per hour from now-hours to now, with the
+// error ratio given by ratioAt(hours ago).
+func hourlyHistory(now time.Time, hours int, ratioAt func(ago int) float64) []models.SLISample {
+	var history []models.SLISample
+	for ago := hours; ago > 0; ago-- {
+		history = append(history, models.SLISample{
+			Timestamp: now.Add(-time.Duration(ago) * time.Hour),
+			Good:      100 * (1 - ratioAt(ago)),
+			Total:     100,
+		})
+	}
+	return history
+}
+
+func TestForecastBudget(t *testing.T) {
+	now := time.Date(2024, 3, 12, 12, 0, 0, 0, time.UTC)
+	slo := &models.SLO{ID: 1, Target: 0.99, TimeWindowDays: 7}
+
+	tests := []struct {
+		name     string
+		ratioAt  func(ago int) float64
+		minHours float64 // expected exhaustion, hours from now; -1 for none
+		maxHours float64
+	}{
+		{"steady under target", func(int) float64 { return 0.005 }, -1, -1},
+		{"already spent", func(int) float64 { return 0.02 }, 0, 0},
+		{"recent burn", func(ago int) float64 {
+			if ago <= 24 {
+				return 0.02
+			}
+			return 0.005
+		}, 24, 48},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forecast, err := forecastBudget(slo, hourlyHistory(now, 8*24, tt.ratioAt), now)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !forecast.WindowStart.Equal(now.Add(-7*24*time.Hour)) || !forecast.WindowEnd.Equal(now.Add(7*24*time.Hour)) {
+				t.Errorf("window %s to %s, want the rolling window ending now and one window ahead", forecast.WindowStart, forecast.WindowEnd)
+			}
+			if tt.minHours < 0 {
+				if !forecast.WillNotExhaust {
+					t.Errorf("exhausts at %s, want never", forecast.ExpectedExhaustion)
+				}
+				return
+			}
+			if forecast.ExpectedExhaustion == nil {
+				t.Fatal("will not exhaust, want exhaustion")
+			}
+			if hours := forecast.ExpectedExhaustion.Sub(now).Hours(); hours < tt.minHours || hours > tt.maxHours {
+				t.Errorf("exhausts in %.0fh, want %.0fh to %.0fh", hours, tt.minHours, tt.maxHours)
+			}
+		})
+	}
+}
+
+func TestForecastCache(t *testing.T) {
+	now := time.Now()
+	slo := &models.SLO{ID: 1, UpdatedAt: now.Add(-time.Hour)}
+	cache := newForecastCache()
+	forecast := &models.BudgetForecast{SLOID: 1}
+	cache.store(slo, forecast, now)
+
+	if got, ok := cache.get(slo, now.Add(30*time.Minute), forecastStep); !ok || got != forecast {
+		t.Errorf("get = %v, %v; want the stored forecast", got, ok)
+	}
+	if _, ok := cache.get(slo, now.Add(forecastStep), forecastStep); ok {
+		t.Error("forecast older than maxAge returned")
+	}
+	edited := *slo
+	edited.UpdatedAt = now
+	if _, ok := cache.get(&edited, now, forecastStep); ok {
+		t.Error("forecast returned after the SLO changed")
+	}
+	cache.remove(1)
+	if _, ok := cache.get(slo, now, forecastStep); ok {
+		t.Error("forecast returned after remove")
+	}
+}
+
+func TestForecastExhaustionUnknownSLO(t *testing.T) {
+	slos := NewSLOService(newTestDB(t), nil, nil, nil)
+	if _, err := slos.ForecastExhaustion(42); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("got %v, want gorm.ErrRecordNotFound", err)
+	}
+}
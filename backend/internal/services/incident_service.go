@@ -0,0 +1,313 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrUnknownService is returned when an incident names a service that does
+// not exist.
+var ErrUnknownService = errors.New("unknown service")
+
+// recentIncidentWindow is how long a resolved incident still counts as
+// "recent" for deploy checks.
+const recentIncidentWindow = 24 * time.Hour
+
+type IncidentService struct {
+	db *gorm.DB
+}
+
+func NewIncidentService(db *gorm.DB) *IncidentService {
+	return &IncidentService{db: db}
+}
+
+type IncidentFilter struct {
+	Status    models.IncidentStatus
+	ServiceID uint
+}
+
+func (is *IncidentService) CreateIncident(incident *models.Incident, serviceIDs []uint) error {
+	if incident.Status == "" {
+		incident.Status = models.IncidentStatusOpen
+	}
+	if incident.Source == "" {
+		incident.Source = models.IncidentSourceManual
+	}
+	if incident.StartedAt.IsZero() {
+		incident.StartedAt = time.Now()
+	}
+
+	services, err := is.loadServices(serviceIDs)
+	if err != nil {
+		return err
+	}
+	incident.Services = services
+
+	return is.db.Create(incident).Error
+}
+
+func (is *IncidentService) GetIncident(id uint) (*models.Incident, error) {
+	var incident models.Incident
+	err := is.db.Preload("Services").First(&incident, id).Error
+	return &incident, err
+}
+
+func (is *IncidentService) ListIncidents(filter IncidentFilter) ([]models.Incident, error) {
+	var incidents []models.Incident
+	query := is.db.Preload("Services").Order("started_at DESC")
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.ServiceID != 0 {
+		query = query.Where("id IN (?)", is.db.Table("incident_services").Select("incident_id").Where("service_id = ?", filter.ServiceID))
+	}
+	err := query.Find(&incidents).Error
+	return incidents, err
+}
+
+// UpdateIncident saves the incident. When serviceIDs is non-nil the set of
+// affected services is replaced as well.
+func (is *IncidentService) UpdateIncident(incident *models.Incident, serviceIDs []uint) error {
+	if incident.Status == models.IncidentStatusResolved && incident.ResolvedAt == nil {
+		now := time.Now()
+		incident.ResolvedAt = &now
+	}
+
+	var services []models.Service
+	if serviceIDs != nil {
+		var err error
+		if services, err = is.loadServices(serviceIDs); err != nil {
+			return err
+		}
+	}
+
+	return is.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Services").Save(incident).Error; err != nil {
+			return err
+		}
+		if serviceIDs == nil {
+			return nil
+		}
+		incident.Services = services
+		return tx.Model(incident).Association("Services").Replace(services)
+	})
+}
+
+func (is *IncidentService) ResolveIncident(id uint) (*models.Incident, error) {
+	incident, err := is.GetIncident(id)
+	if err != nil {
+		return nil, err
+	}
+	if !incident.IsActive() {
+		return incident, nil
+	}
+
+	now := time.Now()
+	incident.Status = models.IncidentStatusResolved
+	incident.ResolvedAt = &now
+	if err := is.db.Omit("Services").Save(incident).Error; err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+func (is *IncidentService) DeleteIncident(id uint) error {
+	return is.db.Delete(&models.Incident{}, id).Error
+}
+
+// GetRecentIncidents returns incidents affecting the service that are still
+// active or were resolved after since.
+func (is *IncidentService) GetRecentIncidents(serviceID uint, since time.Time) ([]models.Incident, error) {
+	var incidents []models.Incident
+	err := is.db.
+		Joins("JOIN incident_services ON incident_services.incident_id = incidents.id").
+		Where("incident_services.service_id = ?", serviceID).
+		Where("incidents.status <> ? OR incidents.resolved_at >= ?", models.IncidentStatusResolved, since).
+		Order("incidents.started_at DESC").
+		Find(&incidents).Error
+	return incidents, err
+}
+
+// LastSLOBreach returns the start of the most recent SLO breach incident for
+// the service, or nil if it never breached.
+func (is *IncidentService) LastSLOBreach(serviceID uint) (*time.Time, error) {
+	var incident models.Incident
+	err := is.db.
+		Joins("JOIN incident_services ON incident_services.incident_id = incidents.id").
+		Where("incident_services.service_id = ? AND incidents.source = ?", serviceID, models.IncidentSourceSLOBreach).
+		Order("incidents.started_at DESC").
+		Limit(1).
+		Find(&incident).Error
+	if err != nil || incident.ID == 0 {
+		return nil, err
+	}
+	return &incident.StartedAt, nil
+}
+
+// OpenSLOBreachIncident opens an incident for a breached SLO unless one is
+// already active for it.
+func (is *IncidentService) OpenSLOBreachIncident(slo *models.SLO, status *models.SLOStatus) (*models.Incident, error) {
+	var existing models.Incident
+	err := is.db.
+		Where("slo_id = ? AND source = ? AND status <> ?", slo.ID, models.IncidentSourceSLOBreach, models.IncidentStatusResolved).
+		Limit(1).
+		Find(&existing).Error
+	if err != nil {
+		return nil, err
+	}
+	if existing.ID != 0 {
+		return &existing, nil
+	}
+
+	severity := models.IncidentSeverityMajor
+	if slo.HardBudgetPolicy {
+		severity = models.IncidentSeverityCritical
+	}
+
+	sloID := slo.ID
+	incident := &models.Incident{
+		Title: fmt.Sprintf("SLO breached: %s / %s", slo.Service.Name, slo.Name),
+		Description: fmt.Sprintf("Current SLI %.4f is below target %.4f (%.1f%% budget remaining)",
+			status.CurrentSLI, status.Target, status.RemainingBudget),
+		Severity:  severity,
+		Status:    models.IncidentStatusOpen,
+		Source:    models.IncidentSourceSLOBreach,
+		SLOID:     &sloID,
+		StartedAt: status.LastUpdated,
+	}
+	if err := is.CreateIncident(incident, []uint{slo.ServiceID}); err != nil {
+		return nil, err
+	}
+	return incident, nil
+}
+
+// ResolveSLOBreachIncidents resolves any automatically opened incidents for an
+// SLO that has recovered.
+func (is *IncidentService) ResolveSLOBreachIncidents(sloID uint) error {
+	return is.db.Model(&models.Incident{}).
+		Where("slo_id = ? AND source = ? AND status <> ?", sloID, models.IncidentSourceSLOBreach, models.IncidentStatusResolved).
+		Updates(map[string]interface{}{
+			"status":      models.IncidentStatusResolved,
+			"resolved_at": time.Now(),
+		}).Error
+}
+
+// CalculateBudgetImpact computes how much of each affected SLO's error budget
+// was consumed while the incident was active. Ingested success/total samples
+// are used when available; otherwise the incident is treated as a full outage
+// and the impact is its share of the compliance window.
+func (is *IncidentService) CalculateBudgetImpact(incident *models.Incident) ([]models.IncidentBudgetImpact, error) {
+	serviceIDs := make([]uint, 0, len(incident.Services))
+	for _, service := range incident.Services {
+		serviceIDs = append(serviceIDs, service.ID)
+	}
+	if len(serviceIDs) == 0 {
+		return nil, nil
+	}
+
+	var slos []models.SLO
+	if err := is.db.Preload("Service").Where("service_id IN ?", serviceIDs).Find(&slos).Error; err != nil {
+		return nil, err
+	}
+
+	end := time.Now()
+	if incident.ResolvedAt != nil {
+		end = *incident.ResolvedAt
+	}
+
+	impacts := make([]models.IncidentBudgetImpact, 0, len(slos))
+	for _, slo := range slos {
+		impact, err := is.budgetImpact(&slo, incident.StartedAt, end)
+		if err != nil {
+			return nil, err
+		}
+		impacts = append(impacts, *impact)
+	}
+	return impacts, nil
+}
+
+func (is *IncidentService) budgetImpact(slo *models.SLO, start, end time.Time) (*models.IncidentBudgetImpact, error) {
+	totalBudget := 1.0 - slo.Target
+	window := time.Duration(slo.TimeWindowDays) * 24 * time.Hour
+
+	impact := &models.IncidentBudgetImpact{
+		SLOID:       slo.ID,
+		SLOName:     slo.Name,
+		ServiceName: slo.Service.Name,
+	}
+
+	incidentSuccess, err := is.sumIngested(slo.ID, "success", start, end)
+	if err != nil {
+		return nil, err
+	}
+	incidentTotal, err := is.sumIngested(slo.ID, "total", start, end)
+	if err != nil {
+		return nil, err
+	}
+	windowTotal, err := is.sumIngested(slo.ID, "total", end.Add(-window), end)
+	if err != nil {
+		return nil, err
+	}
+
+	if incidentTotal > 0 && windowTotal > 0 {
+		impact.Method = "events"
+		impact.ConsumedBudget = (incidentTotal - incidentSuccess) / windowTotal
+	} else {
+		impact.Method = "duration"
+		if window > 0 {
+			impact.ConsumedBudget = end.Sub(start).Seconds() / window.Seconds()
+		}
+	}
+
+	if totalBudget > 0 {
+		impact.ConsumedPercent = impact.ConsumedBudget / totalBudget * 100
+	}
+	return impact, nil
+}
+
+func (is *IncidentService) sumIngested(sloID uint, metricType string, start, end time.Time) (float64, error) {
+	sums, err := ingestedSums(is.db, sloID, []string{metricType}, start, end)
+	if err != nil {
+		return 0, err
+	}
+	return sums[metricType].Sum, nil
+}
+
+// loadServices loads the services with the given IDs, ignoring repeats.
+func (is *IncidentService) loadServices(serviceIDs []uint) ([]models.Service, error) {
+	if len(serviceIDs) == 0 {
+		return nil, nil
+	}
+
+	unique := make([]uint, 0, len(serviceIDs))
+	seen := make(map[uint]bool, len(serviceIDs))
+	for _, id := range serviceIDs {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+
+	var services []models.Service
+	if err := is.db.Where("id IN ?", unique).Find(&services).Error; err != nil {
+		return nil, err
+	}
+	if len(services) == len(unique) {
+		return services, nil
+	}
+	for _, service := range services {
+		delete(seen, service.ID)
+	}
+	missing := make([]uint, 0, len(seen))
+	for _, id := range unique {
+		if seen[id] {
+			missing = append(missing, id)
+		}
+	}
+	return nil, fmt.Errorf("%w: %v", ErrUnknownService, missing)
+}
@@ -0,0 +1,101 @@
+package services
+
+import (
+	"time"
+
+	"slo-platform/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// Ingested data lives in three tiers: raw MetricIngest samples and 1m and 1h
+// MetricRollup buckets. The helpers below read all tiers so callers see the
+// same totals before and after the retention manager downsamples them.
+
+type ingestedSum struct {
+	MetricType string
+	Count      int64
+	Sum        float64
+}
+
+// ingestedSums returns the sample count and value sum per metric type for an
+// SLO in [start, end). A zero end leaves the range open.
+func ingestedSums(db *gorm.DB, sloID uint, metricTypes []string, start, end time.Time) (map[string]ingestedSum, error) {
+	sums := make(map[string]ingestedSum)
+
+	var raw []ingestedSum
+	err := timeRange(db.Model(&models.MetricIngest{}), start, end).
+		Select("metric_type, COUNT(*) AS count, COALESCE(SUM(value), 0) AS sum").
+		Where("slo_id = ? AND metric_type IN ?", sloID, metricTypes).
+		Group("metric_type").
+		Scan(&raw).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var rolled []ingestedSum
+	err = timeRange(db.Model(&models.MetricRollup{}), start, end).
+		Select("metric_type, COALESCE(SUM(count), 0) AS count, COALESCE(SUM(value), 0) AS sum").
+		Where("slo_id = ? AND metric_type IN ?", sloID, metricTypes).
+		Group("metric_type").
+		Scan(&rolled).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range append(raw, rolled...) {
+		sum := sums[row.MetricType]
+		sum.MetricType = row.MetricType
+		sum.Count += row.Count
+		sum.Sum += row.Sum
+		sums[row.MetricType] = sum
+	}
+	return sums, nil
+}
+
+// ingestedLatency returns how many latency samples of an SLO in [start, end)
+// were within threshold, and how many there were in total.
+func ingestedLatency(db *gorm.DB, sloID uint, threshold float64, start, end time.Time) (float64, float64, error) {
+	raw := timeRange(db.Model(&models.MetricIngest{}), start, end).
+		Where("slo_id = ? AND metric_type = ?", sloID, "latency")
+
+	var total, good int64
+	if err := raw.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := raw.Session(&gorm.Session{}).Where("value <= ?", threshold).Count(&good).Error; err != nil {
+		return 0, 0, err
+	}
+
+	var rolled struct {
+		Count int64
+		Good  float64
+	}
+	err := timeRange(db.Model(&models.MetricRollup{}), start, end).
+		Select("COALESCE(SUM(count), 0) AS count, COALESCE(SUM(good), 0) AS good").
+		Where("slo_id = ? AND metric_type = ?", sloID, "latency").
+		Scan(&rolled).Error
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return float64(good) + rolled.Good, float64(total + rolled.Count), nil
+}
+
+// ingestedRollups returns the rollup buckets of an SLO in [start, end).
+func ingestedRollups(db *gorm.DB, sloID uint, metricTypes []string, start, end time.Time) ([]models.MetricRollup, error) {
+	var rollups []models.MetricRollup
+	err := timeRange(db, start, end).
+		Where("slo_id = ? AND metric_type IN ?", sloID, metricTypes).
+		Order("timestamp").
+		Find(&rollups).Error
+	return rollups, err
+}
+
+func timeRange(query *gorm.DB, start, end time.Time) *gorm.DB {
+	query = query.Where("timestamp >= ?", start)
+	if !end.IsZero() {
+		query = query.Where("timestamp < ?", end)
+	}
+	return query
+}
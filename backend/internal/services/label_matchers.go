@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// labelMatcher is one PromQL-style label matcher: name="value", name!="value",
+// name=~"regex" or name!~"regex".
+type labelMatcher struct {
+	name  string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func (m labelMatcher) matches(labels map[string]string) bool {
+	value := labels[m.name]
+	switch m.op {
+	case "!=":
+		return value != m.value
+	case "=~":
+		return m.re.MatchString(value)
+	case "!~":
+		return !m.re.MatchString(value)
+	default:
+		return value == m.value
+	}
+}
+
+func matchAll(matchers []labelMatcher, labels map[string]string) bool {
+	for _, matcher := range matchers {
+		if !matcher.matches(labels) {
+			return false
+		}
+	}
+	return true
+}
+
+var matcherPattern = regexp.MustCompile(`^\s*([a-zA-Z_][a-zA-Z0-9_]*)\s*(=~|!~|!=|=)\s*("(?:[^"\\]|\\.)*")\s*(?:,|$)`)
+
+// parseLabelMatchers parses a selector such as {job="api",handler=~"/v1/.*"}.
+// The braces are optional and regular expressions are fully anchored, as in
+// PromQL.
+func parseLabelMatchers(selector string) ([]labelMatcher, error) {
+	rest := strings.TrimSpace(selector)
+	if strings.HasPrefix(rest, "{") != strings.HasSuffix(rest, "}") {
+		return nil, fmt.Errorf("unbalanced braces in %q", selector)
+	}
+	rest = strings.TrimSuffix(strings.TrimPrefix(rest, "{"), "}")
+
+	var matchers []labelMatcher
+	for strings.TrimSpace(rest) != "" {
+		match := matcherPattern.FindStringSubmatch(rest)
+		if match == nil {
+			return nil, fmt.Errorf("invalid label matcher near %q", strings.TrimSpace(rest))
+		}
+		value, err := strconv.Unquote(match[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid label value %s: %w", match[3], err)
+		}
+
+		matcher := labelMatcher{name: match[1], op: match[2], value: value}
+		if matcher.op == "=~" || matcher.op == "!~" {
+			matcher.re, err = regexp.Compile("^(?:" + value + ")$")
+			if err != nil {
+				return nil, fmt.Errorf("invalid regular expression for %s: %w", matcher.name, err)
+			}
+		}
+		matchers = append(matchers, matcher)
+		rest = rest[len(match[0]):]
+	}
+	return matchers, nil
+}
@@ -0,0 +1,179 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"github.com/prometheus/common/model"
+)
+
+const defaultLatencyMetric = "http_request_duration_seconds"
+
+type histogramBucket struct {
+	le    float64
+	count float64
+}
+
+// calculateLatency returns the fraction of requests faster than the SLO's
+// LatencyThreshold, read from a classic or native Prometheus histogram.
+func (ms *MetricsService) calculateLatency(ctx context.Context, slo *models.SLO) (*models.SLIMeasurement, error) {
+	if slo.PrometheusQuery != "" {
+		value, err := ms.queryCustomMetric(ctx, slo)
+		if err != nil {
+			return nil, err
+		}
+		return &models.SLIMeasurement{SLOID: slo.ID, Value: value, Source: models.SLISourcePrometheus, Method: "query"}, nil
+	}
+
+	if slo.LatencyThreshold <= 0 {
+		return nil, fmt.Errorf("latency SLO requires a latency_threshold")
+	}
+
+	metric := slo.LatencyMetric
+	if metric == "" {
+		metric = defaultLatencyMetric
+	}
+
+	if slo.LatencyMode == models.LatencyModeNative {
+		return ms.nativeHistogramLatency(ctx, slo, metric)
+	}
+	return ms.classicHistogramLatency(ctx, slo, metric)
+}
+
+func (ms *MetricsService) classicHistogramLatency(ctx context.Context, slo *models.SLO, metric string) (*models.SLIMeasurement, error) {
+	query := fmt.Sprintf(`sum by (le) (rate(%s_bucket{service="%s"}[5m]))`, metric, slo.Service.Name)
+	result, warnings, err := ms.prometheusAPI.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("prometheus query failed: %w", err)
+	}
+
+	recordWarnings(ctx, query, warnings)
+
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unsupported result type: %s", result.Type())
+	}
+
+	buckets := make([]histogramBucket, 0, len(vector))
+	for _, sample := range vector {
+		le, err := strconv.ParseFloat(string(sample.Metric["le"]), 64)
+		if err != nil {
+			continue
+		}
+		buckets = append(buckets, histogramBucket{le: le, count: float64(sample.Value)})
+	}
+	if len(buckets) == 0 {
+		return nil, ErrNoData
+	}
+
+	measurement := latencyFromBuckets(buckets, slo.LatencyThreshold, slo.LatencyMode)
+	measurement.SLOID = slo.ID
+	measurement.Source = models.SLISourcePrometheus
+	return measurement, nil
+}
+
+// latencyFromBuckets computes the good-event ratio at threshold from
+// cumulative histogram buckets. When the threshold falls between bucket
+// boundaries the count is either linearly interpolated or taken from the
+// nearest boundary, and the boundaries used are reported.
+func latencyFromBuckets(buckets []histogramBucket, threshold float64, mode models.LatencyMode) *models.SLIMeasurement {
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].le < buckets[j].le })
+	total := buckets[len(buckets)-1].count
+
+	measurement := &models.SLIMeasurement{Total: total}
+
+	upper := sort.Search(len(buckets), func(i int) bool { return buckets[i].le >= threshold })
+	lower := histogramBucket{} // implicit bucket at 0
+	if upper > 0 {
+		lower = buckets[upper-1]
+	}
+
+	switch {
+	case upper < len(buckets) && buckets[upper].le == threshold:
+		measurement.Method = "exact_bucket"
+		measurement.Good = buckets[upper].count
+		measurement.Buckets = []float64{buckets[upper].le}
+	case upper == len(buckets) || math.IsInf(buckets[upper].le, 1):
+		// Threshold beyond the last finite bucket: only the lower bound is known.
+		measurement.Method = "nearest_bucket"
+		measurement.Good = lower.count
+		measurement.Buckets = []float64{lower.le}
+	case mode == models.LatencyModeNearestBucket:
+		nearest := buckets[upper]
+		if threshold-lower.le <= nearest.le-threshold {
+			nearest = lower
+		}
+		measurement.Method = "nearest_bucket"
+		measurement.Good = nearest.count
+		measurement.Buckets = []float64{nearest.le}
+	default:
+		next := buckets[upper]
+		fraction := (threshold - lower.le) / (next.le - lower.le)
+		measurement.Method = "interpolated"
+		measurement.Good = lower.count + (next.count-lower.count)*fraction
+		measurement.Buckets = []float64{lower.le, next.le}
+	}
+
+	measurement.Value = 1.0 // 100% if no traffic
+	if total > 0 {
+		measurement.Value = measurement.Good / total
+	}
+	return measurement
+}
+
+func (ms *MetricsService) nativeHistogramLatency(ctx context.Context, slo *models.SLO, metric string) (*models.SLIMeasurement, error) {
+	selector := fmt.Sprintf(`sum(rate(%s{service="%s"}[5m]))`, metric, slo.Service.Name)
+
+	total, err := ms.queryPrometheus(ctx, fmt.Sprintf(`histogram_count(%s)`, selector), models.SeriesAggregationSum)
+	if err != nil {
+		return nil, err
+	}
+
+	measurement := &models.SLIMeasurement{
+		SLOID:  slo.ID,
+		Value:  1.0, // 100% if no traffic
+		Total:  total,
+		Source: models.SLISourcePrometheus,
+		Method: "native_histogram",
+	}
+	if total == 0 {
+		return measurement, nil
+	}
+
+	fraction, err := ms.queryPrometheus(ctx, fmt.Sprintf(`histogram_fraction(0, %g, %s)`, slo.LatencyThreshold, selector), slo.SeriesAggregation)
+	if err != nil {
+		return nil, err
+	}
+	measurement.Value = fraction
+	measurement.Good = fraction * total
+	return measurement, nil
+}
+
+// measureIngestedLatency computes the latency SLI from ingested per-request
+// latency samples over the SLO window. It returns nil when there are none.
+func (ms *MetricsService) measureIngestedLatency(slo *models.SLO) (*models.SLIMeasurement, error) {
+	if slo.LatencyThreshold <= 0 {
+		return nil, nil
+	}
+
+	since := time.Now().AddDate(0, 0, -slo.TimeWindowDays)
+	good, total, err := ingestedLatency(ms.db, slo.ID, slo.LatencyThreshold, since, time.Time{})
+	if err != nil || total == 0 {
+		return nil, err
+	}
+
+	return &models.SLIMeasurement{
+		SLOID:  slo.ID,
+		Value:  good / total,
+		Good:   good,
+		Total:  total,
+		Source: models.SLISourceIngested,
+		Method: "samples",
+	}, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"slo-platform/internal/models"
+)
+
+func TestLatencyFromBuckets(t *testing.T) {
+	// 1000 requests: 600 under 100ms, 900 under 250ms, 980 under 500ms.
+	buckets := func() []histogramBucket {
+		return []histogramBucket{
+			{le: math.Inf(1), count: 1000},
+			{le: 0.25, count: 900},
+			{le: 0.1, count: 600},
+			{le: 0.5, count: 980},
+		}
+	}
+	tests := []struct {
+		name      string
+		threshold float64
+		mode      models.LatencyMode
+		method    string
+		good      float64
+		bounds    []float64
+	}{
+		{"on a boundary", 0.25, models.LatencyModeInterpolate, "exact_bucket", 900, []float64{0.25}},
+		{"interpolated", 0.175, models.LatencyModeInterpolate, "interpolated", 750, []float64{0.1, 0.25}},
+		{"interpolated by default", 0.3, "", "interpolated", 916, []float64{0.25, 0.5}},
+		{"interpolated below the first bucket", 0.05, models.LatencyModeInterpolate, "interpolated", 300, []float64{0, 0.1}},
+		{"nearest lower bucket", 0.15, models.LatencyModeNearestBucket, "nearest_bucket", 600, []float64{0.1}},
+		{"nearest upper bucket", 0.2, models.LatencyModeNearestBucket, "nearest_bucket", 900, []float64{0.25}},
+		{"above the top finite bucket", 2, models.LatencyModeInterpolate, "nearest_bucket", 980, []float64{0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := latencyFromBuckets(buckets(), tt.threshold, tt.mode)
+			if got.Method != tt.method {
+				t.Errorf("method %s, want %s", got.Method, tt.method)
+			}
+			if math.Abs(got.Good-tt.good) > 1e-9 || got.Total != 1000 {
+				t.Errorf("good %v of %v, want %v of 1000", got.Good, got.Total, tt.good)
+			}
+			if math.Abs(got.Value-tt.good/1000) > 1e-9 {
+				t.Errorf("value %v, want %v", got.Value, tt.good/1000)
+			}
+			if !reflect.DeepEqual(got.Buckets, tt.bounds) {
+				t.Errorf("buckets %v, want %v", got.Buckets, tt.bounds)
+			}
+		})
+	}
+}
+
+func TestLatencyFromBucketsWithoutTraffic(t *testing.T) {
+	got := latencyFromBuckets([]histogramBucket{{le: 0.1}, {le: math.Inf(1)}}, 0.1, models.LatencyModeInterpolate)
+	if got.Value != 1 {
+		t.Errorf("value %v with no requests, want 1", got.Value)
+	}
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"fmt"
+
+	"slo-platform/internal/models"
+)
+
+// SLOStatusSource computes SLO statuses for list status filters.
+type SLOStatusSource interface {
+	CalculateSLOStatus(sloID uint) (*models.SLOStatus, error)
+}
+
+var sloStatuses = map[string]bool{"healthy": true, "degraded": true, "breached": true, "no_data": true}
+
+type ServiceFilter struct {
+	OwnerTeam   string
+	Environment string
+	NamePrefix  string
+	Status      string // services with at least one SLO in this status
+}
+
+var serviceSorts = map[string]sortField{
+	"id":          {"id", sortNumber},
+	"name":        {"name", sortString},
+	"owner_team":  {"owner_team", sortString},
+	"environment": {"environment", sortString},
+	"created_at":  {"created_at", sortTime},
+	"updated_at":  {"updated_at", sortTime},
+}
+
+// ListServicesPage returns a page of services matching filter. SLOs are only
+// loaded when withSLOs is set; statuses is needed for a status filter.
+func (sr *ServiceRegistry) ListServicesPage(filter ServiceFilter, page PageRequest, withSLOs bool, statuses SLOStatusSource) ([]models.Service, *PageInfo, error) {
+	query := sr.db.Model(&models.Service{})
+	if filter.OwnerTeam != "" {
+		query = query.Where("services.owner_team = ?", filter.OwnerTeam)
+	}
+	if filter.Environment != "" {
+		query = query.Where("services.environment = ?", filter.Environment)
+	}
+	if filter.NamePrefix != "" {
+		query = query.Where(`services.name LIKE ? ESCAPE '\'`, prefixPattern(filter.NamePrefix))
+	}
+
+	pq := pageQuery{query: query, table: "services", sorts: serviceSorts, defaultSort: "name"}
+	if withSLOs {
+		pq.preloads = []string{"SLOs"}
+	}
+	if filter.Status != "" {
+		if !sloStatuses[filter.Status] {
+			return nil, nil, fmt.Errorf("%w: unknown status %q", ErrInvalidPage, filter.Status)
+		}
+		pq.keep = func(row interface{}) (bool, error) {
+			var sloIDs []uint
+			err := sr.db.Model(&models.SLO{}).Where("service_id = ?", row.(*models.Service).ID).Pluck("id", &sloIDs).Error
+			if err != nil {
+				return false, err
+			}
+			for _, id := range sloIDs {
+				if status, err := statuses.CalculateSLOStatus(id); err == nil && status.Status == filter.Status {
+					return true, nil
+				}
+			}
+			return false, nil
+		}
+	}
+
+	var services []models.Service
+	info, err := paginate(pq, page, &services)
+	return services, info, err
+}
+
+type SLOFilter struct {
+	ServiceID   uint
+	OwnerTeam   string
+	Environment string
+	SLIType     models.SLIType
+	NamePrefix  string
+	Status      string
+}
+
+var sloSorts = map[string]sortField{
+	"id":         {"id", sortNumber},
+	"name":       {"name", sortString},
+	"service_id": {"service_id", sortNumber},
+	"sli_type":   {"sli_type", sortString},
+	"target":     {"target", sortNumber},
+	"created_at": {"created_at", sortTime},
+	"updated_at": {"updated_at", sortTime},
+}
+
+// ListSLOsPage returns a page of SLOs of live services matching filter, with
+// their service loaded.
+func (s *SLOService) ListSLOsPage(filter SLOFilter, page PageRequest) ([]models.SLO, *PageInfo, error) {
+	query := s.db.Model(&models.SLO{}).
+		Joins("JOIN services ON services.id = slos.service_id AND services.deleted_at IS NULL")
+	if filter.ServiceID != 0 {
+		query = query.Where("slos.service_id = ?", filter.ServiceID)
+	}
+	if filter.OwnerTeam != "" {
+		query = query.Where("services.owner_team = ?", filter.OwnerTeam)
+	}
+	if filter.Environment != "" {
+		query = query.Where("services.environment = ?", filter.Environment)
+	}
+	if filter.SLIType != "" {
+		query = query.Where("slos.sli_type = ?", filter.SLIType)
+	}
+	if filter.NamePrefix != "" {
+		query = query.Where(`slos.name LIKE ? ESCAPE '\'`, prefixPattern(filter.NamePrefix))
+	}
+
+	pq := pageQuery{query: query, table: "slos", preloads: []string{"Service"}, sorts: sloSorts, defaultSort: "name"}
+	if filter.Status != "" {
+		if !sloStatuses[filter.Status] {
+			return nil, nil, fmt.Errorf("%w: unknown status %q", ErrInvalidPage, filter.Status)
+		}
+		pq.keep = func(row interface{}) (bool, error) {
+			status, err := s.CalculateSLOStatus(row.(*models.SLO).ID)
+			return err == nil && status.Status == filter.Status, nil
+		}
+	}
+
+	var slos []models.SLO
+	info, err := paginate(pq, page, &slos)
+	return slos, info, err
+}
@@ -2,8 +2,10 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"net/http"
+	"math"
+	"sort"
 	"time"
 
 	"slo-platform/internal/models"
@@ -20,7 +22,13 @@ type MetricsService struct {
 	prometheusAPI  v1.API
 }
 
+// NewMetricsService queries Prometheus at prometheusURL. With an empty URL
+// the service runs on ingested and mock data only.
 func NewMetricsService(db *gorm.DB, prometheusURL string) *MetricsService {
+	if prometheusURL == "" {
+		return &MetricsService{db: db}
+	}
+
 	client, err := api.NewClient(api.Config{
 		Address: prometheusURL,
 	})
@@ -31,7 +39,7 @@ func NewMetricsService(db *gorm.DB, prometheusURL string) *MetricsService {
 
 	var promAPI v1.API
 	if client != nil {
-		promAPI = v1.NewAPI(client)
+		promAPI = instrumentedAPI{v1.NewAPI(client)}
 	}
 
 	return &MetricsService{
@@ -41,7 +49,15 @@ func NewMetricsService(db *gorm.DB, prometheusURL string) *MetricsService {
 	}
 }
 
-func (ms *MetricsService) IngestMetric(serviceID, sloID uint, timestamp time.Time, value float64, metricType string) error {
+// Outcomes of IngestMetric.
+const (
+	IngestAccepted  = "accepted"
+	IngestDuplicate = "duplicate" // the same sample was already stored
+	IngestLate      = "late"      // the sample's minute was already rolled up
+)
+
+// IngestMetric stores one sample and reports whether it was accepted.
+func (ms *MetricsService) IngestMetric(serviceID, sloID uint, timestamp time.Time, value float64, metricType string) (string, error) {
 	metric := models.MetricIngest{
 		ServiceID:  serviceID,
 		SLOID:      sloID,
@@ -49,29 +65,98 @@ func (ms *MetricsService) IngestMetric(serviceID, sloID uint, timestamp time.Tim
 		Value:      value,
 		MetricType: metricType,
 	}
-	return ms.db.Create(&metric).Error
+	stored, late, err := insertMetricIngests(ms.db, []models.MetricIngest{metric})
+	switch {
+	case err != nil:
+		return "", err
+	case stored > 0:
+		return IngestAccepted, nil
+	case late > 0:
+		return IngestLate, nil
+	default:
+		return IngestDuplicate, nil
+	}
 }
 
 func (ms *MetricsService) GetCurrentSLI(slo *models.SLO) (float64, error) {
+	measurement, err := ms.MeasureSLI(slo)
+	if err != nil {
+		return 0, err
+	}
+	if measurement.NoData {
+		return 0, ErrNoData
+	}
+	return measurement.Value, nil
+}
+
+// MeasureSLI computes the current SLI along with how it was obtained. When
+// the queries return no series the measurement is marked NoData instead of
+// failing; Prometheus warnings are returned with it.
+func (ms *MetricsService) MeasureSLI(slo *models.SLO) (*models.SLIMeasurement, error) {
+	if slo.SLIType == models.SLITypeLatency {
+		measurement, err := ms.measureIngestedLatency(slo)
+		if err != nil || measurement != nil {
+			return measurement, err
+		}
+	}
+	if !isWindowBased(slo) {
+		measurement, err := ms.measureIngestedEvents(slo)
+		if err != nil || measurement != nil {
+			return measurement, err
+		}
+	}
+
 	if ms.prometheusAPI == nil {
-		return ms.getMockSLI(slo), nil
+		return &models.SLIMeasurement{SLOID: slo.ID, Value: ms.getMockSLI(slo), Source: models.SLISourceMock}, nil
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	timeout := 10 * time.Second
+	if isWindowBased(slo) || usesRangeQueries(slo) {
+		timeout = 30 * time.Second // several range queries over the whole window
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
+	ctx, warnings := collectWarnings(ctx)
+
+	measurement, err := ms.measurePrometheusSLI(ctx, slo)
+	if errors.Is(err, ErrNoData) {
+		measurement, err = &models.SLIMeasurement{SLOID: slo.ID, Source: models.SLISourcePrometheus, NoData: true}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	measurement.Warnings = warnings.list()
+	return measurement, nil
+}
 
+func (ms *MetricsService) measurePrometheusSLI(ctx context.Context, slo *models.SLO) (*models.SLIMeasurement, error) {
+	if isWindowBased(slo) {
+		return ms.measureWindowSLI(ctx, slo)
+	}
+	if usesRangeQueries(slo) {
+		return ms.measureRangeSLI(ctx, slo)
+	}
+
+	var value float64
+	var err error
 	switch slo.SLIType {
 	case models.SLITypeAvailability:
-		return ms.calculateAvailability(ctx, slo)
+		value, err = ms.calculateAvailability(ctx, slo)
 	case models.SLITypeLatency:
 		return ms.calculateLatency(ctx, slo)
 	case models.SLITypeErrorRate:
-		return ms.calculateErrorRate(ctx, slo)
+		value, err = ms.calculateErrorRate(ctx, slo)
 	case models.SLITypeCustom:
-		return ms.queryCustomMetric(ctx, slo)
+		value, err = ms.queryCustomMetric(ctx, slo)
+	case models.SLITypeCorrectness, models.SLITypeDurability:
+		value, err = ms.calculateEventRatio(ctx, slo)
 	default:
-		return 0.0, fmt.Errorf("unsupported SLI type: %s", slo.SLIType)
+		err = fmt.Errorf("unsupported SLI type: %s", slo.SLIType)
 	}
+	if err != nil {
+		return nil, err
+	}
+	return &models.SLIMeasurement{SLOID: slo.ID, Value: value, Source: models.SLISourcePrometheus}, nil
 }
 
 func (ms *MetricsService) calculateAvailability(ctx context.Context, slo *models.SLO) (float64, error) {
@@ -83,12 +168,12 @@ func (ms *MetricsService) calculateAvailability(ctx context.Context, slo *models
 	successQuery := fmt.Sprintf(`sum(rate(http_requests_total{service="%s",status!~"5.."}[5m]))`, slo.Service.Name)
 	totalQuery := fmt.Sprintf(`sum(rate(http_requests_total{service="%s"}[5m]))`, slo.Service.Name)
 
-	successValue, err := ms.queryPrometheus(ctx, successQuery)
+	successValue, err := ms.queryPrometheus(ctx, successQuery, models.SeriesAggregationSum)
 	if err != nil {
 		return 0, err
 	}
 
-	totalValue, err := ms.queryPrometheus(ctx, totalQuery)
+	totalValue, err := ms.queryPrometheus(ctx, totalQuery, models.SeriesAggregationSum)
 	if err != nil {
 		return 0, err
 	}
@@ -100,58 +185,68 @@ func (ms *MetricsService) calculateAvailability(ctx context.Context, slo *models
 	return successValue / totalValue, nil
 }
 
-func (ms *MetricsService) calculateLatency(ctx context.Context, slo *models.SLO) (float64, error) {
+func (ms *MetricsService) calculateErrorRate(ctx context.Context, slo *models.SLO) (float64, error) {
 	if slo.PrometheusQuery != "" {
 		return ms.queryCustomMetric(ctx, slo)
 	}
 
-	// Default latency calculation (p95)
-	query := fmt.Sprintf(
-		`histogram_quantile(0.95, sum(rate(http_request_duration_seconds_bucket{service="%s"}[5m])) by (le))`,
-		slo.Service.Name,
-	)
+	// Default error rate calculation
+	errorQuery := fmt.Sprintf(`sum(rate(http_requests_total{service="%s",status=~"5.."}[5m]))`, slo.Service.Name)
+	totalQuery := fmt.Sprintf(`sum(rate(http_requests_total{service="%s"}[5m]))`, slo.Service.Name)
 
-	latencyValue, err := ms.queryPrometheus(ctx, query)
+	errorValue, err := ms.queryPrometheus(ctx, errorQuery, models.SeriesAggregationSum)
 	if err != nil {
 		return 0, err
 	}
 
-	// Convert to compliance ratio (1.0 = perfect, lower is worse)
-	if slo.LatencyThreshold > 0 {
-		if latencyValue <= slo.LatencyThreshold {
-			return 1.0, nil
-		}
-		return slo.LatencyThreshold / latencyValue, nil
+	totalValue, err := ms.queryPrometheus(ctx, totalQuery, models.SeriesAggregationSum)
+	if err != nil {
+		return 0, err
+	}
+
+	if totalValue == 0 {
+		return 1.0, nil // 100% success rate if no traffic
 	}
 
-	return 0.95, nil // Mock value
+	successRate := 1.0 - (errorValue / totalValue)
+	return successRate, nil
 }
 
-func (ms *MetricsService) calculateErrorRate(ctx context.Context, slo *models.SLO) (float64, error) {
+// calculateEventRatio computes correctness (validated / total outputs) and
+// durability (intact / checked objects) SLIs from SuccessMetric and
+// TotalMetric counters.
+func (ms *MetricsService) calculateEventRatio(ctx context.Context, slo *models.SLO) (float64, error) {
 	if slo.PrometheusQuery != "" {
 		return ms.queryCustomMetric(ctx, slo)
 	}
+	if slo.SuccessMetric == "" || slo.TotalMetric == "" {
+		return 0, fmt.Errorf("%s SLO requires success_metric and total_metric or a prometheus_query", slo.SLIType)
+	}
 
-	// Default error rate calculation
-	errorQuery := fmt.Sprintf(`sum(rate(http_requests_total{service="%s",status=~"5.."}[5m]))`, slo.Service.Name)
-	totalQuery := fmt.Sprintf(`sum(rate(http_requests_total{service="%s"}[5m]))`, slo.Service.Name)
-
-	errorValue, err := ms.queryPrometheus(ctx, errorQuery)
+	successQuery, totalQuery := eventRatioQueries(slo)
+	successValue, err := ms.queryPrometheus(ctx, successQuery, models.SeriesAggregationSum)
 	if err != nil {
 		return 0, err
 	}
 
-	totalValue, err := ms.queryPrometheus(ctx, totalQuery)
+	totalValue, err := ms.queryPrometheus(ctx, totalQuery, models.SeriesAggregationSum)
 	if err != nil {
 		return 0, err
 	}
 
 	if totalValue == 0 {
-		return 1.0, nil // 100% success rate if no traffic
+		return 1.0, nil // nothing produced, nothing wrong
 	}
 
-	successRate := 1.0 - (errorValue / totalValue)
-	return successRate, nil
+	return successValue / totalValue, nil
+}
+
+// eventRatioQueries returns the success and total rate queries of a
+// correctness or durability SLI.
+func eventRatioQueries(slo *models.SLO) (string, string) {
+	successQuery := fmt.Sprintf(`sum(rate(%s{service="%s"}[5m]))`, slo.SuccessMetric, slo.Service.Name)
+	totalQuery := fmt.Sprintf(`sum(rate(%s{service="%s"}[5m]))`, slo.TotalMetric, slo.Service.Name)
+	return successQuery, totalQuery
 }
 
 func (ms *MetricsService) queryCustomMetric(ctx context.Context, slo *models.SLO) (float64, error) {
@@ -159,34 +254,217 @@ func (ms *MetricsService) queryCustomMetric(ctx context.Context, slo *models.SLO
 		return 0.0, fmt.Errorf("no Prometheus query defined for custom SLO")
 	}
 
-	return ms.queryPrometheus(ctx, slo.PrometheusQuery)
+	return ms.queryPrometheus(ctx, slo.PrometheusQuery, slo.SeriesAggregation)
 }
 
-func (ms *MetricsService) queryPrometheus(ctx context.Context, query string) (float64, error) {
+// queryPrometheus runs an instant query and combines multiple series with
+// the given aggregation. Empty results return ErrNoData.
+func (ms *MetricsService) queryPrometheus(ctx context.Context, query string, aggregation models.SeriesAggregation) (float64, error) {
 	result, warnings, err := ms.prometheusAPI.Query(ctx, query, time.Now())
 	if err != nil {
 		return 0, fmt.Errorf("prometheus query failed: %w", err)
 	}
 
-	if len(warnings) > 0 {
-		fmt.Printf("Prometheus query warnings: %v\n", warnings)
-	}
+	recordWarnings(ctx, query, warnings)
 
 	switch result.Type() {
 	case model.ValVector:
-		vector := result.(model.Vector)
-		if len(vector) == 0 {
-			return 0.0, fmt.Errorf("no data returned from query")
-		}
-		return float64(vector[0].Value), nil
+		return aggregateSeries(vectorValues(result.(model.Vector)), aggregation)
 	case model.ValScalar:
 		scalar := result.(*model.Scalar)
-		return float64(scalar.Value), nil
+		return aggregateSeries([]float64{float64(scalar.Value)}, aggregation)
 	default:
 		return 0.0, fmt.Errorf("unsupported result type: %s", result.Type())
 	}
 }
 
+// GetSLIHistory returns good/total event counts per step between start and
+// end. Ingested samples take precedence over Prometheus; mock history is used
+// when neither is available. SLOs defined only by a ratio query get one
+// equally weighted sample per step (Good = ratio, Total = 1).
+func (ms *MetricsService) GetSLIHistory(slo *models.SLO, start, end time.Time, step time.Duration) ([]models.SLISample, error) {
+	history, err := ms.getIngestedHistory(slo, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) > 0 {
+		return history, nil
+	}
+
+	if ms.prometheusAPI == nil {
+		return ms.getMockHistory(slo, start, end, step), nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if isWindowBased(slo) {
+		return ms.windowHistory(ctx, slo, start, end, step)
+	}
+
+	hasEventMetrics := slo.SuccessMetric != "" && slo.TotalMetric != ""
+	if !hasEventMetrics && slo.PrometheusQuery == "" {
+		return ms.getMockHistory(slo, start, end, step), nil
+	}
+
+	if !hasEventMetrics {
+		ratios, err := ms.queryPrometheusRange(ctx, slo.PrometheusQuery, start, end, step, slo.SeriesAggregation)
+		if err != nil {
+			return nil, err
+		}
+		for ts, ratio := range ratios {
+			history = append(history, models.SLISample{
+				Timestamp: ts.Add(-step),
+				Good:      ratio,
+				Total:     1,
+			})
+		}
+		sortSamples(history)
+		return history, nil
+	}
+
+	stepString := model.Duration(step).String()
+	good, err := ms.queryPrometheusRange(ctx, fmt.Sprintf(`sum(increase(%s[%s]))`, slo.SuccessMetric, stepString), start, end, step, models.SeriesAggregationSum)
+	if err != nil {
+		return nil, err
+	}
+	total, err := ms.queryPrometheusRange(ctx, fmt.Sprintf(`sum(increase(%s[%s]))`, slo.TotalMetric, stepString), start, end, step, models.SeriesAggregationSum)
+	if err != nil {
+		return nil, err
+	}
+
+	// Each point covers the step ending at its timestamp.
+	for ts, totalValue := range total {
+		history = append(history, models.SLISample{
+			Timestamp: ts.Add(-step),
+			Good:      good[ts],
+			Total:     totalValue,
+		})
+	}
+	sortSamples(history)
+	return history, nil
+}
+
+func (ms *MetricsService) getIngestedHistory(slo *models.SLO, start, end time.Time, step time.Duration) ([]models.SLISample, error) {
+	var rows []models.MetricIngest
+	err := ms.db.
+		Where("slo_id = ? AND metric_type IN ? AND timestamp >= ? AND timestamp < ?", slo.ID, []string{"success", "total", "latency"}, start, end).
+		Order("timestamp").
+		Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	rollups, err := ingestedRollups(ms.db, slo.ID, []string{"success", "total", "latency"}, start, end)
+	if err != nil || len(rows)+len(rollups) == 0 {
+		return nil, err
+	}
+
+	buckets := make(map[int64]int)
+	var history []models.SLISample
+	sampleAt := func(timestamp time.Time) *models.SLISample {
+		index := int64(timestamp.Sub(start) / step)
+		i, ok := buckets[index]
+		if !ok {
+			history = append(history, models.SLISample{Timestamp: start.Add(time.Duration(index) * step)})
+			i = len(history) - 1
+			buckets[index] = i
+		}
+		return &history[i]
+	}
+
+	// Downsampled buckets are attributed to the step holding their start.
+	for _, rollup := range rollups {
+		sample := sampleAt(rollup.Timestamp)
+		switch rollup.MetricType {
+		case "success":
+			sample.Good += rollup.Value
+		case "total":
+			sample.Total += rollup.Value
+		case "latency":
+			sample.Total += float64(rollup.Count)
+			sample.Good += rollup.Good
+		}
+	}
+
+	for _, row := range rows {
+		sample := sampleAt(row.Timestamp)
+		switch row.MetricType {
+		case "success":
+			sample.Good += row.Value
+		case "total":
+			sample.Total += row.Value
+		case "latency":
+			// Each latency sample is one request, good if under the threshold.
+			sample.Total++
+			if slo.LatencyThreshold > 0 && row.Value <= slo.LatencyThreshold {
+				sample.Good++
+			}
+		}
+	}
+	sortSamples(history)
+	return history, nil
+}
+
+// queryPrometheusRange runs a range query and combines the series at each
+// timestamp with the given aggregation.
+func (ms *MetricsService) queryPrometheusRange(ctx context.Context, query string, start, end time.Time, step time.Duration, aggregation models.SeriesAggregation) (map[time.Time]float64, error) {
+	result, warnings, err := ms.prometheusAPI.QueryRange(ctx, query, v1.Range{Start: start, End: end, Step: step})
+	if err != nil {
+		return nil, fmt.Errorf("prometheus range query failed: %w", err)
+	}
+
+	recordWarnings(ctx, query, warnings)
+
+	matrix, ok := result.(model.Matrix)
+	if !ok {
+		return nil, fmt.Errorf("unsupported result type: %s", result.Type())
+	}
+
+	series := make(map[time.Time][]float64)
+	for _, stream := range matrix {
+		for _, pair := range stream.Values {
+			ts := pair.Timestamp.Time()
+			series[ts] = append(series[ts], float64(pair.Value))
+		}
+	}
+
+	values := make(map[time.Time]float64, len(series))
+	for ts, points := range series {
+		value, err := aggregateSeries(points, aggregation)
+		if errors.Is(err, ErrNoData) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		values[ts] = value
+	}
+	return values, nil
+}
+
+func sortSamples(samples []models.SLISample) {
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].Timestamp.Before(samples[j].Timestamp)
+	})
+}
+
+// getMockHistory produces steady mock traffic with a daily cycle at the mock
+// SLI for development/testing.
+func (ms *MetricsService) getMockHistory(slo *models.SLO, start, end time.Time, step time.Duration) []models.SLISample {
+	sli := ms.getMockSLI(slo)
+	var history []models.SLISample
+	for ts := start; ts.Before(end); ts = ts.Add(step) {
+		hour := float64(ts.UTC().Hour()) + float64(ts.UTC().Minute())/60
+		total := 1000 * step.Hours() * (1 + 0.5*math.Sin(2*math.Pi*hour/24))
+		history = append(history, models.SLISample{
+			Timestamp: ts,
+			Good:      total * sli,
+			Total:     total,
+		})
+	}
+	return history
+}
+
 func (ms *MetricsService) getMockSLI(slo *models.SLO) float64 {
 	// Mock data for development/testing
 	switch slo.SLIType {
@@ -198,56 +476,75 @@ func (ms *MetricsService) getMockSLI(slo *models.SLO) float64 {
 		return 0.992  // 99.2% success rate
 	case models.SLITypeCustom:
 		return 0.99   // 99% for custom
+	case models.SLITypeFreshness:
+		return 0.998  // 99.8% of minutes fresh
+	case models.SLITypeThroughput:
+		return 0.997  // 99.7% of minutes above minimum throughput
+	case models.SLITypeCorrectness:
+		return 0.9995 // 99.95% of outputs validated
+	case models.SLITypeDurability:
+		return 0.999999 // 99.9999% of objects intact
 	default:
 		return 0.99
 	}
 }
 
+// GetBurnRates returns burn rates over 5m, 1h, 6h and 24h from the SLO's
+// success and total metrics. Windows without data have a burn rate of 0 and
+// NoData is set when every window is empty.
 func (ms *MetricsService) GetBurnRates(slo *models.SLO) (*BurnRates, error) {
 	if ms.prometheusAPI == nil {
 		return ms.getMockBurnRates(), nil
 	}
+	if slo.SuccessMetric == "" || slo.TotalMetric == "" {
+		return &BurnRates{NoData: true}, nil
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
+	ctx, warnings := collectWarnings(ctx)
 
 	// Calculate burn rates over different time windows
-	fiveMinBurn, _ := ms.calculateBurnRate(ctx, slo, "5m")
-	oneHourBurn, _ := ms.calculateBurnRate(ctx, slo, "1h")
-	sixHourBurn, _ := ms.calculateBurnRate(ctx, slo, "6h")
-	twentyFourHourBurn, _ := ms.calculateBurnRate(ctx, slo, "24h")
+	windows := []string{"5m", "1h", "6h", "24h"}
+	rates := make([]float64, len(windows))
+	empty := 0
+	for i, window := range windows {
+		rate, err := ms.calculateBurnRate(ctx, slo, window)
+		if errors.Is(err, ErrNoData) {
+			empty++
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		rates[i] = rate
+	}
 
 	return &BurnRates{
-		CurrentBurnRate:    oneHourBurn, // Use 1h as current
-		FiveMinuteBurn:     fiveMinBurn,
-		OneHourBurn:        oneHourBurn,
-		SixHourBurn:        sixHourBurn,
-		TwentyFourHourBurn: twentyFourHourBurn,
+		CurrentBurnRate:    rates[1], // Use 1h as current
+		FiveMinuteBurn:     rates[0],
+		OneHourBurn:        rates[1],
+		SixHourBurn:        rates[2],
+		TwentyFourHourBurn: rates[3],
+		NoData:             empty == len(windows),
+		Warnings:           warnings.list(),
 	}, nil
 }
 
 func (ms *MetricsService) calculateBurnRate(ctx context.Context, slo *models.SLO, timeWindow string) (float64, error) {
-	// This is a simplified burn rate calculation
-	// In production, you'd want more sophisticated logic
-	
 	query := fmt.Sprintf(
 		`(sum(rate(%s[%s])) / sum(rate(%s[%s])))`,
 		slo.SuccessMetric, timeWindow,
 		slo.TotalMetric, timeWindow,
 	)
 
-	rate, err := ms.queryPrometheus(ctx, query)
+	rate, err := ms.queryPrometheus(ctx, query, slo.SeriesAggregation)
 	if err != nil {
-		return 1.0, err // Default to normal burn rate
+		return 0, err
 	}
 
 	// Convert to burn rate multiplier (1.0 = normal consumption)
-	normalConsumption := slo.Target
-	if rate <= 0 {
-		return 1.0, nil
-	}
-
-	return (1.0 - rate) / (1.0 - normalConsumption), nil
+	return (1.0 - rate) / (1.0 - slo.Target), nil
 }
 
 func (ms *MetricsService) getMockBurnRates() *BurnRates {
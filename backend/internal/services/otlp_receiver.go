@@ -0,0 +1,288 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"time"
+
+	"slo-platform/internal/models"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"gorm.io/gorm"
+)
+
+type OTLPResult struct {
+	DataPoints  int `json:"data_points"`
+	Matched     int `json:"matched"`
+	Unsupported int `json:"unsupported"`
+	Skipped     int `json:"skipped"` // points without a recorded value, or NaN
+	Stored      int `json:"stored"`
+	Late        int `json:"late"` // in or before a rolled-up minute, not stored
+}
+
+// OTLPReceiver accepts OTLP/HTTP metric exports. Monotonic sums named by an
+// SLO's success or total metric and histograms named by a latency SLO's
+// latency metric are stored as MetricIngest rows, the same data the SLI
+// engine reads for /metrics/ingest and remote write.
+type OTLPReceiver struct {
+	db       *gorm.DB
+	counters *counterTracker
+}
+
+func NewOTLPReceiver(db *gorm.DB) *OTLPReceiver {
+	return &OTLPReceiver{
+		db:       db,
+		counters: newCounterTracker(),
+	}
+}
+
+// HandleExport decodes an ExportMetricsServiceRequest, as protobuf or as
+// OTLP JSON, and stores the data points of every metric matching an SLO.
+// Points flagged as having no recorded value, and NaN values, are skipped.
+func (r *OTLPReceiver) HandleExport(body []byte, isJSON bool) (*OTLPResult, error) {
+	// ExportMetricsServiceRequest has the same fields as MetricsData, so the
+	// collector service package (and its gRPC dependencies) is not needed.
+	var request metricspb.MetricsData
+	var err error
+	if isJSON {
+		err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(body, &request)
+	} else {
+		err = proto.Unmarshal(body, &request)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	targets, err := loadIngestTargets(r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &OTLPResult{}
+	var rows []models.MetricIngest
+	for _, resourceMetrics := range request.GetResourceMetrics() {
+		resource := attributeLabels(resourceMetrics.GetResource().GetAttributes())
+		serviceName := resource["service_name"]
+		environment := resource["deployment_environment"]
+
+		for _, scopeMetrics := range resourceMetrics.GetScopeMetrics() {
+			for _, metric := range scopeMetrics.GetMetrics() {
+				switch {
+				case metric.GetSum() != nil:
+					sum := metric.GetSum()
+					result.DataPoints += len(sum.GetDataPoints())
+					if !sum.GetIsMonotonic() {
+						result.Unsupported += len(sum.GetDataPoints())
+						continue
+					}
+					cumulative := sum.GetAggregationTemporality() == metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+					for _, point := range sum.GetDataPoints() {
+						value := numberValue(point)
+						if noRecordedValue(point.GetFlags()) || math.IsNaN(value) {
+							result.Skipped++
+							continue
+						}
+						labels := pointLabels(resource, point.GetAttributes(), serviceName)
+						timestamp := time.Unix(0, int64(point.GetTimeUnixNano()))
+						matched := false
+						for _, target := range targets.counters[metric.GetName()] {
+							if !otlpTargetMatches(target, labels, environment) {
+								continue
+							}
+							matched = true
+							key := fmt.Sprintf("%d/%s/%s", target.slo.ID, target.metricType, seriesKey(labels))
+							if increase, ok := r.increase(key, cumulative, timestamp, value); ok {
+								rows = append(rows, ingestRow(target, labels, timestamp, target.metricType, increase))
+							}
+						}
+						if matched {
+							result.Matched++
+						}
+					}
+
+				case metric.GetHistogram() != nil:
+					histogram := metric.GetHistogram()
+					result.DataPoints += len(histogram.GetDataPoints())
+					cumulative := histogram.GetAggregationTemporality() == metricspb.AggregationTemporality_AGGREGATION_TEMPORALITY_CUMULATIVE
+					for _, point := range histogram.GetDataPoints() {
+						if noRecordedValue(point.GetFlags()) {
+							result.Skipped++
+							continue
+						}
+						labels := pointLabels(resource, point.GetAttributes(), serviceName)
+						timestamp := time.Unix(0, int64(point.GetTimeUnixNano()))
+						matched := false
+
+						// A histogram named as a success or total metric counts
+						// its observations.
+						for _, target := range targets.counters[metric.GetName()] {
+							if !otlpTargetMatches(target, labels, environment) {
+								continue
+							}
+							matched = true
+							key := fmt.Sprintf("%d/%s/%s", target.slo.ID, target.metricType, seriesKey(labels))
+							if value, ok := r.increase(key, cumulative, timestamp, float64(point.GetCount())); ok {
+								rows = append(rows, ingestRow(target, labels, timestamp, target.metricType, value))
+							}
+						}
+
+						for _, target := range targets.histograms[metric.GetName()] {
+							if !otlpTargetMatches(target, labels, environment) {
+								continue
+							}
+							buckets := otlpBuckets(point)
+							if len(buckets) == 0 {
+								continue
+							}
+							matched = true
+							latency := latencyFromBuckets(buckets, target.slo.LatencyThreshold, target.slo.LatencyMode)
+							key := fmt.Sprintf("%d/latency/%s", target.slo.ID, seriesKey(labels))
+							good, goodOK := r.increase(key+"/good", cumulative, timestamp, latency.Good)
+							total, totalOK := r.increase(key+"/total", cumulative, timestamp, latency.Total)
+							if goodOK && totalOK {
+								rows = append(rows,
+									ingestRow(target, labels, timestamp, "success", good),
+									ingestRow(target, labels, timestamp, "total", total))
+							}
+						}
+
+						if matched {
+							result.Matched++
+						}
+					}
+
+				case metric.GetGauge() != nil:
+					result.DataPoints += len(metric.GetGauge().GetDataPoints())
+					result.Unsupported += len(metric.GetGauge().GetDataPoints())
+				case metric.GetExponentialHistogram() != nil:
+					result.DataPoints += len(metric.GetExponentialHistogram().GetDataPoints())
+					result.Unsupported += len(metric.GetExponentialHistogram().GetDataPoints())
+				case metric.GetSummary() != nil:
+					result.DataPoints += len(metric.GetSummary().GetDataPoints())
+					result.Unsupported += len(metric.GetSummary().GetDataPoints())
+				}
+			}
+		}
+	}
+
+	if len(rows) > 0 {
+		stored, late, err := insertMetricIngests(r.db, rows)
+		if err != nil {
+			return nil, err
+		}
+		result.Stored = stored
+		result.Late = late
+	}
+
+	zap.L().Debug("Received OTLP metrics",
+		zap.Int("data_points", result.DataPoints),
+		zap.Int("matched", result.Matched),
+		zap.Int("stored", result.Stored),
+		zap.Int("late", result.Late))
+	return result, nil
+}
+
+// increase returns the increment carried by a data point: delta points
+// carry it directly, cumulative points are diffed against the previous one.
+func (r *OTLPReceiver) increase(key string, cumulative bool, timestamp time.Time, value float64) (float64, bool) {
+	if !cumulative {
+		return value, true
+	}
+	return r.counters.increase(key, timestamp, value)
+}
+
+// otlpTargetMatches applies the SLO's ingest matchers and, when the resource
+// sets deployment.environment, requires the SLO's service to be in it.
+func otlpTargetMatches(target ingestTarget, labels map[string]string, environment string) bool {
+	if environment != "" && target.slo.Service.Environment != environment {
+		return false
+	}
+	return matchAll(target.matchers, labels)
+}
+
+// noRecordedValue reports whether a data point only marks its series as
+// stale, the OTLP equivalent of a Prometheus staleness marker.
+func noRecordedValue(flags uint32) bool {
+	return flags&uint32(metricspb.DataPointFlags_DATA_POINT_FLAGS_NO_RECORDED_VALUE_MASK) != 0
+}
+
+func numberValue(point *metricspb.NumberDataPoint) float64 {
+	if value, ok := point.GetValue().(*metricspb.NumberDataPoint_AsInt); ok {
+		return float64(value.AsInt)
+	}
+	return point.GetAsDouble()
+}
+
+// otlpBuckets converts explicit-bound bucket counts into cumulative buckets
+// keyed by their upper bound.
+func otlpBuckets(point *metricspb.HistogramDataPoint) []histogramBucket {
+	counts := point.GetBucketCounts()
+	bounds := point.GetExplicitBounds()
+	if len(counts) == 0 || len(counts) != len(bounds)+1 {
+		return nil
+	}
+
+	buckets := make([]histogramBucket, len(counts))
+	var cumulative float64
+	for i, count := range counts {
+		cumulative += float64(count)
+		le := math.Inf(1)
+		if i < len(bounds) {
+			le = bounds[i]
+		}
+		buckets[i] = histogramBucket{le: le, count: cumulative}
+	}
+	return buckets
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// attributeLabels converts OTLP attributes to label names usable in ingest
+// matchers, e.g. service.name becomes service_name.
+func attributeLabels(attributes []*commonpb.KeyValue) map[string]string {
+	labels := make(map[string]string, len(attributes))
+	for _, attribute := range attributes {
+		labels[invalidLabelChars.ReplaceAllString(attribute.GetKey(), "_")] = anyValueString(attribute.GetValue())
+	}
+	return labels
+}
+
+// pointLabels merges resource and data point attributes. The service label
+// is set to service.name, and the environment label defaults to
+// deployment.environment, so default ingest matchers apply.
+func pointLabels(resource map[string]string, attributes []*commonpb.KeyValue, serviceName string) map[string]string {
+	labels := attributeLabels(attributes)
+	for name, value := range resource {
+		if _, ok := labels[name]; !ok {
+			labels[name] = value
+		}
+	}
+	if serviceName != "" {
+		labels["service"] = serviceName
+	}
+	if _, ok := labels["environment"]; !ok && resource["deployment_environment"] != "" {
+		labels["environment"] = resource["deployment_environment"]
+	}
+	return labels
+}
+
+func anyValueString(value *commonpb.AnyValue) string {
+	switch v := value.GetValue().(type) {
+	case *commonpb.AnyValue_StringValue:
+		return v.StringValue
+	case *commonpb.AnyValue_IntValue:
+		return strconv.FormatInt(v.IntValue, 10)
+	case *commonpb.AnyValue_DoubleValue:
+		return strconv.FormatFloat(v.DoubleValue, 'g', -1, 64)
+	case *commonpb.AnyValue_BoolValue:
+		return strconv.FormatBool(v.BoolValue)
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"slo-platform/internal/models"
+
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestOTLPReceiver(t *testing.T) *OTLPReceiver {
+	t.Helper()
+	db := newTestDB(t)
+	service := createService(t, db, "checkout", "prod")
+	slos := []*models.SLO{
+		{ServiceID: service.ID, Name: "availability", SLIType: models.SLITypeAvailability, Target: 0.999,
+			TimeWindowDays: 30, SuccessMetric: "checkout.requests.success", TotalMetric: "checkout.requests"},
+		{ServiceID: service.ID, Name: "latency", SLIType: models.SLITypeLatency, Target: 0.99,
+			TimeWindowDays: 30, LatencyMetric: "http.server.duration", LatencyThreshold: 0.25},
+	}
+	for _, slo := range slos {
+		if err := db.Create(slo).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	return NewOTLPReceiver(db)
+}
+
+// testdata/otlp/export.json is an OTLP JSON export from service checkout
+// with, 15s apart:
+//
+//	checkout.requests          cumulative 1000, 1060, no recorded value, NaN, 1100
+//	checkout.requests.success  delta 59, 38
+//	http.server.duration       delta histogram (80 of 100 under 0.25s), no recorded value
+//	checkout.queue.depth       a gauge
+func readExport(t *testing.T) []byte {
+	t.Helper()
+	body, err := os.ReadFile(filepath.Join("testdata", "otlp", "export.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func TestOTLPExport(t *testing.T) {
+	jsonBody := readExport(t)
+	var request metricspb.MetricsData
+	if err := protojson.Unmarshal(jsonBody, &request); err != nil {
+		t.Fatal(err)
+	}
+	protobufBody, err := proto.Marshal(&request)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		body   []byte
+		isJSON bool
+	}{
+		{"json", jsonBody, true},
+		{"protobuf", protobufBody, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			receiver := newTestOTLPReceiver(t)
+			result, err := receiver.HandleExport(tt.body, tt.isJSON)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := OTLPResult{DataPoints: 10, Matched: 6, Unsupported: 1, Skipped: 3, Stored: 6}
+			if *result != want {
+				t.Errorf("result = %+v, want %+v", *result, want)
+			}
+
+			var rows []models.MetricIngest
+			if err := receiver.db.Order("slo_id, metric_type, timestamp").Find(&rows).Error; err != nil {
+				t.Fatal(err)
+			}
+			var got []float64
+			for _, row := range rows {
+				got = append(got, row.Value)
+			}
+			// availability: success 59, 38 and total 60, then 40 across the
+			// skipped points; latency: success 80 and total 100.
+			wantValues := []float64{59, 38, 60, 40, 80, 100}
+			if len(got) != len(wantValues) {
+				t.Fatalf("stored %v, want %v", got, wantValues)
+			}
+			for i := range got {
+				if got[i] != wantValues[i] {
+					t.Fatalf("stored %v, want %v", got, wantValues)
+				}
+			}
+		})
+	}
+}
+
+func TestOTLPExportRejectsInvalidBodies(t *testing.T) {
+	receiver := newTestOTLPReceiver(t)
+	if _, err := receiver.HandleExport([]byte(`{"resourceMetrics": [`), true); err == nil {
+		t.Error("truncated JSON accepted")
+	}
+	if _, err := receiver.HandleExport([]byte{0x0a, 0xff}, false); err == nil {
+		t.Error("truncated protobuf accepted")
+	}
+}
@@ -0,0 +1,175 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultOverviewLimit = 10
+	MaxOverviewLimit     = 100
+)
+
+// OverviewFilter narrows an overview to one owner team and/or environment.
+// Limit caps the worst offender lists.
+type OverviewFilter struct {
+	OwnerTeam   string
+	Environment string
+	Limit       int
+}
+
+type overviewRow struct {
+	OwnerTeam          string
+	Environment        string
+	Status             *string
+	Count              int
+	MinRemainingBudget *float64
+	MaxBurnRate        *float64
+}
+
+// Overview summarizes SLO health across all live services from the stored
+// snapshots. SLOs the evaluator has not reached yet count as unevaluated.
+func (s *SLOService) Overview(filter OverviewFilter) (*models.Overview, error) {
+	limit := filter.Limit
+	if limit == 0 {
+		limit = DefaultOverviewLimit
+	}
+	if limit < 1 || limit > MaxOverviewLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPage, MaxOverviewLimit)
+	}
+
+	var rows []overviewRow
+	err := s.overviewQuery(filter).
+		Select(`services.owner_team, services.environment, slo_snapshots.status,
+			COUNT(*) AS count,
+			MIN(slo_snapshots.remaining_budget) AS min_remaining_budget,
+			MAX(slo_snapshots.current_burn_rate) AS max_burn_rate`).
+		Group("services.owner_team, services.environment, slo_snapshots.status").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	overview := &models.Overview{GeneratedAt: time.Now()}
+	teams := make(map[string]*models.StatusRollup)
+	environments := make(map[string]*models.StatusRollup)
+	for _, row := range rows {
+		addOverviewCounts(&overview.Counts, row)
+		addOverviewRollup(rollupFor(teams, row.OwnerTeam), row)
+		addOverviewRollup(rollupFor(environments, row.Environment), row)
+	}
+	overview.Teams = sortedRollups(teams)
+	overview.Environments = sortedRollups(environments)
+
+	// Read separately: aggregated timestamps lose their type on SQLite.
+	var oldest []time.Time
+	err = s.overviewQuery(filter).
+		Where("slo_snapshots.evaluated_at IS NOT NULL").
+		Order("slo_snapshots.evaluated_at").Limit(1).
+		Pluck("slo_snapshots.evaluated_at", &oldest).Error
+	if err != nil {
+		return nil, err
+	}
+	if len(oldest) > 0 {
+		overview.OldestEvaluation = &oldest[0]
+	}
+
+	if overview.WorstByBudget, err = s.worstOffenders(filter, "slo_snapshots.remaining_budget ASC", limit); err != nil {
+		return nil, err
+	}
+	if overview.WorstByBurnRate, err = s.worstOffenders(filter, "slo_snapshots.current_burn_rate DESC", limit); err != nil {
+		return nil, err
+	}
+	return overview, nil
+}
+
+// overviewQuery selects live SLOs with their snapshot, if any.
+func (s *SLOService) overviewQuery(filter OverviewFilter) *gorm.DB {
+	query := s.db.Model(&models.SLO{}).
+		Joins("JOIN services ON services.id = slos.service_id AND services.deleted_at IS NULL").
+		Joins("LEFT JOIN slo_snapshots ON slo_snapshots.slo_id = slos.id")
+	if filter.OwnerTeam != "" {
+		query = query.Where("services.owner_team = ?", filter.OwnerTeam)
+	}
+	if filter.Environment != "" {
+		query = query.Where("services.environment = ?", filter.Environment)
+	}
+	return query
+}
+
+// worstOffenders lists evaluated SLOs with data, in order.
+func (s *SLOService) worstOffenders(filter OverviewFilter, order string, limit int) ([]models.OverviewSLO, error) {
+	offenders := []models.OverviewSLO{}
+	err := s.overviewQuery(filter).
+		Select(`slos.id AS slo_id, slos.name AS slo_name, services.id AS service_id,
+			services.name AS service_name, services.environment, services.owner_team,
+			slo_snapshots.status, slo_snapshots.remaining_budget, slo_snapshots.current_burn_rate,
+			slo_snapshots.evaluated_at`).
+		Where("slo_snapshots.status IS NOT NULL AND slo_snapshots.status <> ?", "no_data").
+		Order(order + ", slos.id").
+		Limit(limit).
+		Scan(&offenders).Error
+	return offenders, err
+}
+
+// addOverviewCounts adds a grouped row to counts. It reports whether the
+// row has data.
+func addOverviewCounts(counts *models.StatusCounts, row overviewRow) bool {
+	counts.Total += row.Count
+	status := ""
+	if row.Status != nil {
+		status = *row.Status
+	}
+	switch status {
+	case "healthy":
+		counts.Healthy += row.Count
+	case "degraded":
+		counts.Degraded += row.Count
+	case "breached":
+		counts.Breached += row.Count
+	case "no_data":
+		counts.NoData += row.Count
+		return false
+	default:
+		counts.Unevaluated += row.Count
+		return false
+	}
+	return true
+}
+
+// addOverviewRollup adds a grouped row to a rollup. Rows without data do
+// not count towards the budget and burn rate extremes.
+func addOverviewRollup(rollup *models.StatusRollup, row overviewRow) {
+	if !addOverviewCounts(&rollup.StatusCounts, row) {
+		return
+	}
+	if row.MinRemainingBudget != nil && (rollup.MinRemainingBudget == nil || *row.MinRemainingBudget < *rollup.MinRemainingBudget) {
+		rollup.MinRemainingBudget = row.MinRemainingBudget
+	}
+	if row.MaxBurnRate != nil && (rollup.MaxBurnRate == nil || *row.MaxBurnRate > *rollup.MaxBurnRate) {
+		rollup.MaxBurnRate = row.MaxBurnRate
+	}
+}
+
+func rollupFor(rollups map[string]*models.StatusRollup, name string) *models.StatusRollup {
+	rollup, ok := rollups[name]
+	if !ok {
+		rollup = &models.StatusRollup{Name: name}
+		rollups[name] = rollup
+	}
+	return rollup
+}
+
+func sortedRollups(rollups map[string]*models.StatusRollup) []models.StatusRollup {
+	result := make([]models.StatusRollup, 0, len(rollups))
+	for _, rollup := range rollups {
+		result = append(result, *rollup)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
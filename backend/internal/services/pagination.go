@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// ErrInvalidPage is returned for malformed cursors, unknown sort fields and
+// limits out of range.
+var ErrInvalidPage = errors.New("invalid page request")
+
+const (
+	DefaultPageLimit = 50
+	MaxPageLimit     = 200
+)
+
+// PageRequest selects a page of a list. Pages are keyset-based: Cursor is
+// the NextCursor of the previous page and is only valid with the same Sort.
+type PageRequest struct {
+	Limit  int    // default DefaultPageLimit
+	Cursor string // empty for the first page
+	Sort   string // field name, prefixed with "-" for descending
+}
+
+type PageInfo struct {
+	Limit      int    `json:"limit"`
+	Count      int    `json:"count"`
+	Total      *int64 `json:"total,omitempty"` // omitted when a status filter applies
+	Sort       string `json:"sort"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+}
+
+type sortKind int
+
+const (
+	sortString sortKind = iota
+	sortNumber
+	sortTime
+)
+
+// sortField is a column a list can be sorted by. Ties are broken by id.
+type sortField struct {
+	column string
+	kind   sortKind
+}
+
+type pageCursor struct {
+	Sort  string      `json:"s"`
+	Value interface{} `json:"v"`
+	ID    uint        `json:"id"`
+}
+
+// pageQuery describes a paginated list: query holds the filters, table
+// qualifies the sort columns, and keep, when set, filters loaded rows on
+// values the database does not have (e.g. computed status). With keep the
+// query is read in chunks until the page is full, and no total is given.
+type pageQuery struct {
+	query       *gorm.DB
+	table       string
+	preloads    []string
+	sorts       map[string]sortField
+	defaultSort string
+	keep        func(row interface{}) (bool, error)
+}
+
+var pageSchemas sync.Map
+
+// paginate loads one page of pq into dest, a pointer to a slice of models.
+func paginate(pq pageQuery, page PageRequest, dest interface{}) (*PageInfo, error) {
+	limit := page.Limit
+	if limit == 0 {
+		limit = DefaultPageLimit
+	}
+	if limit < 1 || limit > MaxPageLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidPage, MaxPageLimit)
+	}
+
+	sortName := page.Sort
+	if sortName == "" {
+		sortName = pq.defaultSort
+	}
+	descending := strings.HasPrefix(sortName, "-")
+	field, ok := pq.sorts[strings.TrimPrefix(sortName, "-")]
+	if !ok {
+		return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidPage, strings.TrimPrefix(sortName, "-"))
+	}
+
+	slice := reflect.ValueOf(dest).Elem()
+	rowSchema, err := schema.Parse(reflect.New(slice.Type().Elem()).Interface(), &pageSchemas, pq.query.NamingStrategy)
+	if err != nil {
+		return nil, err
+	}
+	sortSchemaField := rowSchema.LookUpField(field.column)
+	if sortSchemaField == nil || rowSchema.PrioritizedPrimaryField == nil {
+		return nil, fmt.Errorf("%w: cannot sort by %q", ErrInvalidPage, field.column)
+	}
+
+	base := pq.query.Session(&gorm.Session{})
+	info := &PageInfo{Limit: limit, Sort: sortName}
+	if pq.keep == nil {
+		var total int64
+		if err := base.Count(&total).Error; err != nil {
+			return nil, err
+		}
+		info.Total = &total
+	}
+
+	var after *pageCursor
+	if page.Cursor != "" {
+		if after, err = decodeCursor(page.Cursor, sortName, field.kind); err != nil {
+			return nil, err
+		}
+	}
+
+	column := pq.table + "." + field.column
+	op, direction := ">", "ASC"
+	if descending {
+		op, direction = "<", "DESC"
+	}
+
+	rows := reflect.MakeSlice(slice.Type(), 0, limit+1)
+	for rows.Len() <= limit {
+		query := base
+		for _, preload := range pq.preloads {
+			query = query.Preload(preload)
+		}
+		if after != nil {
+			query = query.Where(fmt.Sprintf("(%s %s ? OR (%s = ? AND %s.id %s ?))", column, op, column, pq.table, op),
+				after.Value, after.Value, after.ID)
+		}
+
+		chunk := reflect.New(slice.Type())
+		want := limit + 1 - rows.Len()
+		err := query.Order(fmt.Sprintf("%s %s, %s.id %s", column, direction, pq.table, direction)).
+			Limit(want).Find(chunk.Interface()).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for i := 0; i < chunk.Elem().Len(); i++ {
+			row := chunk.Elem().Index(i)
+			if pq.keep != nil {
+				kept, err := pq.keep(row.Addr().Interface())
+				if err != nil {
+					return nil, err
+				}
+				if !kept {
+					continue
+				}
+			}
+			rows = reflect.Append(rows, row)
+		}
+
+		if chunk.Elem().Len() < want {
+			break
+		}
+		last := chunk.Elem().Index(chunk.Elem().Len() - 1)
+		after = cursorFor(last, sortName, sortSchemaField, rowSchema.PrioritizedPrimaryField)
+	}
+
+	if rows.Len() > limit {
+		info.HasMore = true
+		rows = rows.Slice(0, limit)
+		last := rows.Index(limit - 1)
+		info.NextCursor = encodeCursor(cursorFor(last, sortName, sortSchemaField, rowSchema.PrioritizedPrimaryField))
+	}
+	info.Count = rows.Len()
+	slice.Set(rows)
+	return info, nil
+}
+
+func cursorFor(row reflect.Value, sortName string, sortField, idField *schema.Field) *pageCursor {
+	value, _ := sortField.ValueOf(context.Background(), row)
+	if t, ok := value.(time.Time); ok {
+		value = t.UTC().Format(time.RFC3339Nano)
+	}
+	id, _ := idField.ValueOf(context.Background(), row)
+	cursor := &pageCursor{Sort: sortName, Value: value}
+	if id, ok := id.(uint); ok {
+		cursor.ID = id
+	}
+	return cursor
+}
+
+func encodeCursor(cursor *pageCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(encoded, sortName string, kind sortKind) (*pageCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+	var cursor pageCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+	}
+	if cursor.Sort != sortName {
+		return nil, fmt.Errorf("%w: cursor was issued for sort %q", ErrInvalidPage, cursor.Sort)
+	}
+
+	switch kind {
+	case sortString:
+		if _, ok := cursor.Value.(string); !ok {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+		}
+	case sortNumber:
+		if _, ok := cursor.Value.(float64); !ok {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+		}
+	case sortTime:
+		text, _ := cursor.Value.(string)
+		t, err := time.Parse(time.RFC3339Nano, text)
+		if err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor", ErrInvalidPage)
+		}
+		cursor.Value = t
+	}
+	return &cursor, nil
+}
+
+// prefixPattern returns a LIKE pattern matching values that start with
+// prefix, for use with ESCAPE '\'.
+func prefixPattern(prefix string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(prefix) + "%"
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"slo-platform/internal/models"
+)
+
+func TestPaginate(t *testing.T) {
+	db := newTestDB(t)
+	for _, name := range []string{"delta", "alpha", "echo", "bravo", "charlie"} {
+		createService(t, db, name, "prod") // IDs 1 to 5, all owned by payments
+	}
+	newQuery := func(keep func(row interface{}) (bool, error)) pageQuery {
+		return pageQuery{
+			query: db.Model(&models.Service{}),
+			table: "services",
+			sorts: map[string]sortField{
+				"name":       {column: "name", kind: sortString},
+				"owner_team": {column: "owner_team", kind: sortString},
+				"id":         {column: "id", kind: sortNumber},
+				"created_at": {column: "created_at", kind: sortTime},
+			},
+			defaultSort: "name",
+			keep:        keep,
+		}
+	}
+	oddIDs := func(row interface{}) (bool, error) {
+		return row.(*models.Service).ID%2 == 1, nil
+	}
+
+	tests := []struct {
+		name  string
+		sort  string
+		limit int
+		keep  func(row interface{}) (bool, error)
+		want  []uint
+	}{
+		{"default sort", "", 2, nil, []uint{2, 4, 5, 1, 3}},
+		{"descending", "-name", 2, nil, []uint{3, 1, 5, 4, 2}},
+		{"ties broken by id", "owner_team", 2, nil, []uint{1, 2, 3, 4, 5}},
+		{"descending ties broken by id", "-owner_team", 2, nil, []uint{5, 4, 3, 2, 1}},
+		{"descending numbers", "-id", 3, nil, []uint{5, 4, 3, 2, 1}},
+		{"descending times", "-created_at", 2, nil, []uint{5, 4, 3, 2, 1}},
+		{"kept rows refilled in chunks", "name", 1, oddIDs, []uint{5, 1, 3}},
+		{"kept rows, descending", "-owner_team", 2, oddIDs, []uint{5, 3, 1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []uint
+			cursor := ""
+			for pages := 0; ; pages++ {
+				if pages > 10 {
+					t.Fatal("pages never end")
+				}
+				var rows []models.Service
+				info, err := paginate(newQuery(tt.keep), PageRequest{Limit: tt.limit, Cursor: cursor, Sort: tt.sort}, &rows)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if info.Count != len(rows) || info.Count > tt.limit {
+					t.Fatalf("count %d for %d rows, limit %d", info.Count, len(rows), tt.limit)
+				}
+				if (info.Total == nil) != (tt.keep != nil) {
+					t.Errorf("total %v, want one only without a keep filter", info.Total)
+				}
+				for _, row := range rows {
+					got = append(got, row.ID)
+				}
+				if !info.HasMore {
+					break
+				}
+				cursor = info.NextCursor
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IDs %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaginateInvalid(t *testing.T) {
+	db := newTestDB(t)
+	for _, name := range []string{"alpha", "bravo", "charlie"} {
+		createService(t, db, name, "prod")
+	}
+	pq := pageQuery{
+		query:       db.Model(&models.Service{}),
+		table:       "services",
+		sorts:       map[string]sortField{"name": {column: "name", kind: sortString}, "id": {column: "id", kind: sortNumber}},
+		defaultSort: "name",
+	}
+	var rows []models.Service
+	info, err := paginate(pq, PageRequest{Limit: 1}, &rows)
+	if err != nil || info.NextCursor == "" {
+		t.Fatalf("first page: %+v, %v", info, err)
+	}
+
+	tests := []struct {
+		name string
+		page PageRequest
+	}{
+		{"cursor under another sort", PageRequest{Cursor: info.NextCursor, Sort: "id"}},
+		{"cursor under the reversed sort", PageRequest{Cursor: info.NextCursor, Sort: "-name"}},
+		{"malformed cursor", PageRequest{Cursor: "not a cursor"}},
+		{"unknown sort", PageRequest{Sort: "owner_team"}},
+		{"limit too large", PageRequest{Limit: MaxPageLimit + 1}},
+		{"negative limit", PageRequest{Limit: -1}},
+	}
+	for _, tt := range tests {
+		if _, err := paginate(pq, tt.page, &rows); !errors.Is(err, ErrInvalidPage) {
+			t.Errorf("%s: got %v, want ErrInvalidPage", tt.name, err)
+		}
+	}
+}
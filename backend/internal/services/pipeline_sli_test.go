@@ -0,0 +1,132 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"slo-platform/internal/models"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+func TestValidatePipelineSLOs(t *testing.T) {
+	var cases []struct {
+		Name   string     `json:"name"`
+		SLO    models.SLO `json:"slo"`
+		Errors []string   `json:"errors"` // invalid fields, in order
+	}
+	readJSON(t, "slos/pipeline.json", &cases)
+
+	for _, tc := range cases {
+		t.Run(tc.Name, func(t *testing.T) {
+			err := ValidateSLO(&tc.SLO)
+			var fields []string
+			var validation *ValidationError
+			if errors.As(err, &validation) {
+				for _, field := range validation.Fields {
+					fields = append(fields, field.Field)
+				}
+			} else if err != nil {
+				t.Fatalf("got %v, want a *ValidationError", err)
+			}
+			if !reflect.DeepEqual(fields, tc.Errors) {
+				t.Errorf("invalid fields %v, want %v (%v)", fields, tc.Errors, err)
+			}
+		})
+	}
+}
+
+func TestWindowConditionFor(t *testing.T) {
+	service := models.Service{Name: "orders"}
+	tests := []struct {
+		name string
+		slo  models.SLO
+		want windowCondition
+	}{
+		{
+			"freshness template",
+			models.SLO{SLIType: models.SLITypeFreshness, Service: service,
+				FreshnessMetric: "orders_last_export_timestamp_seconds", FreshnessThresholdSeconds: 900},
+			windowCondition{Query: `time() - max(orders_last_export_timestamp_seconds{service="orders"})`, Threshold: 900, Comparison: "<="},
+		},
+		{
+			"freshness query overrides the metric",
+			models.SLO{SLIType: models.SLITypeFreshness, Service: service, PrometheusQuery: "time() - max(export_ts)",
+				FreshnessMetric: "ignored", FreshnessThresholdSeconds: 60},
+			windowCondition{Query: "time() - max(export_ts)", Threshold: 60, Comparison: "<="},
+		},
+		{
+			"throughput template",
+			models.SLO{SLIType: models.SLITypeThroughput, Service: service,
+				ThroughputMetric: "events_processed_total", MinThroughputPerMinute: 500},
+			windowCondition{Query: `sum(rate(events_processed_total{service="orders"}[5m])) * 60`, Threshold: 500, Comparison: ">="},
+		},
+		{
+			"window mode uses the SLO's comparison",
+			models.SLO{SLIType: models.SLITypeAvailability, SLIMode: models.SLIModeWindow, Service: service,
+				PrometheusQuery: "up", WindowThreshold: 1, WindowComparison: ">"},
+			windowCondition{Query: "up", Threshold: 1, Comparison: ">"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := windowConditionFor(&tt.slo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("got %+v, want %+v", got, tt.want)
+			}
+			if !isWindowBased(&tt.slo) {
+				t.Error("not window-based")
+			}
+		})
+	}
+
+	for _, sliType := range []models.SLIType{models.SLITypeFreshness, models.SLITypeThroughput} {
+		if _, err := windowConditionFor(&models.SLO{SLIType: sliType}); err == nil {
+			t.Errorf("%s SLO without a metric or query: no error", sliType)
+		}
+	}
+}
+
+func TestWindowConditionIsGood(t *testing.T) {
+	tests := []struct {
+		value      float64
+		comparison string
+		threshold  float64
+		want       bool
+	}{
+		{900, "<=", 900, true}, // freshness: data exactly at the maximum age
+		{901, "<=", 900, false},
+		{900, "<", 900, false},
+		{500, ">=", 500, true}, // throughput: exactly the minimum rate
+		{499.9, ">=", 500, false},
+		{500, ">", 500, false},
+		{500, "", 500, true}, // defaults to >=
+	}
+	for _, tt := range tests {
+		condition := windowCondition{Threshold: tt.threshold, Comparison: tt.comparison}
+		if got := condition.isGood(tt.value); got != tt.want {
+			t.Errorf("%v %q %v = %v, want %v", tt.value, tt.comparison, tt.threshold, got, tt.want)
+		}
+	}
+}
+
+func TestEventRatioQueries(t *testing.T) {
+	slo := &models.SLO{SLIType: models.SLITypeCorrectness, Service: models.Service{Name: "billing"},
+		SuccessMetric: "invoices_validated_total", TotalMetric: "invoices_generated_total"}
+	success, total := eventRatioQueries(slo)
+	if want := `sum(rate(invoices_validated_total{service="billing"}[5m]))`; success != want {
+		t.Errorf("success query %s, want %s", success, want)
+	}
+	if want := `sum(rate(invoices_generated_total{service="billing"}[5m]))`; total != want {
+		t.Errorf("total query %s, want %s", total, want)
+	}
+	for _, query := range []string{success, total} {
+		if err := checkPromQL(query, parser.ValueTypeVector); err != nil {
+			t.Errorf("%s: %v", query, err)
+		}
+	}
+}
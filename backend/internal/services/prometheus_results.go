@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+
+	"slo-platform/internal/models"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+)
+
+// ErrNoData is returned when a query matches no series, or only series
+// without a value (NaN, e.g. a ratio with no traffic).
+var ErrNoData = errors.New("no data returned from query")
+
+type warningsKey struct{}
+
+// warningCollector gathers Prometheus warnings for one API request.
+type warningCollector struct {
+	mu       sync.Mutex
+	warnings []string
+}
+
+// collectWarnings returns a context whose Prometheus warnings are recorded in
+// the returned collector instead of being logged.
+func collectWarnings(ctx context.Context) (context.Context, *warningCollector) {
+	collector := &warningCollector{}
+	return context.WithValue(ctx, warningsKey{}, collector), collector
+}
+
+func (wc *warningCollector) list() []string {
+	wc.mu.Lock()
+	defer wc.mu.Unlock()
+	return append([]string(nil), wc.warnings...)
+}
+
+func recordWarnings(ctx context.Context, query string, warnings v1.Warnings) {
+	if len(warnings) == 0 {
+		return
+	}
+
+	collector, ok := ctx.Value(warningsKey{}).(*warningCollector)
+	if !ok {
+		zap.L().Warn("Prometheus query warnings", zap.String("query", query), zap.Strings("warnings", warnings))
+		return
+	}
+
+	collector.mu.Lock()
+	defer collector.mu.Unlock()
+	for _, warning := range warnings {
+		collector.warnings = appendUnique(collector.warnings, warning)
+	}
+}
+
+func appendUnique(values []string, value string) []string {
+	for _, existing := range values {
+		if existing == value {
+			return values
+		}
+	}
+	return append(values, value)
+}
+
+// aggregateSeries combines the values of a multi-series result. Series
+// without a value are ignored.
+func aggregateSeries(values []float64, aggregation models.SeriesAggregation) (float64, error) {
+	var present []float64
+	for _, value := range values {
+		if !math.IsNaN(value) {
+			present = append(present, value)
+		}
+	}
+	if len(present) == 0 {
+		return 0, ErrNoData
+	}
+	if len(present) == 1 {
+		return present[0], nil
+	}
+
+	switch aggregation {
+	case models.SeriesAggregationError:
+		return 0, fmt.Errorf("query returned %d series; aggregate it to one series or set series_aggregation", len(present))
+	case models.SeriesAggregationMax:
+		result := present[0]
+		for _, value := range present[1:] {
+			result = math.Max(result, value)
+		}
+		return result, nil
+	case models.SeriesAggregationSum, models.SeriesAggregationAvg:
+		var sum float64
+		for _, value := range present {
+			sum += value
+		}
+		if aggregation == models.SeriesAggregationAvg {
+			return sum / float64(len(present)), nil
+		}
+		return sum, nil
+	default:
+		result := present[0]
+		for _, value := range present[1:] {
+			result = math.Min(result, value)
+		}
+		return result, nil
+	}
+}
+
+func vectorValues(vector model.Vector) []float64 {
+	values := make([]float64, len(vector))
+	for i, sample := range vector {
+		values[i] = float64(sample.Value)
+	}
+	return values
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrInvalidPromotion is returned for promotions that cannot be carried out
+// as requested, e.g. to the source environment or of another service's SLOs.
+var ErrInvalidPromotion = errors.New("invalid promotion")
+
+type PromotionRequest struct {
+	ToEnvironment string `json:"to_environment"` // default: the next environment by rank
+	SLOIDs        []uint `json:"slo_ids"`        // default: all of the service's SLOs
+	Overwrite     bool   `json:"overwrite"`      // replace SLOs with the same name in the target
+}
+
+// PromoteSLOs copies SLO definitions from a service to the same service in
+// another environment, registering it there if needed. SLOs are matched by
+// name; existing ones are left alone unless req.Overwrite is set.
+func (s *SLOService) PromoteSLOs(serviceID uint, req PromotionRequest) (*models.PromotionResult, error) {
+	var source models.Service
+	if err := s.db.Preload("SLOs").First(&source, serviceID).Error; err != nil {
+		return nil, err
+	}
+
+	target, err := s.promotionTarget(source.Environment, req.ToEnvironment)
+	if err != nil {
+		return nil, err
+	}
+
+	slos := source.SLOs
+	if len(req.SLOIDs) > 0 {
+		byID := make(map[uint]models.SLO, len(source.SLOs))
+		for _, slo := range source.SLOs {
+			byID[slo.ID] = slo
+		}
+		slos = nil
+		for _, id := range req.SLOIDs {
+			slo, ok := byID[id]
+			if !ok {
+				return nil, fmt.Errorf("%w: SLO %d does not belong to service %d", ErrInvalidPromotion, id, serviceID)
+			}
+			slos = append(slos, slo)
+		}
+	}
+
+	result := &models.PromotionResult{
+		SourceServiceID: source.ID,
+		FromEnvironment: source.Environment,
+		ToEnvironment:   target,
+		Created:         []models.SLO{},
+		Updated:         []models.SLO{},
+	}
+	err = s.db.Transaction(func(tx *gorm.DB) error {
+		var services []models.Service
+		if err := tx.Where("name = ? AND environment = ?", source.Name, target).Limit(1).Find(&services).Error; err != nil {
+			return err
+		}
+		if len(services) == 0 {
+			result.Service = models.Service{
+				Name:        source.Name,
+				OwnerTeam:   source.OwnerTeam,
+				Environment: target,
+				Version:     source.Version,
+				Description: source.Description,
+			}
+			if err := tx.Create(&result.Service).Error; err != nil {
+				return err
+			}
+			result.ServiceCreated = true
+		} else {
+			result.Service = services[0]
+		}
+
+		var existing []models.SLO
+		if err := tx.Where("service_id = ?", result.Service.ID).Find(&existing).Error; err != nil {
+			return err
+		}
+		byName := make(map[string]models.SLO, len(existing))
+		for _, slo := range existing {
+			byName[slo.Name] = slo
+		}
+
+		for _, slo := range slos {
+			promoted := promotedSLO(slo, result.Service.ID)
+			current, exists := byName[slo.Name]
+			switch {
+			case !exists:
+				if err := tx.Create(&promoted).Error; err != nil {
+					return err
+				}
+				result.Created = append(result.Created, promoted)
+			case req.Overwrite:
+				promoted.ID = current.ID
+				promoted.CreatedAt = current.CreatedAt
+				if err := tx.Save(&promoted).Error; err != nil {
+					return err
+				}
+				result.Updated = append(result.Updated, promoted)
+			default:
+				result.Skipped = append(result.Skipped, slo.Name)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// promotionTarget resolves the environment to promote to: the requested one,
+// or the lowest ranked environment above the source.
+func (s *SLOService) promotionTarget(from, to string) (string, error) {
+	if to != "" {
+		if to == from {
+			return "", fmt.Errorf("%w: service is already in %s", ErrInvalidPromotion, to)
+		}
+		if err := validateEnvironment(s.db, to); err != nil {
+			return "", err
+		}
+		return to, nil
+	}
+
+	var source models.Environment
+	if err := s.db.Where("name = ?", from).First(&source).Error; err != nil {
+		return "", fmt.Errorf("%w %q", ErrUnknownEnvironment, from)
+	}
+	var next []models.Environment
+	if err := s.db.Where("rank > ?", source.Rank).Order("rank, name").Limit(1).Find(&next).Error; err != nil {
+		return "", err
+	}
+	if len(next) == 0 {
+		return "", fmt.Errorf("%w: no environment ranks above %s, set to_environment", ErrInvalidPromotion, from)
+	}
+	return next[0].Name, nil
+}
+
+// promotedSLO copies an SLO's definition for another service.
+func promotedSLO(slo models.SLO, serviceID uint) models.SLO {
+	slo.ID = 0
+	slo.ServiceID = serviceID
+	slo.Service = models.Service{}
+	slo.CreatedAt = time.Time{}
+	slo.UpdatedAt = time.Time{}
+	slo.DeletedAt = gorm.DeletedAt{}
+	slo.CurrentStatus = nil
+	slo.ErrorBudget = nil
+	return slo
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// Experimental functions such as double_exponential_smoothing are accepted;
+// Prometheus itself rejects them at query time unless they are enabled there.
+func init() {
+	parser.EnableExperimentalFunctions = true
+}
+
+// checkPromQL parses a query with the Prometheus parser, which also
+// type-checks it, and checks that it evaluates to one of the value types the
+// query is used as. A broken prometheus_query is then rejected when the SLO
+// is saved rather than on every evaluation.
+func checkPromQL(query string, want ...parser.ValueType) error {
+	expr, err := parser.ParseExpr(query)
+	if err != nil {
+		return err
+	}
+	got := expr.Type()
+	for _, valueType := range want {
+		if got == valueType {
+			return nil
+		}
+	}
+	names := make([]string, len(want))
+	for i, valueType := range want {
+		names[i] = parser.DocumentedType(valueType)
+	}
+	return fmt.Errorf("must evaluate to %s, got %s", strings.Join(names, " or "), parser.DocumentedType(got))
+}
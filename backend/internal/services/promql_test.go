@@ -0,0 +1,53 @@
+package services
+
+import (
+	"strings"
+	"testing"
+
+	"slo-platform/internal/models"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+func TestCheckPromQL(t *testing.T) {
+	vectorOrScalar := []parser.ValueType{parser.ValueTypeVector, parser.ValueTypeScalar}
+	tests := []struct {
+		query string
+		want  []parser.ValueType
+		err   string // a substring of the error, empty if the query is valid
+	}{
+		{`sum(rate(http_requests_total{code!~"5.."}[5m])) / sum(rate(http_requests_total[5m]))`, vectorOrScalar, ""},
+		{`time() - max(orders_last_export_timestamp_seconds)`, vectorOrScalar, ""},
+		{`scalar(up)`, vectorOrScalar, ""},
+		{`{"up"}`, vectorOrScalar, ""},
+		{`double_exponential_smoothing(x[1h], 0.5, 0.5)`, vectorOrScalar, ""},
+		{`sum(`, vectorOrScalar, "unclosed left parenthesis"},
+		{`rate(x)`, vectorOrScalar, "expected type range vector"},
+		{`up[5m]`, vectorOrScalar, "must evaluate to instant vector or scalar, got range vector"},
+		{`"up"`, vectorOrScalar, "got string"},
+		{`scalar(up)`, []parser.ValueType{parser.ValueTypeVector}, "must evaluate to instant vector, got scalar"},
+	}
+	for _, tt := range tests {
+		err := checkPromQL(tt.query, tt.want...)
+		switch {
+		case tt.err == "" && err != nil:
+			t.Errorf("%s: %v", tt.query, err)
+		case tt.err != "" && err == nil:
+			t.Errorf("%s: accepted, want error containing %q", tt.query, tt.err)
+		case tt.err != "" && !strings.Contains(err.Error(), tt.err):
+			t.Errorf("%s: error %q, want it to contain %q", tt.query, err, tt.err)
+		}
+	}
+}
+
+func TestValidateSLOQueryType(t *testing.T) {
+	slo := &models.SLO{Name: "api", ServiceID: 1, SLIType: models.SLITypeCustom, Target: 0.99, TimeWindowDays: 30,
+		PrometheusQuery: `scalar(sum(up) / count(up))`}
+	if err := ValidateSLO(slo); err != nil {
+		t.Errorf("scalar query: %v", err)
+	}
+	slo.GroupBy = "region"
+	if err := ValidateSLO(slo); err == nil || !strings.Contains(err.Error(), "prometheus_query") {
+		t.Errorf("scalar query sliced by region: got %v, want a prometheus_query error", err)
+	}
+}
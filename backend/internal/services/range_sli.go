@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"github.com/prometheus/common/model"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRangeStep = time.Hour
+	minRangeStep     = time.Minute
+	// Longest span covered by a single range query, so a 30d window is read in
+	// several cheap queries rather than one large one.
+	maxRangeChunk = 7 * 24 * time.Hour
+)
+
+func rangeStep(slo *models.SLO) time.Duration {
+	if slo.RangeStepSeconds <= 0 {
+		return defaultRangeStep
+	}
+	return time.Duration(slo.RangeStepSeconds) * time.Second
+}
+
+func usesRangeQueries(slo *models.SLO) bool {
+	return slo.BudgetQueryMode == models.BudgetQueryRange || slo.BudgetQueryMode == models.BudgetQueryRecordingRule
+}
+
+// rangeQueries returns per-step good and total queries for the SLO. In range
+// mode they take increase() of the raw counters over each step; in
+// recording_rule mode SuccessMetric and TotalMetric name recording rules that
+// already hold per-step counts. invert means the first query counts bad events.
+func rangeQueries(slo *models.SLO, step time.Duration) (string, string, bool, error) {
+	if slo.BudgetQueryMode == models.BudgetQueryRecordingRule {
+		if slo.SuccessMetric == "" || slo.TotalMetric == "" {
+			return "", "", false, fmt.Errorf("recording_rule mode requires success_metric and total_metric recording rules")
+		}
+		return fmt.Sprintf(`sum(%s)`, slo.SuccessMetric), fmt.Sprintf(`sum(%s)`, slo.TotalMetric), false, nil
+	}
+
+	stepString := model.Duration(step).String()
+	increase := func(selector string) string {
+		return fmt.Sprintf(`sum(increase(%s[%s]))`, selector, stepString)
+	}
+
+	switch {
+	case slo.SuccessMetric != "" && slo.TotalMetric != "":
+		return increase(slo.SuccessMetric), increase(slo.TotalMetric), false, nil
+	case slo.SLIType == models.SLITypeAvailability:
+		return increase(fmt.Sprintf(`http_requests_total{service="%s",status!~"5.."}`, slo.Service.Name)),
+			increase(fmt.Sprintf(`http_requests_total{service="%s"}`, slo.Service.Name)), false, nil
+	case slo.SLIType == models.SLITypeErrorRate:
+		return increase(fmt.Sprintf(`http_requests_total{service="%s",status=~"5.."}`, slo.Service.Name)),
+			increase(fmt.Sprintf(`http_requests_total{service="%s"}`, slo.Service.Name)), true, nil
+	default:
+		return "", "", false, fmt.Errorf("range mode requires success_metric and total_metric for %s SLOs", slo.SLIType)
+	}
+}
+
+// measureRangeSLI computes good / total events over the whole compliance
+// window from per-step range queries summed client-side. The window is
+// aligned to the step and clipped to Prometheus' retention; steps without
+// data are left out and reported through DataCoverage.
+func (ms *MetricsService) measureRangeSLI(ctx context.Context, slo *models.SLO) (*models.SLIMeasurement, error) {
+	step := rangeStep(slo)
+	goodQuery, totalQuery, invert, err := rangeQueries(slo, step)
+	if err != nil {
+		return nil, err
+	}
+
+	end := time.Now().Truncate(step)
+	windowStart := end.AddDate(0, 0, -slo.TimeWindowDays)
+	start := ms.clipToRetention(ctx, windowStart, end, step)
+
+	good, err := ms.sumRangeChunks(ctx, goodQuery, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	total, err := ms.sumRangeChunks(ctx, totalQuery, start, end, step)
+	if err != nil {
+		return nil, err
+	}
+	if len(total) == 0 {
+		return nil, ErrNoData
+	}
+
+	measurement := &models.SLIMeasurement{
+		SLOID:       slo.ID,
+		Value:       1.0, // 100% if no traffic
+		Source:      models.SLISourcePrometheus,
+		Method:      string(slo.BudgetQueryMode),
+		WindowStart: &start,
+		WindowEnd:   &end,
+	}
+	for ts, totalValue := range total {
+		goodValue := good[ts]
+		if invert {
+			goodValue = totalValue - goodValue
+		}
+		measurement.Good += goodValue
+		measurement.Total += totalValue
+	}
+	if measurement.Total > 0 {
+		measurement.Value = measurement.Good / measurement.Total
+	}
+
+	expectedSteps := float64(end.Sub(windowStart) / step)
+	measurement.DataCoverage = float64(len(total)) / expectedSteps * 100
+	return measurement, nil
+}
+
+// sumRangeChunks evaluates a per-step query for every step ending in
+// (start, end], split into chunks no longer than maxRangeChunk or the
+// Prometheus point limit. Each point covers the step ending at its timestamp.
+func (ms *MetricsService) sumRangeChunks(ctx context.Context, query string, start, end time.Time, step time.Duration) (map[time.Time]float64, error) {
+	chunk := step * maxRangeQueryPoints
+	if chunk > maxRangeChunk {
+		chunk = maxRangeChunk
+	}
+	if chunk < step {
+		chunk = step
+	}
+
+	values := make(map[time.Time]float64)
+	for chunkStart := start; chunkStart.Before(end); {
+		chunkEnd := chunkStart.Add(chunk)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		points, err := ms.queryPrometheusRange(ctx, query, chunkStart.Add(step), chunkEnd, step, models.SeriesAggregationSum)
+		if err != nil {
+			return nil, err
+		}
+		for ts, value := range points {
+			values[ts] = value
+		}
+
+		chunkStart = chunkEnd
+	}
+	return values, nil
+}
+
+// clipToRetention moves start forward to the oldest step Prometheus still
+// retains, so no queries are made for data that cannot exist.
+func (ms *MetricsService) clipToRetention(ctx context.Context, start, end time.Time, step time.Duration) time.Time {
+	retention, err := ms.storageRetention(ctx)
+	if err != nil || retention <= 0 {
+		return start
+	}
+
+	oldest := end.Add(-retention).Truncate(step)
+	if oldest.After(start) {
+		if oldest.Before(end) {
+			oldest = oldest.Add(step) // the oldest step may be partially deleted
+		}
+		return oldest
+	}
+	return start
+}
+
+// storageRetention reads Prometheus' time-based retention, e.g. "15d" or
+// "15d or 50GiB". It returns 0 when only size-based retention is set.
+func (ms *MetricsService) storageRetention(ctx context.Context) (time.Duration, error) {
+	info, err := ms.prometheusAPI.Runtimeinfo(ctx)
+	if err != nil {
+		zap.L().Debug("Failed to read Prometheus runtime info", zap.Error(err))
+		return 0, err
+	}
+
+	for _, part := range strings.Split(info.StorageRetention, " or ") {
+		if retention, err := model.ParseDuration(strings.TrimSpace(part)); err == nil {
+			return time.Duration(retention), nil
+		}
+	}
+	return 0, nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"github.com/prometheus/common/model"
+)
+
+func TestMeasureRangeSLI(t *testing.T) {
+	db := newTestDB(t)
+	slo := &models.SLO{ID: 1, SLIType: models.SLITypeAvailability, Target: 0.99, TimeWindowDays: 1,
+		BudgetQueryMode: models.BudgetQueryRange, SuccessMetric: "good_total", TotalMetric: "requests_total"}
+	goodQuery, totalQuery, _, err := rangeQueries(slo, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// The last 12 hourly steps have 100 requests, 99 of them good.
+	last := time.Now().Truncate(time.Hour)
+	series := map[string]model.Matrix{
+		goodQuery:  stepPoints(last, time.Hour, 12, func(int) float64 { return 99 }),
+		totalQuery: stepPoints(last, time.Hour, 12, func(int) float64 { return 100 }),
+	}
+
+	tests := []struct {
+		name      string
+		series    map[string]model.Matrix
+		retention string
+		steps     float64 // steps with data; the oldest retained step is left out
+	}{
+		{"full retention", series, "15d", 12},
+		{"clipped to retention", series, "6h", 5},
+		{"no data", map[string]model.Matrix{}, "15d", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prometheus := &fakePrometheus{series: tt.series, matrix: model.Matrix{}, retention: tt.retention}
+			ms := NewMetricsService(db, "")
+			ms.prometheusAPI = prometheus
+
+			measurement, err := ms.MeasureSLI(slo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.steps == 0 {
+				if !measurement.NoData {
+					t.Errorf("got %+v, want no data", measurement)
+				}
+				return
+			}
+			if measurement.NoData || measurement.Total != tt.steps*100 || measurement.Good != tt.steps*99 {
+				t.Fatalf("good %v of %v (no data %v), want %v of %v", measurement.Good, measurement.Total,
+					measurement.NoData, tt.steps*99, tt.steps*100)
+			}
+			if want := tt.steps / 24 * 100; math.Abs(measurement.DataCoverage-want) > 1e-9 {
+				t.Errorf("coverage %v, want %v", measurement.DataCoverage, want)
+			}
+			for _, r := range prometheus.ranges {
+				if r.Start.Before(*measurement.WindowStart) {
+					t.Errorf("queried from %s, before the window start %s", r.Start, measurement.WindowStart)
+				}
+			}
+		})
+	}
+}
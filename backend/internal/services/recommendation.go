@@ -0,0 +1,217 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"slo-platform/internal/models"
+)
+
+const (
+	defaultBacktestDays         = 90
+	defaultRecommendationWindow = 30
+	defaultMinCompliance        = 0.9
+	recommendationStep          = time.Hour
+
+	// maxHistoryDays keeps an hourly range query under Prometheus's limit
+	// of 11,000 points.
+	maxHistoryDays = 450
+)
+
+// ErrInvalidRecommendation is returned for recommendation requests that
+// cannot be backtested as given.
+var ErrInvalidRecommendation = errors.New("invalid recommendation request")
+
+// TargetRecommendationRequest describes the SLI to backtest, either by
+// referencing an existing SLO or by giving its query/metrics inline.
+type TargetRecommendationRequest struct {
+	SLOID           uint           `json:"slo_id"`
+	SLIType         models.SLIType `json:"sli_type"`
+	PrometheusQuery string         `json:"prometheus_query"`
+	SuccessMetric   string         `json:"success_metric"`
+	TotalMetric     string         `json:"total_metric"`
+	Days            int            `json:"days"`           // default 90, at most 450
+	WindowDays      int            `json:"window_days"`    // default 30, or the SLO's window
+	MinCompliance   float64        `json:"min_compliance"` // default 0.9 of windows must meet the target
+}
+
+// RecommendTarget backtests an SLI over the past days and recommends the
+// strictest candidate target that would have held in enough windows.
+func (s *SLOService) RecommendTarget(serviceID uint, req TargetRecommendationRequest) (*models.TargetRecommendation, error) {
+	var service models.Service
+	if err := s.db.First(&service, serviceID).Error; err != nil {
+		return nil, fmt.Errorf("service not found: %w", err)
+	}
+
+	slo := &models.SLO{
+		ServiceID:       service.ID,
+		Service:         service,
+		SLIType:         req.SLIType,
+		PrometheusQuery: req.PrometheusQuery,
+		SuccessMetric:   req.SuccessMetric,
+		TotalMetric:     req.TotalMetric,
+	}
+	if req.SLOID != 0 {
+		existing, err := s.GetSLO(req.SLOID)
+		if err != nil {
+			return nil, fmt.Errorf("SLO not found: %w", err)
+		}
+		if existing.ServiceID != serviceID {
+			return nil, fmt.Errorf("%w: SLO %d does not belong to service %d", ErrInvalidRecommendation, req.SLOID, serviceID)
+		}
+		slo = existing
+		if req.WindowDays == 0 {
+			req.WindowDays = existing.TimeWindowDays
+		}
+	} else if slo.PrometheusQuery == "" && (slo.SuccessMetric == "" || slo.TotalMetric == "") {
+		return nil, fmt.Errorf("%w: prometheus_query or success_metric and total_metric are required", ErrInvalidRecommendation)
+	}
+
+	if req.Days <= 0 {
+		req.Days = defaultBacktestDays
+	}
+	if req.Days > maxHistoryDays {
+		return nil, fmt.Errorf("%w: days must be at most %d", ErrInvalidRecommendation, maxHistoryDays)
+	}
+	if req.WindowDays <= 0 {
+		req.WindowDays = defaultRecommendationWindow
+	}
+	if req.WindowDays > req.Days {
+		req.WindowDays = req.Days
+	}
+	if req.MinCompliance <= 0 || req.MinCompliance > 1 {
+		req.MinCompliance = defaultMinCompliance
+	}
+
+	end := time.Now().UTC().Truncate(24 * time.Hour)
+	start := end.AddDate(0, 0, -req.Days)
+	history, err := s.metricsService.GetSLIHistory(slo, start, end, recommendationStep)
+	if err != nil {
+		return nil, err
+	}
+
+	windows := rollingWindowSLIs(history, start, req.Days, req.WindowDays)
+	if len(windows) == 0 {
+		return nil, fmt.Errorf("no SLI history in the last %d days", req.Days)
+	}
+
+	recommendation := &models.TargetRecommendation{
+		ServiceID:   serviceID,
+		SLOID:       req.SLOID,
+		Days:        req.Days,
+		WindowDays:  req.WindowDays,
+		Windows:     windows,
+		GeneratedAt: time.Now(),
+	}
+	recommendation.MinSLI, recommendation.MedianSLI = sliSummary(windows)
+
+	for _, target := range models.CandidateTargets {
+		recommendation.Candidates = append(recommendation.Candidates, evaluateCandidate(windows, target))
+	}
+	recommendation.RecommendedTarget, recommendation.Reasoning = recommendTarget(recommendation, req.MinCompliance)
+
+	return recommendation, nil
+}
+
+// rollingWindowSLIs buckets history by day and evaluates every windowDays
+// long window ending on a day boundary. Windows without traffic are skipped.
+func rollingWindowSLIs(history []models.SLISample, start time.Time, days, windowDays int) []models.WindowSLI {
+	good := make([]float64, days)
+	total := make([]float64, days)
+	for _, sample := range history {
+		day := int(sample.Timestamp.Sub(start) / (24 * time.Hour))
+		if day < 0 || day >= days {
+			continue
+		}
+		good[day] += sample.Good
+		total[day] += sample.Total
+	}
+
+	var windows []models.WindowSLI
+	for end := windowDays; end <= days; end++ {
+		var windowGood, windowTotal float64
+		for day := end - windowDays; day < end; day++ {
+			windowGood += good[day]
+			windowTotal += total[day]
+		}
+		if windowTotal == 0 {
+			continue
+		}
+		windows = append(windows, models.WindowSLI{
+			Start:  start.AddDate(0, 0, end-windowDays),
+			End:    start.AddDate(0, 0, end),
+			SLI:    windowGood / windowTotal,
+			Events: windowTotal,
+		})
+	}
+	return windows
+}
+
+func evaluateCandidate(windows []models.WindowSLI, target float64) models.CandidateTarget {
+	candidate := models.CandidateTarget{Target: target}
+	for _, window := range windows {
+		if window.SLI >= target {
+			candidate.WindowsMet++
+		}
+		budgetUse := (1 - window.SLI) / (1 - target)
+		candidate.WorstBudgetUse = math.Max(candidate.WorstBudgetUse, budgetUse)
+	}
+	candidate.ComplianceRate = float64(candidate.WindowsMet) / float64(len(windows)) * 100
+	return candidate
+}
+
+func sliSummary(windows []models.WindowSLI) (float64, float64) {
+	slis := make([]float64, len(windows))
+	for i, window := range windows {
+		slis[i] = window.SLI
+	}
+	sort.Float64s(slis)
+
+	median := slis[len(slis)/2]
+	if len(slis)%2 == 0 {
+		median = (slis[len(slis)/2-1] + slis[len(slis)/2]) / 2
+	}
+	return slis[0], median
+}
+
+func recommendTarget(recommendation *models.TargetRecommendation, minCompliance float64) (float64, string) {
+	threshold := minCompliance * 100
+	windows := len(recommendation.Windows)
+
+	best := -1
+	for i, candidate := range recommendation.Candidates {
+		if candidate.ComplianceRate >= threshold {
+			best = i
+		}
+	}
+
+	if best < 0 {
+		return 0, fmt.Sprintf(
+			"No candidate target was met in at least %.0f%% of %d %d-day windows; the worst window achieved %s and the median %s. Improve reliability or choose a target below %s.",
+			threshold, windows, recommendation.WindowDays,
+			formatTarget(recommendation.MinSLI), formatTarget(recommendation.MedianSLI),
+			formatTarget(recommendation.Candidates[0].Target))
+	}
+
+	chosen := recommendation.Candidates[best]
+	reasoning := fmt.Sprintf(
+		"%s would have been met in %.0f%% of %d %d-day windows (threshold %.0f%%); the worst window used %.0f%% of its error budget.",
+		formatTarget(chosen.Target), chosen.ComplianceRate, windows, recommendation.WindowDays, threshold, chosen.WorstBudgetUse*100)
+
+	if best+1 < len(recommendation.Candidates) {
+		next := recommendation.Candidates[best+1]
+		reasoning += fmt.Sprintf(" The next stricter target, %s, would only have been met in %.0f%% of windows.",
+			formatTarget(next.Target), next.ComplianceRate)
+	} else {
+		reasoning += " This is the strictest candidate; confirm users actually need this level before committing to it."
+	}
+
+	return chosen.Target, reasoning
+}
+
+func formatTarget(target float64) string {
+	return fmt.Sprintf("%.4g%%", target*100)
+}
@@ -0,0 +1,34 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+func TestRecommendTargetRejectsBadRequests(t *testing.T) {
+	db := newTestDB(t)
+	slos := NewSLOService(db, nil, nil, nil)
+	service := createService(t, db, "checkout", "prod")
+	metrics := TargetRecommendationRequest{SuccessMetric: "good_total", TotalMetric: "all_total"}
+
+	tests := []struct {
+		name      string
+		serviceID uint
+		req       TargetRecommendationRequest
+		want      error
+	}{
+		{"unknown service", service.ID + 1, metrics, gorm.ErrRecordNotFound},
+		{"unknown SLO", service.ID, TargetRecommendationRequest{SLOID: 42}, gorm.ErrRecordNotFound},
+		{"no query", service.ID, TargetRecommendationRequest{}, ErrInvalidRecommendation},
+		{"too many days", service.ID, TargetRecommendationRequest{SuccessMetric: "good_total", TotalMetric: "all_total", Days: maxHistoryDays + 1}, ErrInvalidRecommendation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := slos.RecommendTarget(tt.serviceID, tt.req); !errors.Is(err, tt.want) {
+				t.Errorf("got %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,153 @@
+package services
+
+import (
+	"fmt"
+	"math"
+
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// Minimal decoder for the Prometheus remote write 1.0 WriteRequest message.
+// Only labels and float samples are read; metadata, exemplars and native
+// histograms are skipped.
+
+type remoteWriteSeries struct {
+	Labels  map[string]string
+	Samples []remoteWriteSample
+}
+
+type remoteWriteSample struct {
+	Value     float64
+	Timestamp int64 // milliseconds since epoch
+}
+
+// staleNaNBits is the NaN Prometheus writes as a staleness marker when a
+// series disappears.
+const staleNaNBits = 0x7ff0000000000002
+
+func isStaleNaN(value float64) bool {
+	return math.Float64bits(value) == staleNaNBits
+}
+
+// decodeWriteRequest decodes an uncompressed prometheus.WriteRequest.
+func decodeWriteRequest(data []byte) ([]remoteWriteSeries, error) {
+	var series []remoteWriteSeries
+	err := walkMessage(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if num != 1 || typ != protowire.BytesType {
+			return nil
+		}
+		ts, err := decodeTimeSeries(value)
+		if err != nil {
+			return err
+		}
+		series = append(series, ts)
+		return nil
+	})
+	return series, err
+}
+
+func decodeTimeSeries(data []byte) (remoteWriteSeries, error) {
+	series := remoteWriteSeries{Labels: make(map[string]string)}
+	err := walkMessage(data, func(num protowire.Number, typ protowire.Type, value []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			name, labelValue, err := decodeLabel(value)
+			if err != nil {
+				return err
+			}
+			series.Labels[name] = labelValue
+		case 2:
+			sample, err := decodeSample(value)
+			if err != nil {
+				return err
+			}
+			series.Samples = append(series.Samples, sample)
+		}
+		return nil
+	})
+	return series, err
+}
+
+func decodeLabel(data []byte) (string, string, error) {
+	var name, value string
+	err := walkMessage(data, func(num protowire.Number, typ protowire.Type, field []byte) error {
+		if typ != protowire.BytesType {
+			return nil
+		}
+		switch num {
+		case 1:
+			name = string(field)
+		case 2:
+			value = string(field)
+		}
+		return nil
+	})
+	return name, value, err
+}
+
+func decodeSample(data []byte) (remoteWriteSample, error) {
+	var sample remoteWriteSample
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return sample, protowire.ParseError(n)
+		}
+		data = data[n:]
+
+		switch {
+		case num == 1 && typ == protowire.Fixed64Type:
+			bits, n := protowire.ConsumeFixed64(data)
+			if n < 0 {
+				return sample, protowire.ParseError(n)
+			}
+			sample.Value = math.Float64frombits(bits)
+			data = data[n:]
+		case num == 2 && typ == protowire.VarintType:
+			v, n := protowire.ConsumeVarint(data)
+			if n < 0 {
+				return sample, protowire.ParseError(n)
+			}
+			sample.Timestamp = int64(v)
+			data = data[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, data)
+			if n < 0 {
+				return sample, protowire.ParseError(n)
+			}
+			data = data[n:]
+		}
+	}
+	return sample, nil
+}
+
+// walkMessage calls fn for every field of a protobuf message. Length-delimited
+// fields are passed their contents; other fields are skipped over and passed
+// nil.
+func walkMessage(data []byte, fn func(protowire.Number, protowire.Type, []byte) error) error {
+	for len(data) > 0 {
+		num, typ, n := protowire.ConsumeTag(data)
+		if n < 0 {
+			return fmt.Errorf("invalid protobuf: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		var value []byte
+		if typ == protowire.BytesType {
+			value, n = protowire.ConsumeBytes(data)
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, data)
+		}
+		if n < 0 {
+			return fmt.Errorf("invalid protobuf: %w", protowire.ParseError(n))
+		}
+		data = data[n:]
+
+		if err := fn(num, typ, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
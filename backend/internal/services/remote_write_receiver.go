@@ -0,0 +1,105 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"github.com/golang/snappy"
+	"gorm.io/gorm"
+)
+
+// ErrInvalidPayload is returned for request bodies that cannot be decoded.
+var ErrInvalidPayload = errors.New("invalid payload")
+
+type RemoteWriteResult struct {
+	Series          int `json:"series"`
+	Samples         int `json:"samples"`
+	MatchedSeries   int `json:"matched_series"`
+	UnmatchedSeries int `json:"unmatched_series"`
+	Stored          int `json:"stored"`
+	Late            int `json:"late"` // in or before a rolled-up minute, not stored
+}
+
+// RemoteWriteReceiver accepts Prometheus remote write requests and stores
+// increments of the counters named by SLO success and total metrics as
+// MetricIngest rows for the local SLI engine.
+type RemoteWriteReceiver struct {
+	db       *gorm.DB
+	counters *counterTracker
+}
+
+func NewRemoteWriteReceiver(db *gorm.DB) *RemoteWriteReceiver {
+	return &RemoteWriteReceiver{
+		db:       db,
+		counters: newCounterTracker(),
+	}
+}
+
+// HandleWriteRequest decodes a snappy-compressed WriteRequest and stores the
+// samples of every series matching an SLO. NaN samples are skipped; a
+// staleness marker also forgets the series' last value.
+func (r *RemoteWriteReceiver) HandleWriteRequest(compressed []byte) (*RemoteWriteResult, error) {
+	data, err := snappy.Decode(nil, compressed)
+	if err != nil {
+		return nil, fmt.Errorf("%w: snappy: %v", ErrInvalidPayload, err)
+	}
+	series, err := decodeWriteRequest(data)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	targets, err := loadIngestTargets(r.db)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &RemoteWriteResult{Series: len(series)}
+	var rows []models.MetricIngest
+	for _, ts := range series {
+		result.Samples += len(ts.Samples)
+
+		matched := false
+		for _, target := range targets.counters[ts.Labels["__name__"]] {
+			if !matchAll(target.matchers, ts.Labels) {
+				continue
+			}
+			matched = true
+
+			key := fmt.Sprintf("%d/%s/%s", target.slo.ID, target.metricType, seriesKey(ts.Labels))
+			for _, sample := range ts.Samples {
+				if math.IsNaN(sample.Value) {
+					if isStaleNaN(sample.Value) {
+						r.counters.forget(key) // the series ended
+					}
+					continue
+				}
+				timestamp := time.UnixMilli(sample.Timestamp)
+				increase, ok := r.counters.increase(key, timestamp, sample.Value)
+				if !ok {
+					continue
+				}
+				rows = append(rows, ingestRow(target, ts.Labels, timestamp, target.metricType, increase))
+			}
+		}
+
+		if matched {
+			result.MatchedSeries++
+		} else {
+			result.UnmatchedSeries++
+		}
+	}
+
+	if len(rows) > 0 {
+		stored, late, err := insertMetricIngests(r.db, rows)
+		if err != nil {
+			return nil, err
+		}
+		result.Stored = stored
+		result.Late = late
+	}
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"github.com/golang/snappy"
+)
+
+// testdata/remote_write/checkout.snappy is a snappy-compressed WriteRequest
+// with three series of job="checkout", 15s apart from 2024-03-12T09:00:00Z:
+//
+//	http_requests_total          1200, 1260, stale marker (plus an exemplar)
+//	http_requests_success_total  1190, NaN, 1247
+//	up                           1
+//
+// and one metadata entry, which the decoder skips.
+func readWriteRequest(t *testing.T) []byte {
+	t.Helper()
+	compressed, err := os.ReadFile(filepath.Join("testdata", "remote_write", "checkout.snappy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return compressed
+}
+
+func TestDecodeWriteRequest(t *testing.T) {
+	data, err := snappy.Decode(nil, readWriteRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	series, err := decodeWriteRequest(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(series) != 3 {
+		t.Fatalf("decoded %d series, want 3", len(series))
+	}
+
+	total := series[0]
+	if total.Labels["__name__"] != "http_requests_total" || total.Labels["job"] != "checkout" || len(total.Labels) != 3 {
+		t.Errorf("labels = %v", total.Labels)
+	}
+	if len(total.Samples) != 3 {
+		t.Fatalf("got %d samples, want 3", len(total.Samples))
+	}
+	if want := (remoteWriteSample{Value: 1260, Timestamp: 1710234015000}); total.Samples[1] != want {
+		t.Errorf("sample = %+v, want %+v", total.Samples[1], want)
+	}
+	if !isStaleNaN(total.Samples[2].Value) {
+		t.Errorf("last sample %v, want the staleness marker", total.Samples[2].Value)
+	}
+	if success := series[1].Samples[1].Value; !math.IsNaN(success) || isStaleNaN(success) {
+		t.Errorf("success sample %v, want a plain NaN", success)
+	}
+}
+
+func TestDecodeWriteRequestRejectsTruncatedData(t *testing.T) {
+	data, err := snappy.Decode(nil, readWriteRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := decodeWriteRequest(data[:len(data)/2]); err == nil {
+		t.Error("truncated request decoded without error")
+	}
+}
+
+func TestHandleWriteRequest(t *testing.T) {
+	db := newTestDB(t)
+	service := createService(t, db, "checkout", "prod")
+	slo := &models.SLO{ServiceID: service.ID, Name: "availability", SLIType: models.SLITypeAvailability,
+		Target: 0.999, TimeWindowDays: 30, SuccessMetric: "http_requests_success_total",
+		TotalMetric: "http_requests_total", IngestMatchers: `{job="checkout"}`}
+	if err := db.Create(slo).Error; err != nil {
+		t.Fatal(err)
+	}
+	receiver := NewRemoteWriteReceiver(db)
+
+	result, err := receiver.HandleWriteRequest(readWriteRequest(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := RemoteWriteResult{Series: 3, Samples: 7, MatchedSeries: 2, UnmatchedSeries: 1, Stored: 2}
+	if *result != want {
+		t.Errorf("result = %+v, want %+v", *result, want)
+	}
+
+	var rows []models.MetricIngest
+	if err := db.Order("metric_type").Find(&rows).Error; err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].MetricType != "success" || rows[0].Value != 57 || rows[1].MetricType != "total" || rows[1].Value != 60 {
+		t.Fatalf("stored %+v; want success 57 across the NaN and total 60", rows)
+	}
+
+	// The stale marker dropped the total series from the tracker, so its
+	// next sample only primes it again.
+	for key := range receiver.counters.last {
+		if strings.Contains(key, "/total/") {
+			t.Errorf("still tracking %s after its stale marker", key)
+		}
+	}
+	if len(receiver.counters.last) != 1 {
+		t.Errorf("tracking %d series, want only the success series", len(receiver.counters.last))
+	}
+}
+
+func TestCounterTrackerEvictsIdleSeries(t *testing.T) {
+	now := time.Date(2024, 3, 12, 9, 0, 0, 0, time.UTC)
+	tracker := newCounterTracker()
+	tracker.now = func() time.Time { return now }
+
+	tracker.increase("idle", now, 10)
+	tracker.increase("busy", now, 10)
+	for i := 1; i <= 4; i++ {
+		now = now.Add(counterTTL / 4)
+		if _, ok := tracker.increase("busy", now, float64(10+i)); !ok {
+			t.Fatalf("busy series lost its previous sample at step %d", i)
+		}
+	}
+
+	if _, ok := tracker.last["idle"]; ok {
+		t.Error("idle series still tracked after the TTL")
+	}
+	if len(tracker.last) != 1 {
+		t.Errorf("tracking %d series, want 1", len(tracker.last))
+	}
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"slo-platform/internal/database"
+	"slo-platform/internal/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	minuteResolution = 60
+	hourResolution   = 3600
+	rollupChunkSize  = 5000
+)
+
+// RetentionPolicy sets how long each tier of ingested metric data is kept.
+// Raw samples older than Raw are rolled into 1m buckets, 1m buckets older
+// than Minute into 1h buckets, and 1h buckets older than Hour are deleted.
+type RetentionPolicy struct {
+	Raw      time.Duration
+	Minute   time.Duration
+	Hour     time.Duration
+	Interval time.Duration // how often the manager runs
+}
+
+type RetentionResult struct {
+	RolledRaw         int `json:"rolled_raw"`
+	RolledMinute      int `json:"rolled_minute"`
+	DeletedHour       int `json:"deleted_hour"`
+	DroppedPartitions int `json:"dropped_partitions"`
+}
+
+// RetentionManager downsamples and expires MetricIngest data. Each sample
+// lives in exactly one tier at a time: rows are deleted in the same
+// transaction that adds them to the coarser tier, so readers summing all
+// tiers never count a sample twice.
+type RetentionManager struct {
+	db     *gorm.DB
+	policy RetentionPolicy
+}
+
+func NewRetentionManager(db *gorm.DB, policy RetentionPolicy) (*RetentionManager, error) {
+	if policy.Raw <= 0 || policy.Minute < policy.Raw || policy.Hour < policy.Minute {
+		return nil, fmt.Errorf("retention must satisfy 0 < raw (%s) <= minute (%s) <= hour (%s)", policy.Raw, policy.Minute, policy.Hour)
+	}
+	if policy.Interval <= 0 {
+		policy.Interval = 10 * time.Minute
+	}
+	return &RetentionManager{db: db, policy: policy}, nil
+}
+
+// Start runs the manager in the background every policy interval.
+func (rm *RetentionManager) Start() {
+	go func() {
+		ticker := time.NewTicker(rm.policy.Interval)
+		defer ticker.Stop()
+		for {
+			result, err := rm.Run(time.Now())
+			if err != nil {
+				zap.L().Error("Metric retention failed", zap.Error(err))
+			} else {
+				zap.L().Debug("Metric retention complete",
+					zap.Int("rolled_raw", result.RolledRaw),
+					zap.Int("rolled_minute", result.RolledMinute),
+					zap.Int("deleted_hour", result.DeletedHour),
+					zap.Int("dropped_partitions", result.DroppedPartitions))
+			}
+			<-ticker.C
+		}
+	}()
+}
+
+// Run applies the retention policy once as of now.
+func (rm *RetentionManager) Run(now time.Time) (*RetentionResult, error) {
+	result := &RetentionResult{}
+
+	if err := database.EnsurePartitions(rm.db, now, now.AddDate(0, 0, 7)); err != nil {
+		zap.L().Warn("Failed to create metric_ingests partitions", zap.Error(err))
+	}
+
+	rawCutoff := now.Add(-rm.policy.Raw).Truncate(time.Minute)
+	rolled, err := rm.rollupRaw(rawCutoff)
+	result.RolledRaw = rolled
+	if err != nil {
+		return result, err
+	}
+	if result.DroppedPartitions, err = database.DropPartitionsBefore(rm.db, rawCutoff); err != nil {
+		return result, err
+	}
+
+	minuteCutoff := now.Add(-rm.policy.Minute).Truncate(time.Hour)
+	if result.RolledMinute, err = rm.rollupMinutes(minuteCutoff); err != nil {
+		return result, err
+	}
+
+	deleted := rm.db.Where("resolution = ? AND timestamp < ?", hourResolution, now.Add(-rm.policy.Hour)).
+		Delete(&models.MetricRollup{})
+	result.DeletedHour = int(deleted.RowsAffected)
+	return result, deleted.Error
+}
+
+// rollupWatermark returns the end of the SLO's newest rollup bucket. Raw
+// samples before it may already be part of a rollup.
+func rollupWatermark(db *gorm.DB, sloID uint) (time.Time, error) {
+	var watermark time.Time
+	for _, resolution := range []int{minuteResolution, hourResolution} {
+		var rollup models.MetricRollup
+		err := db.Select("timestamp").Where("slo_id = ? AND resolution = ?", sloID, resolution).
+			Order("timestamp DESC").Limit(1).Find(&rollup).Error
+		if err != nil {
+			return time.Time{}, err
+		}
+		if end := rollup.Timestamp.Add(time.Duration(resolution) * time.Second); !rollup.Timestamp.IsZero() && end.After(watermark) {
+			watermark = end
+		}
+	}
+	return watermark, nil
+}
+
+type rollupKey struct {
+	sloID      uint
+	timestamp  time.Time
+	metricType string
+}
+
+// rollupRaw moves raw samples older than cutoff into 1m rollups.
+func (rm *RetentionManager) rollupRaw(cutoff time.Time) (int, error) {
+	thresholds, err := rm.latencyThresholds()
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for {
+		var rows []models.MetricIngest
+		err := rm.db.Where("timestamp < ?", cutoff).Order("id").Limit(rollupChunkSize).Find(&rows).Error
+		if err != nil || len(rows) == 0 {
+			return total, err
+		}
+
+		rollups := make(map[rollupKey]*models.MetricRollup)
+		ids := make([]uint, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+			key := rollupKey{row.SLOID, row.Timestamp.UTC().Truncate(time.Minute), row.MetricType}
+			rollup, ok := rollups[key]
+			if !ok {
+				rollup = &models.MetricRollup{
+					ServiceID:  row.ServiceID,
+					SLOID:      row.SLOID,
+					Resolution: minuteResolution,
+					Timestamp:  key.timestamp,
+					MetricType: row.MetricType,
+				}
+				rollups[key] = rollup
+			}
+			rollup.Value += row.Value
+			rollup.Count++
+			if row.MetricType == "latency" && thresholds[row.SLOID] > 0 && row.Value <= thresholds[row.SLOID] {
+				rollup.Good++
+			}
+		}
+
+		err = rm.db.Transaction(func(tx *gorm.DB) error {
+			if err := upsertRollups(tx, rollups); err != nil {
+				return err
+			}
+			// The timestamp bound lets Postgres prune partitions.
+			return tx.Where("id IN ? AND timestamp < ?", ids, cutoff).Delete(&models.MetricIngest{}).Error
+		})
+		if err != nil {
+			return total, err
+		}
+		total += len(rows)
+		if len(rows) < rollupChunkSize {
+			return total, nil
+		}
+	}
+}
+
+// rollupMinutes moves 1m rollups older than cutoff into 1h rollups.
+func (rm *RetentionManager) rollupMinutes(cutoff time.Time) (int, error) {
+	total := 0
+	for {
+		var rows []models.MetricRollup
+		err := rm.db.Where("resolution = ? AND timestamp < ?", minuteResolution, cutoff).
+			Order("id").Limit(rollupChunkSize).Find(&rows).Error
+		if err != nil || len(rows) == 0 {
+			return total, err
+		}
+
+		rollups := make(map[rollupKey]*models.MetricRollup)
+		ids := make([]uint, 0, len(rows))
+		for _, row := range rows {
+			ids = append(ids, row.ID)
+			key := rollupKey{row.SLOID, row.Timestamp.UTC().Truncate(time.Hour), row.MetricType}
+			rollup, ok := rollups[key]
+			if !ok {
+				rollup = &models.MetricRollup{
+					ServiceID:  row.ServiceID,
+					SLOID:      row.SLOID,
+					Resolution: hourResolution,
+					Timestamp:  key.timestamp,
+					MetricType: row.MetricType,
+				}
+				rollups[key] = rollup
+			}
+			rollup.Value += row.Value
+			rollup.Count += row.Count
+			rollup.Good += row.Good
+		}
+
+		err = rm.db.Transaction(func(tx *gorm.DB) error {
+			if err := upsertRollups(tx, rollups); err != nil {
+				return err
+			}
+			return tx.Where("id IN ?", ids).Delete(&models.MetricRollup{}).Error
+		})
+		if err != nil {
+			return total, err
+		}
+		total += len(rows)
+		if len(rows) < rollupChunkSize {
+			return total, nil
+		}
+	}
+}
+
+// upsertRollups adds the rollups to existing buckets, which late samples or
+// an earlier partial run may already have created.
+func upsertRollups(tx *gorm.DB, rollups map[rollupKey]*models.MetricRollup) error {
+	rows := make([]models.MetricRollup, 0, len(rollups))
+	for _, rollup := range rollups {
+		rows = append(rows, *rollup)
+	}
+	return tx.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "slo_id"}, {Name: "resolution"}, {Name: "timestamp"}, {Name: "metric_type"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"value":      gorm.Expr("metric_rollups.value + excluded.value"),
+			"count":      gorm.Expr("metric_rollups.count + excluded.count"),
+			"good":       gorm.Expr("metric_rollups.good + excluded.good"),
+			"updated_at": gorm.Expr("excluded.updated_at"),
+		}),
+	}).CreateInBatches(rows, ingestBatchSize).Error
+}
+
+// latencyThresholds maps SLO IDs to their latency threshold, including
+// deleted SLOs whose samples have not expired yet.
+func (rm *RetentionManager) latencyThresholds() (map[uint]float64, error) {
+	var slos []models.SLO
+	if err := rm.db.Unscoped().Select("id", "latency_threshold").Where("latency_threshold > 0").Find(&slos).Error; err != nil {
+		return nil, err
+	}
+	thresholds := make(map[uint]float64, len(slos))
+	for _, slo := range slos {
+		thresholds[slo.ID] = slo.LatencyThreshold
+	}
+	return thresholds, nil
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"slo-platform/internal/models"
+)
+
+func TestResentSamplesAreNotCountedTwiceAfterRollup(t *testing.T) {
+	db := newTestDB(t)
+	service := createService(t, db, "checkout", "prod")
+	manager, err := NewRetentionManager(db, RetentionPolicy{Raw: time.Hour, Minute: 24 * time.Hour, Hour: 90 * 24 * time.Hour})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Date(2024, 3, 12, 12, 0, 30, 0, time.UTC)
+	sample := func(ago time.Duration, value float64) models.MetricIngest {
+		return models.MetricIngest{ServiceID: service.ID, SLOID: 1, Timestamp: now.Add(-ago), Value: value, MetricType: "total"}
+	}
+	old := []models.MetricIngest{sample(2*time.Hour, 10), sample(2*time.Hour-20*time.Second, 5)}
+	if stored, _, err := insertMetricIngests(db, old); err != nil || stored != 2 {
+		t.Fatalf("stored %d, %v; want 2", stored, err)
+	}
+
+	result, err := manager.Run(now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.RolledRaw != 2 {
+		t.Fatalf("rolled %d raw samples, want 2", result.RolledRaw)
+	}
+
+	tests := []struct {
+		name   string
+		rows   []models.MetricIngest
+		stored int
+		late   int
+	}{
+		{"resent after rollup", old, 0, 2},
+		{"late sample in a rolled-up minute", []models.MetricIngest{sample(2*time.Hour-10*time.Second, 1)}, 0, 1},
+		{"sample after the newest rollup", []models.MetricIngest{sample(90*time.Minute, 7)}, 1, 0},
+		{"recent sample", []models.MetricIngest{sample(time.Minute, 3)}, 1, 0},
+		{"recent sample resent", []models.MetricIngest{sample(time.Minute, 3)}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stored, late, err := insertMetricIngests(db, tt.rows)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored != tt.stored || late != tt.late {
+				t.Errorf("stored %d and %d late, want %d and %d", stored, late, tt.stored, tt.late)
+			}
+		})
+	}
+
+	var rolled models.MetricRollup
+	if err := db.Where("slo_id = 1 AND resolution = ?", minuteResolution).First(&rolled).Error; err != nil {
+		t.Fatal(err)
+	}
+	if rolled.Value != 15 || rolled.Count != 2 {
+		t.Errorf("rollup value %v from %d samples, want 15 from 2", rolled.Value, rolled.Count)
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"io"
+	"strconv"
+	"time"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+var (
+	httpRequests = newCounterVec("slo_platform_http_requests_total",
+		"HTTP requests handled, by method, route and status code.",
+		"method", "route", "code")
+	httpRequestDuration = newHistogramVec("slo_platform_http_request_duration_seconds",
+		"Time to handle an HTTP request, by method and route.",
+		[]float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		"method", "route")
+
+	prometheusQueries = newCounterVec("slo_platform_prometheus_queries_total",
+		"Queries sent to Prometheus, by API endpoint.",
+		"endpoint")
+	prometheusQueryErrors = newCounterVec("slo_platform_prometheus_query_errors_total",
+		"Queries to Prometheus that failed, by API endpoint.",
+		"endpoint")
+	prometheusQueryDuration = newHistogramVec("slo_platform_prometheus_query_duration_seconds",
+		"Time Prometheus took to answer a query, by API endpoint.",
+		[]float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10},
+		"endpoint")
+
+	evaluationDuration = newHistogramVec("slo_platform_evaluation_duration_seconds",
+		"Time to evaluate every SLO once.",
+		[]float64{.1, .5, 1, 2.5, 5, 10, 30, 60, 120, 300})
+	evaluatedSLOs = newCounterVec("slo_platform_evaluation_slos_total",
+		"SLOs evaluated, by result (evaluated or failed).",
+		"result")
+	lastEvaluation = &gaugeValue{name: "slo_platform_evaluation_last_success_timestamp_seconds",
+		help: "Unix time the last evaluation finished without error."}
+)
+
+// ObserveHTTPRequest records a handled request. route is the route pattern,
+// such as /api/v1/slos/:id, so that IDs do not each get their own series.
+func ObserveHTTPRequest(method, route string, code int, elapsed time.Duration) {
+	httpRequests.inc(method, route, strconv.Itoa(code))
+	httpRequestDuration.observe(elapsed.Seconds(), method, route)
+}
+
+// instrumentedAPI records the latency and failures of the queries sent to
+// Prometheus.
+type instrumentedAPI struct {
+	v1.API
+}
+
+func (a instrumentedAPI) Query(ctx context.Context, query string, ts time.Time, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	start := time.Now()
+	value, warnings, err := a.API.Query(ctx, query, ts, opts...)
+	observePrometheusQuery("query", start, err)
+	return value, warnings, err
+}
+
+func (a instrumentedAPI) QueryRange(ctx context.Context, query string, r v1.Range, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	start := time.Now()
+	value, warnings, err := a.API.QueryRange(ctx, query, r, opts...)
+	observePrometheusQuery("query_range", start, err)
+	return value, warnings, err
+}
+
+func observePrometheusQuery(endpoint string, start time.Time, err error) {
+	prometheusQueries.inc(endpoint)
+	prometheusQueryDuration.observe(time.Since(start).Seconds(), endpoint)
+	if err != nil {
+		prometheusQueryErrors.inc(endpoint)
+	}
+}
+
+type sloMetricRow struct {
+	SLOID           uint
+	SLOName         string
+	ServiceName     string
+	Environment     string
+	OwnerTeam       string
+	Status          string
+	CurrentSLI      float64
+	Target          float64
+	RemainingBudget float64
+	CurrentBurnRate float64
+	FastBurnRate    float64
+	SlowBurnRate    float64
+	EvaluatedAt     time.Time
+}
+
+// WriteMetrics writes the platform's own metrics and a set of gauges per
+// evaluated SLO in the Prometheus text format. The SLO gauges come from the
+// evaluator's snapshots, so a scrape does not query Prometheus. SLOs without
+// data only report their status.
+func (s *SLOService) WriteMetrics(w io.Writer) error {
+	var rows []sloMetricRow
+	err := s.overviewQuery(OverviewFilter{}).
+		Select(`slos.id AS slo_id, slos.name AS slo_name, services.name AS service_name,
+			services.environment, services.owner_team,
+			slo_snapshots.status, slo_snapshots.current_sli, slo_snapshots.target,
+			slo_snapshots.remaining_budget, slo_snapshots.current_burn_rate,
+			slo_snapshots.fast_burn_rate, slo_snapshots.slow_burn_rate,
+			slo_snapshots.evaluated_at`).
+		Where("slo_snapshots.slo_id IS NOT NULL").
+		Order("slos.id").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	target := metricFamily{name: "slo_platform_slo_target", kind: "gauge",
+		help: "Objective of the SLO, as a ratio."}
+	sli := metricFamily{name: "slo_platform_slo_sli", kind: "gauge",
+		help: "Current SLI over the SLO's compliance window, as a ratio."}
+	budget := metricFamily{name: "slo_platform_slo_error_budget_remaining_ratio", kind: "gauge",
+		help: "Share of the error budget left; negative once it is overspent."}
+	burnRate := metricFamily{name: "slo_platform_slo_burn_rate", kind: "gauge",
+		help: "Error budget burn rate over the window; 1 spends the budget exactly over the compliance window."}
+	status := metricFamily{name: "slo_platform_slo_status", kind: "gauge",
+		help: "1 for the SLO's current status, 0 for the others."}
+	evaluatedAt := metricFamily{name: "slo_platform_slo_evaluated_timestamp_seconds", kind: "gauge",
+		help: "Unix time the SLO was last evaluated."}
+
+	for _, row := range rows {
+		labels := []string{
+			"slo_id", strconv.FormatUint(uint64(row.SLOID), 10),
+			"slo", row.SLOName,
+			"service", row.ServiceName,
+			"environment", row.Environment,
+			"owner_team", row.OwnerTeam,
+		}
+		with := func(name, value string) []string {
+			return append(append([]string{}, labels...), name, value)
+		}
+
+		target.samples = append(target.samples, sample{labels: labels, value: row.Target})
+		for _, name := range sortedKeys(sloStatuses) {
+			value := 0.0
+			if row.Status == name {
+				value = 1
+			}
+			status.samples = append(status.samples, sample{labels: with("status", name), value: value})
+		}
+		evaluatedAt.samples = append(evaluatedAt.samples, sample{labels: labels, value: float64(row.EvaluatedAt.Unix())})
+		if row.Status == "no_data" {
+			continue
+		}
+
+		sli.samples = append(sli.samples, sample{labels: labels, value: row.CurrentSLI})
+		budget.samples = append(budget.samples, sample{labels: labels, value: row.RemainingBudget / 100})
+		burnRate.samples = append(burnRate.samples,
+			sample{labels: with("window", "5m"), value: row.FastBurnRate},
+			sample{labels: with("window", "1h"), value: row.CurrentBurnRate},
+			sample{labels: with("window", "6h"), value: row.SlowBurnRate},
+		)
+	}
+
+	return writeFamilies(w, []metricFamily{
+		httpRequests.family(),
+		httpRequestDuration.family(),
+		prometheusQueries.family(),
+		prometheusQueryErrors.family(),
+		prometheusQueryDuration.family(),
+		evaluationDuration.family(),
+		evaluatedSLOs.family(),
+		lastEvaluation.family(),
+		target, sli, budget, burnRate, status, evaluatedAt,
+	})
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const ingestBatchSize = 500
+
+// ingestTarget is an SLO counter that pushed series can feed.
+type ingestTarget struct {
+	slo        models.SLO
+	metricType string // "success", "total" or "latency"
+	matchers   []labelMatcher
+}
+
+// ingestTargets indexes the SLOs that pushed metrics can feed by metric name:
+// counters maps SuccessMetric and TotalMetric, histograms maps the
+// LatencyMetric of latency SLOs.
+type ingestTargets struct {
+	counters   map[string][]ingestTarget
+	histograms map[string][]ingestTarget
+}
+
+// loadIngestTargets returns the SLOs that pushed metrics can feed. SLOs
+// without ingest_matchers match series whose service label is the SLO's
+// service name, and whose environment label is the service's environment
+// when the name is registered in several environments.
+func loadIngestTargets(db *gorm.DB) (*ingestTargets, error) {
+	var slos []models.SLO
+	err := db.Preload("Service").
+		Where("success_metric <> '' OR total_metric <> '' OR (sli_type = ? AND latency_threshold > 0)", models.SLITypeLatency).
+		Find(&slos).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var sharedNames []string
+	err = db.Model(&models.Service{}).Group("name").Having("COUNT(*) > 1").Pluck("name", &sharedNames).Error
+	if err != nil {
+		return nil, err
+	}
+	shared := make(map[string]bool, len(sharedNames))
+	for _, name := range sharedNames {
+		shared[name] = true
+	}
+
+	targets := &ingestTargets{
+		counters:   make(map[string][]ingestTarget),
+		histograms: make(map[string][]ingestTarget),
+	}
+	for _, slo := range slos {
+		matchers, err := ingestMatchers(&slo, shared[slo.Service.Name])
+		if err != nil {
+			zap.L().Warn("Skipping SLO with invalid ingest_matchers", zap.Uint("slo_id", slo.ID), zap.Error(err))
+			continue
+		}
+		if slo.SuccessMetric != "" {
+			targets.counters[slo.SuccessMetric] = append(targets.counters[slo.SuccessMetric], ingestTarget{slo: slo, metricType: "success", matchers: matchers})
+		}
+		if slo.TotalMetric != "" && slo.TotalMetric != slo.SuccessMetric {
+			targets.counters[slo.TotalMetric] = append(targets.counters[slo.TotalMetric], ingestTarget{slo: slo, metricType: "total", matchers: matchers})
+		}
+		if slo.SLIType == models.SLITypeLatency && slo.LatencyThreshold > 0 {
+			metric := slo.LatencyMetric
+			if metric == "" {
+				metric = defaultLatencyMetric
+			}
+			targets.histograms[metric] = append(targets.histograms[metric], ingestTarget{slo: slo, metricType: "latency", matchers: matchers})
+		}
+	}
+	return targets, nil
+}
+
+func ingestMatchers(slo *models.SLO, sharedName bool) ([]labelMatcher, error) {
+	if strings.TrimSpace(slo.IngestMatchers) == "" {
+		matchers := []labelMatcher{{name: "service", op: "=", value: slo.Service.Name}}
+		if sharedName {
+			matchers = append(matchers, labelMatcher{name: "environment", op: "=", value: slo.Service.Environment})
+		}
+		return matchers, nil
+	}
+	return parseLabelMatchers(slo.IngestMatchers)
+}
+
+func ingestRow(target ingestTarget, labels map[string]string, timestamp time.Time, metricType string, value float64) models.MetricIngest {
+	return models.MetricIngest{
+		ServiceID:  target.slo.ServiceID,
+		SLOID:      target.slo.ID,
+		Timestamp:  timestamp,
+		Value:      value,
+		MetricType: metricType,
+		Series:     seriesID(labels),
+	}
+}
+
+type counterPoint struct {
+	timestamp time.Time
+	value     float64
+	seen      time.Time // when the sample arrived, for eviction
+}
+
+// counterTTL is how long a series may go without samples before its
+// tracker entry is dropped. Its next sample then only primes the tracker
+// again.
+const counterTTL = time.Hour
+
+// counterTracker converts cumulative counter samples into increments per
+// series. The first sample of a series only primes the tracker; a value lower
+// than the previous one is treated as a counter reset.
+type counterTracker struct {
+	mu    sync.Mutex
+	last  map[string]counterPoint
+	now   func() time.Time
+	swept time.Time
+}
+
+func newCounterTracker() *counterTracker {
+	return &counterTracker{last: make(map[string]counterPoint), now: time.Now}
+}
+
+func (ct *counterTracker) increase(key string, timestamp time.Time, value float64) (float64, bool) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+
+	now := ct.now()
+	ct.evictIdle(now)
+
+	previous, seen := ct.last[key]
+	if seen && !timestamp.After(previous.timestamp) {
+		return 0, false // duplicate or out of order
+	}
+	ct.last[key] = counterPoint{timestamp: timestamp, value: value, seen: now}
+	if !seen {
+		return 0, false
+	}
+	if value < previous.value {
+		return value, true
+	}
+	return value - previous.value, true
+}
+
+// forget drops a series, for example once it is marked stale.
+func (ct *counterTracker) forget(key string) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	delete(ct.last, key)
+}
+
+// evictIdle drops series without samples for counterTTL, at most once a
+// minute so that the scan stays cheap.
+func (ct *counterTracker) evictIdle(now time.Time) {
+	if now.Sub(ct.swept) < time.Minute {
+		return
+	}
+	ct.swept = now
+	for key, point := range ct.last {
+		if now.Sub(point.seen) >= counterTTL {
+			delete(ct.last, key)
+		}
+	}
+}
+
+// seriesID is a short hash of a series' labels, stored with its samples.
+func seriesID(labels map[string]string) string {
+	h := fnv.New64a()
+	h.Write([]byte(seriesKey(labels)))
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// seriesKey identifies a series by its sorted labels.
+func seriesKey(labels map[string]string) string {
+	names := make([]string, 0, len(labels))
+	for name := range labels {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(labels[name])
+		b.WriteByte(',')
+	}
+	return b.String()
+}
+
+// measureIngestedEvents computes a request-based SLI from ingested success
+// and total increments over the SLO window, whether they were posted to
+// /metrics/ingest or pushed through a receiver. It returns nil when no total
+// samples were ingested.
+func (ms *MetricsService) measureIngestedEvents(slo *models.SLO) (*models.SLIMeasurement, error) {
+	since := time.Now().AddDate(0, 0, -slo.TimeWindowDays)
+	sums, err := ingestedSums(ms.db, slo.ID, []string{"success", "total"}, since, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+
+	measurement := &models.SLIMeasurement{
+		SLOID:  slo.ID,
+		Value:  1.0, // 100% if no traffic
+		Source: models.SLISourceIngested,
+		Method: "events",
+	}
+	if sums["total"].Count == 0 {
+		return nil, nil
+	}
+	measurement.Good = sums["success"].Sum
+	measurement.Total = sums["total"].Sum
+	if measurement.Total > 0 {
+		measurement.Value = measurement.Good / measurement.Total
+	}
+	return measurement, nil
+}
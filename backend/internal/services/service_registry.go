@@ -1,6 +1,10 @@
 package services
 
 import (
+	"errors"
+	"fmt"
+	"time"
+
 	"slo-platform/internal/models"
 
 	"gorm.io/gorm"
@@ -15,6 +19,9 @@ func NewServiceRegistry(db *gorm.DB) *ServiceRegistry {
 }
 
 func (sr *ServiceRegistry) CreateService(service *models.Service) error {
+	if err := sr.validateService(service); err != nil {
+		return err
+	}
 	return sr.db.Create(service).Error
 }
 
@@ -36,12 +43,148 @@ func (sr *ServiceRegistry) ListServices() ([]models.Service, error) {
 	return services, err
 }
 
-func (sr *ServiceRegistry) UpdateService(service *models.Service) error {
-	return sr.db.Save(service).Error
+// UpdateService replaces the service's fields with those of service. If
+// revisions are given, the service's current revision must be one of them.
+func (sr *ServiceRegistry) UpdateService(id uint, service *models.Service, revisions ...string) (*models.Service, error) {
+	return sr.updateService(id, revisions, func(current *models.Service) error {
+		*current = *service
+		return nil
+	})
+}
+
+// PatchService applies a JSON merge patch to the service.
+func (sr *ServiceRegistry) PatchService(id uint, patch []byte, revisions ...string) (*models.Service, error) {
+	return sr.updateService(id, revisions, func(current *models.Service) error {
+		return mergePatch(current, patch)
+	})
+}
+
+func (sr *ServiceRegistry) updateService(id uint, revisions []string, apply func(*models.Service) error) (*models.Service, error) {
+	var service models.Service
+	if err := sr.db.First(&service, id).Error; err != nil {
+		return nil, err
+	}
+	if err := checkRevision(service.UpdatedAt, revisions); err != nil {
+		return nil, err
+	}
+
+	createdAt, updatedAt := service.CreatedAt, service.UpdatedAt
+	if err := apply(&service); err != nil {
+		return nil, err
+	}
+	service.ID, service.CreatedAt = id, createdAt
+	service.SLOs, service.Dependencies = nil, nil
+	if err := sr.validateService(&service); err != nil {
+		return nil, err
+	}
+	if err := saveRevision(sr.db, &service, updatedAt); err != nil {
+		return nil, err
+	}
+	return sr.GetService(id)
+}
+
+// validateService runs ValidateService, checks that the environment is
+// registered and that no other live service has the same name in it.
+func (sr *ServiceRegistry) validateService(service *models.Service) error {
+	errs := serviceFieldErrors(service)
+	if service.Environment != "" {
+		if err := validateEnvironment(sr.db, service.Environment); errors.Is(err, ErrUnknownEnvironment) {
+			errs.add("environment", "%v", err)
+		} else if err != nil {
+			return err
+		}
+	}
+	if err := errs.err(); err != nil {
+		return err
+	}
+
+	var count int64
+	err := sr.db.Model(&models.Service{}).
+		Where("name = ? AND environment = ? AND id <> ?", service.Name, service.Environment, service.ID).
+		Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w: %s in %s", ErrServiceExists, service.Name, service.Environment)
+	}
+	return nil
 }
 
+// DeleteService soft-deletes the service together with its SLOs and the
+// dependencies on either side of it, and resolves the SLOs' open breach
+// incidents. They all get the same deletion time, so
+// RestoreService brings back exactly what was deleted with the service.
 func (sr *ServiceRegistry) DeleteService(id uint) error {
-	return sr.db.Delete(&models.Service{}, id).Error
+	return sr.db.Transaction(func(tx *gorm.DB) error {
+		var service models.Service
+		if err := tx.First(&service, id).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		err := tx.Model(&models.Incident{}).
+			Where("slo_id IN (?) AND source = ? AND status <> ?",
+				tx.Model(&models.SLO{}).Select("id").Where("service_id = ?", id),
+				models.IncidentSourceSLOBreach, models.IncidentStatusResolved).
+			Updates(map[string]interface{}{"status": models.IncidentStatusResolved, "resolved_at": now}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&models.SLO{}).Where("service_id = ?", id).Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.ServiceDependency{}).
+			Where("service_id = ? OR depends_on_id = ?", id, id).
+			Update("deleted_at", now).Error; err != nil {
+			return err
+		}
+		return tx.Model(&service).Update("deleted_at", now).Error
+	})
+}
+
+// RestoreService undoes DeleteService. Dependencies on services that are
+// still deleted stay deleted until those services are restored too.
+func (sr *ServiceRegistry) RestoreService(id uint) (*models.Service, error) {
+	err := sr.db.Transaction(func(tx *gorm.DB) error {
+		var service models.Service
+		if err := tx.Unscoped().First(&service, id).Error; err != nil {
+			return err
+		}
+		if !service.DeletedAt.Valid {
+			return nil
+		}
+
+		var count int64
+		err := tx.Model(&models.Service{}).
+			Where("name = ? AND environment = ?", service.Name, service.Environment).
+			Count(&count).Error
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: %s in %s", ErrServiceExists, service.Name, service.Environment)
+		}
+
+		deletedAt := service.DeletedAt.Time
+		if err := tx.Unscoped().Model(&service).Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.SLO{}).
+			Where("service_id = ? AND deleted_at = ?", id, deletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		deleted := tx.Unscoped().Model(&models.Service{}).Select("id").Where("deleted_at IS NOT NULL")
+		return tx.Unscoped().Model(&models.ServiceDependency{}).
+			Where("(service_id = ? OR depends_on_id = ?) AND deleted_at = ?", id, id, deletedAt).
+			Where("service_id NOT IN (?) AND depends_on_id NOT IN (?)", deleted, deleted).
+			Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return sr.GetService(id)
 }
 
 func (sr *ServiceRegistry) AddDependency(serviceID, dependsOnID uint, depType string, critical bool) error {
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"slo-platform/internal/models"
+)
+
+const (
+	defaultSimulationDays = 30
+	defaultDeployInterval = time.Hour
+	simulationStep        = time.Hour
+)
+
+// ErrInvalidSimulation is returned for simulation requests that cannot be
+// replayed as given.
+var ErrInvalidSimulation = errors.New("invalid simulation request")
+
+// SimulationRequest describes a proposed SLO configuration and deploy policy.
+// Unset fields keep the SLO's current value.
+type SimulationRequest struct {
+	Days                  int                  `json:"days"`                    // history to replay, default 30; with the longest window at most 450
+	DeployIntervalMinutes int                  `json:"deploy_interval_minutes"` // deploy check cadence, default 60
+	DeployTimes           []time.Time          `json:"deploy_times"`            // overrides the cadence with real deploys
+	Target                *float64             `json:"target"`
+	TimeWindowDays        *int                 `json:"time_window_days"`
+	FastBurnThreshold     *float64             `json:"fast_burn_threshold"`
+	SlowBurnThreshold     *float64             `json:"slow_burn_threshold"`
+	Policy                *models.DeployPolicy `json:"policy"`
+}
+
+// SimulatePolicy replays the SLO's SLI history through its current
+// configuration and a proposed one and reports the difference in deploy
+// decisions, budget exhaustion and burn rate alerts.
+func (s *SLOService) SimulatePolicy(sloID uint, req SimulationRequest) (*models.SimulationResult, error) {
+	slo, err := s.GetSLO(sloID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Days <= 0 {
+		req.Days = defaultSimulationDays
+	}
+	interval := time.Duration(req.DeployIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = defaultDeployInterval
+	}
+
+	current := models.SimulationOutcome{
+		Target:            slo.Target,
+		TimeWindowDays:    slo.TimeWindowDays,
+		FastBurnThreshold: slo.FastBurnThreshold,
+		SlowBurnThreshold: slo.SlowBurnThreshold,
+		Policy:            models.DefaultDeployPolicy,
+	}
+	proposed := current
+	if req.Target != nil {
+		proposed.Target = *req.Target
+	}
+	if req.TimeWindowDays != nil {
+		proposed.TimeWindowDays = *req.TimeWindowDays
+	}
+	if req.FastBurnThreshold != nil {
+		proposed.FastBurnThreshold = *req.FastBurnThreshold
+	}
+	if req.SlowBurnThreshold != nil {
+		proposed.SlowBurnThreshold = *req.SlowBurnThreshold
+	}
+	if req.Policy != nil {
+		proposed.Policy = *req.Policy
+	}
+	for _, outcome := range []models.SimulationOutcome{current, proposed} {
+		if outcome.Target <= 0 || outcome.Target >= 1 {
+			return nil, fmt.Errorf("%w: target must be between 0 and 1, got %v", ErrInvalidSimulation, outcome.Target)
+		}
+		if outcome.TimeWindowDays <= 0 {
+			return nil, fmt.Errorf("%w: time_window_days must be positive, got %d", ErrInvalidSimulation, outcome.TimeWindowDays)
+		}
+	}
+
+	end := time.Now().Truncate(simulationStep)
+	start := end.AddDate(0, 0, -req.Days)
+	longestWindow := current.TimeWindowDays
+	if proposed.TimeWindowDays > longestWindow {
+		longestWindow = proposed.TimeWindowDays
+	}
+	if req.Days+longestWindow > maxHistoryDays {
+		return nil, fmt.Errorf("%w: days plus the longest time window must be at most %d", ErrInvalidSimulation, maxHistoryDays)
+	}
+	historyStart := start.AddDate(0, 0, -longestWindow)
+
+	history, err := s.metricsService.GetSLIHistory(slo, historyStart, end, simulationStep)
+	if err != nil {
+		return nil, err
+	}
+	if len(history) == 0 {
+		return nil, fmt.Errorf("no SLI history to replay")
+	}
+
+	series := newSimulationSeries(history, historyStart, end)
+	checks := deployCheckTimes(req.DeployTimes, start, end, interval)
+	series.replay(&current, start, checks)
+	series.replay(&proposed, start, checks)
+
+	return &models.SimulationResult{
+		SLOID:       slo.ID,
+		Start:       start,
+		End:         end,
+		DataPoints:  len(history),
+		GeneratedAt: time.Now(),
+		Current:     current,
+		Proposed:    proposed,
+	}, nil
+}
+
+// simulationSeries holds hourly good/total counts as prefix sums so any
+// window can be summed in constant time.
+type simulationSeries struct {
+	start time.Time
+	good  []float64
+	total []float64
+}
+
+func newSimulationSeries(history []models.SLISample, start, end time.Time) *simulationSeries {
+	steps := int(end.Sub(start) / simulationStep)
+	series := &simulationSeries{
+		start: start,
+		good:  make([]float64, steps+1),
+		total: make([]float64, steps+1),
+	}
+
+	good := make([]float64, steps)
+	total := make([]float64, steps)
+	for _, sample := range history {
+		i := int(sample.Timestamp.Sub(start) / simulationStep)
+		if i < 0 || i >= steps {
+			continue
+		}
+		good[i] += sample.Good
+		total[i] += sample.Total
+	}
+	for i := 0; i < steps; i++ {
+		series.good[i+1] = series.good[i] + good[i]
+		series.total[i+1] = series.total[i] + total[i]
+	}
+	return series
+}
+
+// sum returns good and total events in steps [from, to).
+func (ss *simulationSeries) sum(from, to int) (float64, float64) {
+	if from < 0 {
+		from = 0
+	}
+	return ss.good[to] - ss.good[from], ss.total[to] - ss.total[from]
+}
+
+// burnRate is the bad event ratio over the last hours steps before k,
+// relative to the error budget.
+func (ss *simulationSeries) burnRate(k, hours int, budget float64) float64 {
+	good, total := ss.sum(k-hours, k)
+	if total == 0 {
+		return 0
+	}
+	return (total - good) / total / budget
+}
+
+func (ss *simulationSeries) replay(outcome *models.SimulationOutcome, start time.Time, checks []time.Time) {
+	budget := 1 - outcome.Target
+	windowSteps := outcome.TimeWindowDays * 24
+	first := int(start.Sub(ss.start) / simulationStep)
+	last := len(ss.total) - 1
+
+	// Evaluate at every step boundary k using the data before it.
+	remaining := make([]float64, last+1)
+	burn := make([]float64, last+1)
+	exhaustedDays := make(map[string]bool)
+	outcome.MinRemainingBudget = 100
+	var fastFiring, slowFiring bool
+
+	for k := first + 1; k <= last; k++ {
+		good, total := ss.sum(k-windowSteps, k)
+		remaining[k] = 100
+		if total > 0 {
+			allowed := budget * total
+			remaining[k] = (allowed - (total - good)) / allowed * 100
+		}
+		burn[k] = ss.burnRate(k, 1, budget)
+
+		if remaining[k] < outcome.MinRemainingBudget {
+			outcome.MinRemainingBudget = remaining[k]
+		}
+		if remaining[k] <= 0 {
+			at := ss.start.Add(time.Duration(k-1) * simulationStep)
+			exhaustedDays[at.UTC().Format("2006-01-02")] = true
+		}
+
+		fast := burn[k] >= outcome.FastBurnThreshold
+		if fast && !fastFiring {
+			outcome.FastBurnAlerts++
+		}
+		fastFiring = fast
+
+		slow := ss.burnRate(k, 6, budget) >= outcome.SlowBurnThreshold
+		if slow && !slowFiring {
+			outcome.SlowBurnAlerts++
+		}
+		slowFiring = slow
+	}
+	outcome.BudgetExhaustedDays = len(exhaustedDays)
+
+	for _, check := range checks {
+		k := int(check.Sub(ss.start) / simulationStep)
+		if k <= first || k > last {
+			continue
+		}
+		decision, _ := evaluateDeployPolicy(outcome.Policy, remaining[k], burn[k])
+		outcome.DeployChecks++
+		switch decision {
+		case models.DeployDecisionBlocked:
+			outcome.BlockedDeploys++
+		case models.DeployDecisionRisky:
+			outcome.RiskyDeploys++
+		default:
+			outcome.SafeDeploys++
+		}
+	}
+}
+
+// deployCheckTimes returns the given deploy times within [start, end), or a
+// check every interval when none are given.
+func deployCheckTimes(deployTimes []time.Time, start, end time.Time, interval time.Duration) []time.Time {
+	var checks []time.Time
+	if len(deployTimes) > 0 {
+		for _, t := range deployTimes {
+			if !t.Before(start) && t.Before(end) {
+				checks = append(checks, t)
+			}
+		}
+		sort.Slice(checks, func(i, j int) bool { return checks[i].Before(checks[j]) })
+		return checks
+	}
+
+	for t := start.Add(interval); t.Before(end); t = t.Add(interval) {
+		checks = append(checks, t)
+	}
+	return checks
+}
@@ -0,0 +1,36 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"slo-platform/internal/models"
+)
+
+func TestSimulatePolicyRejectsBadRequests(t *testing.T) {
+	db := newTestDB(t)
+	slos := NewSLOService(db, nil, nil, nil)
+	service := createService(t, db, "checkout", "prod")
+	slo := &models.SLO{ServiceID: service.ID, Name: "availability", SLIType: models.SLITypeAvailability,
+		Target: 0.999, TimeWindowDays: 30}
+	if err := db.Create(slo).Error; err != nil {
+		t.Fatal(err)
+	}
+	target, window := 1.5, 90
+
+	tests := []struct {
+		name string
+		req  SimulationRequest
+	}{
+		{"days over the limit", SimulationRequest{Days: maxHistoryDays}},
+		{"days and proposed window over the limit", SimulationRequest{Days: maxHistoryDays - 60, TimeWindowDays: &window}},
+		{"target out of range", SimulationRequest{Target: &target}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := slos.SimulatePolicy(slo.ID, tt.req); !errors.Is(err, ErrInvalidSimulation) {
+				t.Errorf("got %v, want ErrInvalidSimulation", err)
+			}
+		})
+	}
+}
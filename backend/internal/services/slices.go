@@ -0,0 +1,278 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"github.com/prometheus/common/model"
+)
+
+const (
+	defaultMaxSlices = 50
+	maxSlicesLimit   = 1000
+	maxGroupByLabels = 3
+)
+
+// groupByLabels parses the SLO's comma-separated group_by labels.
+func groupByLabels(slo *models.SLO) []string {
+	var labels []string
+	for _, label := range strings.Split(slo.GroupBy, ",") {
+		if label = strings.TrimSpace(label); label != "" {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
+
+func sliceLimit(slo *models.SLO) int {
+	switch {
+	case slo.MaxSlices <= 0:
+		return defaultMaxSlices
+	case slo.MaxSlices > maxSlicesLimit:
+		return maxSlicesLimit
+	default:
+		return slo.MaxSlices
+	}
+}
+
+// sliceKey returns a stable key and the values of the groupBy labels of a
+// series. Missing labels are reported as empty strings.
+func sliceKey(metric model.Metric, groupBy []string) (string, map[string]string) {
+	if len(groupBy) == 0 {
+		return "", nil
+	}
+	values := make(map[string]string, len(groupBy))
+	parts := make([]string, len(groupBy))
+	for i, label := range groupBy {
+		value := string(metric[model.LabelName(label)])
+		values[label] = value
+		parts[i] = label + "=" + strconv.Quote(value)
+	}
+	return strings.Join(parts, ","), values
+}
+
+// SliceMeasurement is the result of measuring an SLO per slice.
+type SliceMeasurement struct {
+	Slices    []models.SLOSlice // worst first
+	Truncated bool              // more slices than the SLO's max_slices were returned
+	Warnings  []string          // from Prometheus
+}
+
+// MeasureSLISlices computes the SLI for every combination of the SLO's
+// group_by label values, cut to the SLO's cardinality limit. SLOs without
+// group_by labels, or without Prometheus, have no slices.
+func (ms *MetricsService) MeasureSLISlices(slo *models.SLO) (*SliceMeasurement, error) {
+	groupBy := groupByLabels(slo)
+	if len(groupBy) == 0 || ms.prometheusAPI == nil {
+		return &SliceMeasurement{}, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	ctx, warnings := collectWarnings(ctx)
+
+	var slices []models.SLOSlice
+	var err error
+	switch {
+	case isWindowBased(slo):
+		slices, err = ms.windowSlices(ctx, slo, groupBy)
+	case slo.PrometheusQuery != "":
+		slices, err = ms.querySlices(ctx, slo.PrometheusQuery, groupBy)
+	case slo.SLIType == models.SLITypeLatency:
+		slices, err = ms.latencySlices(ctx, slo, groupBy)
+	default:
+		slices, err = ms.ratioSlices(ctx, slo, groupBy)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(slices, func(i, j int) bool { return slices[i].CurrentSLI < slices[j].CurrentSLI })
+	result := &SliceMeasurement{Slices: slices, Warnings: warnings.list()}
+	if limit := sliceLimit(slo); len(slices) > limit {
+		result.Slices = slices[:limit]
+		result.Truncated = true
+	}
+	return result, nil
+}
+
+// querySlices treats each series returned by a ratio query as one slice. The
+// query must keep the group_by labels, e.g. "sum by (endpoint) (...) / sum by
+// (endpoint) (...)". Series sharing a slice keep the worst value.
+func (ms *MetricsService) querySlices(ctx context.Context, query string, groupBy []string) ([]models.SLOSlice, error) {
+	vector, err := ms.queryVector(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	index := make(map[string]int)
+	var slices []models.SLOSlice
+	for _, sample := range vector {
+		key, labels := sliceKey(sample.Metric, groupBy)
+		value := float64(sample.Value)
+		if i, ok := index[key]; ok {
+			if value < slices[i].CurrentSLI {
+				slices[i].CurrentSLI = value
+			}
+			continue
+		}
+		index[key] = len(slices)
+		slices = append(slices, models.SLOSlice{Labels: labels, CurrentSLI: value})
+	}
+	return slices, nil
+}
+
+// ratioSlices runs the SLI type's default good/total query templates grouped
+// by the slice labels.
+func (ms *MetricsService) ratioSlices(ctx context.Context, slo *models.SLO, groupBy []string) ([]models.SLOSlice, error) {
+	var goodSelector, totalSelector string
+	invert := false
+	switch slo.SLIType {
+	case models.SLITypeAvailability:
+		goodSelector = fmt.Sprintf(`http_requests_total{service="%s",status!~"5.."}`, slo.Service.Name)
+		totalSelector = fmt.Sprintf(`http_requests_total{service="%s"}`, slo.Service.Name)
+	case models.SLITypeErrorRate:
+		goodSelector = fmt.Sprintf(`http_requests_total{service="%s",status=~"5.."}`, slo.Service.Name)
+		totalSelector = fmt.Sprintf(`http_requests_total{service="%s"}`, slo.Service.Name)
+		invert = true
+	case models.SLITypeCorrectness, models.SLITypeDurability:
+		if slo.SuccessMetric == "" || slo.TotalMetric == "" {
+			return nil, fmt.Errorf("%s SLO requires success_metric and total_metric or a prometheus_query", slo.SLIType)
+		}
+		goodSelector = fmt.Sprintf(`%s{service="%s"}`, slo.SuccessMetric, slo.Service.Name)
+		totalSelector = fmt.Sprintf(`%s{service="%s"}`, slo.TotalMetric, slo.Service.Name)
+	default:
+		return nil, fmt.Errorf("%s SLO requires a prometheus_query to be sliced", slo.SLIType)
+	}
+
+	by := strings.Join(groupBy, ",")
+	goodVector, err := ms.queryVector(ctx, fmt.Sprintf(`sum by (%s) (rate(%s[5m]))`, by, goodSelector))
+	if err != nil {
+		return nil, err
+	}
+	totalVector, err := ms.queryVector(ctx, fmt.Sprintf(`sum by (%s) (rate(%s[5m]))`, by, totalSelector))
+	if err != nil {
+		return nil, err
+	}
+
+	good := make(map[string]float64, len(goodVector))
+	for _, sample := range goodVector {
+		key, _ := sliceKey(sample.Metric, groupBy)
+		good[key] += float64(sample.Value)
+	}
+
+	slices := make([]models.SLOSlice, 0, len(totalVector))
+	for _, sample := range totalVector {
+		key, labels := sliceKey(sample.Metric, groupBy)
+		slice := models.SLOSlice{Labels: labels, Good: good[key], Total: float64(sample.Value)}
+		if invert {
+			slice.Good = slice.Total - slice.Good
+		}
+		slice.CurrentSLI = 1.0 // 100% if no traffic
+		if slice.Total > 0 {
+			slice.CurrentSLI = slice.Good / slice.Total
+		}
+		slices = append(slices, slice)
+	}
+	return slices, nil
+}
+
+// latencySlices reads classic histogram buckets grouped by the slice labels
+// and applies the latency threshold to each slice separately.
+func (ms *MetricsService) latencySlices(ctx context.Context, slo *models.SLO, groupBy []string) ([]models.SLOSlice, error) {
+	if slo.LatencyThreshold <= 0 {
+		return nil, fmt.Errorf("latency SLO requires a latency_threshold")
+	}
+	if slo.LatencyMode == models.LatencyModeNative {
+		return nil, fmt.Errorf("native histogram latency SLOs require a prometheus_query to be sliced")
+	}
+
+	metric := slo.LatencyMetric
+	if metric == "" {
+		metric = defaultLatencyMetric
+	}
+
+	query := fmt.Sprintf(`sum by (le,%s) (rate(%s_bucket{service="%s"}[5m]))`, strings.Join(groupBy, ","), metric, slo.Service.Name)
+	vector, err := ms.queryVector(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	buckets := make(map[string][]histogramBucket)
+	labels := make(map[string]map[string]string)
+	for _, sample := range vector {
+		le, err := strconv.ParseFloat(string(sample.Metric["le"]), 64)
+		if err != nil {
+			continue
+		}
+		key, values := sliceKey(sample.Metric, groupBy)
+		buckets[key] = append(buckets[key], histogramBucket{le: le, count: float64(sample.Value)})
+		labels[key] = values
+	}
+
+	slices := make([]models.SLOSlice, 0, len(buckets))
+	for key, sliceBuckets := range buckets {
+		measurement := latencyFromBuckets(sliceBuckets, slo.LatencyThreshold, slo.LatencyMode)
+		slices = append(slices, models.SLOSlice{
+			Labels:     labels[key],
+			CurrentSLI: measurement.Value,
+			Good:       measurement.Good,
+			Total:      measurement.Total,
+		})
+	}
+	return slices, nil
+}
+
+// windowSlices counts good intervals per slice over the compliance window,
+// clipped to Prometheus' retention.
+func (ms *MetricsService) windowSlices(ctx context.Context, slo *models.SLO, groupBy []string) ([]models.SLOSlice, error) {
+	end := time.Now()
+	start := ms.clipToRetention(ctx, end.AddDate(0, 0, -slo.TimeWindowDays), end, windowInterval(slo))
+
+	groups, err := ms.evaluateWindowGroups(ctx, slo, start, end, groupBy)
+	if err != nil {
+		return nil, err
+	}
+
+	slices := make([]models.SLOSlice, 0, len(groups))
+	for _, group := range groups {
+		if len(group.intervals) == 0 {
+			continue
+		}
+		var good float64
+		for _, interval := range group.intervals {
+			if interval.Good {
+				good++
+			}
+		}
+		total := float64(len(group.intervals))
+		slices = append(slices, models.SLOSlice{
+			Labels:     group.labels,
+			CurrentSLI: good / total,
+			Good:       good,
+			Total:      total,
+		})
+	}
+	return slices, nil
+}
+
+func (ms *MetricsService) queryVector(ctx context.Context, query string) (model.Vector, error) {
+	result, warnings, err := ms.prometheusAPI.Query(ctx, query, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("prometheus query failed: %w", err)
+	}
+
+	recordWarnings(ctx, query, warnings)
+
+	vector, ok := result.(model.Vector)
+	if !ok {
+		return nil, fmt.Errorf("unsupported result type: %s", result.Type())
+	}
+	return vector, nil
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"slo-platform/internal/models"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+// fakePrometheus answers instant queries with vector and range queries with
+// the points of matrix, or of series[query] when set, inside the range.
+// retention is reported as the storage retention.
+type fakePrometheus struct {
+	v1.API
+	vector    model.Vector
+	matrix    model.Matrix
+	series    map[string]model.Matrix
+	warnings  v1.Warnings
+	retention string
+	ranges    []v1.Range // of every range query
+}
+
+func (p *fakePrometheus) Query(ctx context.Context, query string, ts time.Time, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	return p.vector, p.warnings, nil
+}
+
+func (p *fakePrometheus) QueryRange(ctx context.Context, query string, r v1.Range, opts ...v1.Option) (model.Value, v1.Warnings, error) {
+	p.ranges = append(p.ranges, r)
+	matrix, ok := p.series[query]
+	if !ok {
+		matrix = p.matrix
+	}
+	result := model.Matrix{}
+	for _, stream := range matrix {
+		inRange := &model.SampleStream{Metric: stream.Metric}
+		for _, pair := range stream.Values {
+			if ts := pair.Timestamp.Time(); !ts.Before(r.Start) && !ts.After(r.End) {
+				inRange.Values = append(inRange.Values, pair)
+			}
+		}
+		result = append(result, inRange)
+	}
+	return result, p.warnings, nil
+}
+
+func (p *fakePrometheus) Runtimeinfo(ctx context.Context) (v1.RuntimeinfoResult, error) {
+	return v1.RuntimeinfoResult{StorageRetention: p.retention}, nil
+}
+
+func regionSample(region string, value float64) *model.Sample {
+	return &model.Sample{Metric: model.Metric{"region": model.LabelValue(region)}, Value: model.SampleValue(value)}
+}
+
+func TestApplySlices(t *testing.T) {
+	prometheus := &fakePrometheus{
+		vector: model.Vector{
+			regionSample("eu", 0.999),
+			regionSample("us", 0.95),
+			regionSample("ap", 0.98),
+			regionSample("eu", 0.997), // a second eu series; the worse one counts
+			regionSample("sa", 1),
+		},
+		warnings: v1.Warnings{"results truncated"},
+	}
+	ms := &MetricsService{prometheusAPI: prometheus}
+	s := NewSLOService(nil, nil, ms, nil)
+
+	tests := []struct {
+		name      string
+		maxSlices int
+		want      []string // regions, worst first
+		truncated bool
+	}{
+		{"under the cap", 0, []string{"us", "ap", "eu", "sa"}, false},
+		{"at the cap", 4, []string{"us", "ap", "eu", "sa"}, false},
+		{"over the cap", 2, []string{"us", "ap"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			slo := &models.SLO{ID: 1, SLIType: models.SLITypeCustom, Target: 0.99, TimeWindowDays: 30,
+				PrometheusQuery: `sum by (region) (rate(good[5m])) / sum by (region) (rate(total[5m]))`,
+				GroupBy:         "region", MaxSlices: tt.maxSlices}
+			status := &models.SLOStatus{}
+			s.applySlices(slo, status)
+
+			if len(status.Slices) != len(tt.want) {
+				t.Fatalf("%d slices, want %d: %+v", len(status.Slices), len(tt.want), status.Slices)
+			}
+			for i, region := range tt.want {
+				if got := status.Slices[i].Labels["region"]; got != region {
+					t.Errorf("slice %d is %s, want %s", i, got, region)
+				}
+			}
+			if status.SlicesTruncated != tt.truncated {
+				t.Errorf("truncated %v, want %v", status.SlicesTruncated, tt.truncated)
+			}
+			if status.WorstSlice == nil || status.WorstSlice.Labels["region"] != "us" {
+				t.Errorf("worst slice %+v, want us", status.WorstSlice)
+			}
+			if len(status.Warnings) != 1 {
+				t.Errorf("warnings %v, want Prometheus' warning", status.Warnings)
+			}
+		})
+	}
+
+	slo := &models.SLO{ID: 1, SLIType: models.SLITypeCustom, Target: 0.99, TimeWindowDays: 30,
+		PrometheusQuery: `sum by (region) (rate(good[5m])) / sum by (region) (rate(total[5m]))`, GroupBy: "region"}
+	status := &models.SLOStatus{}
+	s.applySlices(slo, status)
+	wantStatus := map[string]string{"us": "breached", "ap": "breached", "eu": "healthy", "sa": "healthy"}
+	for _, slice := range status.Slices {
+		region := slice.Labels["region"]
+		if slice.Status != wantStatus[region] {
+			t.Errorf("%s: status %s, want %s", region, slice.Status, wantStatus[region])
+		}
+		budget := s.calculateErrorBudget(slo, slice.CurrentSLI)
+		if slice.RemainingBudget != budget.RemainingPercent || slice.ConsumedBudget != 100-budget.RemainingPercent {
+			t.Errorf("%s: budget %v remaining, %v consumed, want %v and %v", region,
+				slice.RemainingBudget, slice.ConsumedBudget, budget.RemainingPercent, 100-budget.RemainingPercent)
+		}
+	}
+	if eu := status.Slices[2]; eu.CurrentSLI != 0.997 {
+		t.Errorf("eu SLI %v, want the worse series, 0.997", eu.CurrentSLI)
+	}
+
+	slo.GroupBy = ""
+	status = &models.SLOStatus{}
+	s.applySlices(slo, status)
+	if status.Slices != nil || status.WorstSlice != nil {
+		t.Errorf("SLO without group_by got slices %+v", status.Slices)
+	}
+}
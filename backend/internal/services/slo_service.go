@@ -3,25 +3,51 @@ package services
 import (
 	"fmt"
 	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"slo-platform/internal/models"
 
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
 type SLOService struct {
-	db *gorm.DB
+	db              *gorm.DB
+	incidentService *IncidentService
+	metricsService  *MetricsService
+	events          *EventHub
+	forecasts       *forecastCache
 }
 
-func NewSLOService(db *gorm.DB) *SLOService {
-	return &SLOService{db: db}
+func NewSLOService(db *gorm.DB, incidentService *IncidentService, metricsService *MetricsService, events *EventHub) *SLOService {
+	return &SLOService{db: db, incidentService: incidentService, metricsService: metricsService, events: events,
+		forecasts: newForecastCache()}
 }
 
 func (s *SLOService) CreateSLO(slo *models.SLO) error {
+	if err := s.validateSLO(slo); err != nil {
+		return err
+	}
 	return s.db.Create(slo).Error
 }
 
+// validateSLO runs ValidateSLO and checks that the SLO's service exists.
+func (s *SLOService) validateSLO(slo *models.SLO) error {
+	errs := sloFieldErrors(slo)
+	if slo.ServiceID != 0 {
+		var count int64
+		if err := s.db.Model(&models.Service{}).Where("id = ?", slo.ServiceID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			errs.add("service_id", "service %d does not exist", slo.ServiceID)
+		}
+	}
+	return errs.err()
+}
+
 func (s *SLOService) GetSLO(id uint) (*models.SLO, error) {
 	var slo models.SLO
 	err := s.db.Preload("Service").First(&slo, id).Error
@@ -34,31 +60,143 @@ func (s *SLOService) ListSLOs(serviceID uint) ([]models.SLO, error) {
 	return slos, err
 }
 
+// UpdateSLO replaces the SLO's fields with those of slo. If revisions are
+// given, the SLO's current revision must be one of them.
+func (s *SLOService) UpdateSLO(id uint, slo *models.SLO, revisions ...string) (*models.SLO, error) {
+	return s.updateSLO(id, revisions, func(current *models.SLO) error {
+		*current = *slo
+		return nil
+	})
+}
+
+// PatchSLO applies a JSON merge patch to the SLO.
+func (s *SLOService) PatchSLO(id uint, patch []byte, revisions ...string) (*models.SLO, error) {
+	return s.updateSLO(id, revisions, func(current *models.SLO) error {
+		return mergePatch(current, patch)
+	})
+}
+
+func (s *SLOService) updateSLO(id uint, revisions []string, apply func(*models.SLO) error) (*models.SLO, error) {
+	var slo models.SLO
+	if err := s.db.First(&slo, id).Error; err != nil {
+		return nil, err
+	}
+	if err := checkRevision(slo.UpdatedAt, revisions); err != nil {
+		return nil, err
+	}
+
+	createdAt, updatedAt := slo.CreatedAt, slo.UpdatedAt
+	if err := apply(&slo); err != nil {
+		return nil, err
+	}
+	slo.ID, slo.CreatedAt = id, createdAt
+	slo.Service = models.Service{}
+	applySLODefaults(&slo)
+	if err := s.validateSLO(&slo); err != nil {
+		return nil, err
+	}
+	if err := saveRevision(s.db, &slo, updatedAt); err != nil {
+		return nil, err
+	}
+	return s.GetSLO(id)
+}
+
+// applySLODefaults fills the fields whose column defaults Create relies on,
+// since an update writes every column.
+func applySLODefaults(slo *models.SLO) {
+	if slo.SLIMode == "" {
+		slo.SLIMode = models.SLIModeRequest
+	}
+	if slo.WindowIntervalSeconds == 0 {
+		slo.WindowIntervalSeconds = 60
+	}
+	if slo.FastBurnThreshold == 0 {
+		slo.FastBurnThreshold = 2.0
+	}
+	if slo.SlowBurnThreshold == 0 {
+		slo.SlowBurnThreshold = 1.0
+	}
+}
+
+// DeleteSLO soft-deletes the SLO and resolves its open breach incident, as
+// nothing would evaluate the SLO again to resolve it. RestoreSLO brings the
+// SLO back.
+func (s *SLOService) DeleteSLO(id uint) error {
+	result := s.db.Delete(&models.SLO{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	s.forecasts.remove(id)
+	return s.incidentService.ResolveSLOBreachIncidents(id)
+}
+
+// RestoreSLO undoes DeleteSLO. An SLO deleted with its service comes back
+// with RestoreService instead.
+func (s *SLOService) RestoreSLO(id uint) (*models.SLO, error) {
+	var slo models.SLO
+	if err := s.db.Unscoped().First(&slo, id).Error; err != nil {
+		return nil, err
+	}
+	if slo.DeletedAt.Valid {
+		var count int64
+		if err := s.db.Model(&models.Service{}).Where("id = ?", slo.ServiceID).Count(&count).Error; err != nil {
+			return nil, err
+		}
+		if count == 0 {
+			return nil, fmt.Errorf("%w: restore service %d first", ErrServiceDeleted, slo.ServiceID)
+		}
+		if err := s.db.Unscoped().Model(&slo).Update("deleted_at", nil).Error; err != nil {
+			return nil, err
+		}
+	}
+	return s.GetSLO(id)
+}
+
 func (s *SLOService) CalculateSLOStatus(sloID uint) (*models.SLOStatus, error) {
 	slo, err := s.GetSLO(sloID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Get current SLI value (this would normally query Prometheus)
-	currentSLI, err := s.getCurrentSLI(slo)
+	// Get current SLI value
+	measurement, err := s.metricsService.MeasureSLI(slo)
 	if err != nil {
 		return nil, err
 	}
 
+	if measurement.NoData {
+		return &models.SLOStatus{
+			SLOID:            slo.ID,
+			ServiceName:      slo.Service.Name,
+			SLOName:          slo.Name,
+			Target:           slo.Target,
+			Status:           "no_data",
+			TimeToExhaustion: -1,
+			Warnings:         measurement.Warnings,
+			LastUpdated:      time.Now(),
+		}, nil
+	}
+	currentSLI := measurement.Value
+
 	// Calculate error budget
 	errorBudget := s.calculateErrorBudget(slo, currentSLI)
 
 	// Calculate burn rates
-	burnRates := s.calculateBurnRates(slo)
+	burnRates, err := s.metricsService.GetBurnRates(slo)
+	if err != nil {
+		return nil, err
+	}
 
 	// Determine status
 	status := s.determineSLOStatus(currentSLI, slo.Target, errorBudget.RemainingPercent)
 
 	// Calculate time to exhaustion
-	timeToExhaustion := s.calculateTimeToExhaustion(errorBudget.RemainingBudget, burnRates.CurrentBurnRate)
+	timeToExhaustion := s.calculateTimeToExhaustion(slo, errorBudget.RemainingBudget, burnRates.CurrentBurnRate)
 
-	return &models.SLOStatus{
+	sloStatus := &models.SLOStatus{
 		SLOID:            slo.ID,
 		ServiceName:      slo.Service.Name,
 		SLOName:          slo.Name,
@@ -71,8 +209,14 @@ func (s *SLOService) CalculateSLOStatus(sloID uint) (*models.SLOStatus, error) {
 		FastBurnRate:     burnRates.FiveMinuteBurn,
 		SlowBurnRate:     burnRates.SixHourBurn,
 		TimeToExhaustion: timeToExhaustion,
+		DataCoverage:     measurement.DataCoverage,
+		Warnings:         mergeWarnings(measurement.Warnings, burnRates.Warnings),
 		LastUpdated:      time.Now(),
-	}, nil
+	}
+
+	s.applySlices(slo, sloStatus)
+
+	return sloStatus, nil
 }
 
 func (s *SLOService) GetErrorBudget(sloID uint) (*models.ErrorBudget, error) {
@@ -81,15 +225,29 @@ func (s *SLOService) GetErrorBudget(sloID uint) (*models.ErrorBudget, error) {
 		return nil, err
 	}
 
-	currentSLI, err := s.getCurrentSLI(slo)
+	measurement, err := s.metricsService.MeasureSLI(slo)
 	if err != nil {
 		return nil, err
 	}
 
+	if measurement.NoData {
+		return &models.ErrorBudget{
+			SLOID:       slo.ID,
+			TotalBudget: 1.0 - slo.Target,
+			NoData:      true,
+			Warnings:    measurement.Warnings,
+			LastUpdated: time.Now(),
+		}, nil
+	}
+	currentSLI := measurement.Value
+
 	errorBudget := s.calculateErrorBudget(slo, currentSLI)
-	burnRates := s.calculateBurnRates(slo)
+	burnRates, err := s.metricsService.GetBurnRates(slo)
+	if err != nil {
+		return nil, err
+	}
 
-	return &models.ErrorBudget{
+	budget := &models.ErrorBudget{
 		SLOID:             slo.ID,
 		TotalBudget:       errorBudget.TotalBudget,
 		ConsumedBudget:    errorBudget.ConsumedBudget,
@@ -100,8 +258,26 @@ func (s *SLOService) GetErrorBudget(sloID uint) (*models.ErrorBudget, error) {
 		OneHourBurn:       burnRates.OneHourBurn,
 		SixHourBurn:       burnRates.SixHourBurn,
 		TwentyFourHourBurn: burnRates.TwentyFourHourBurn,
+		DataCoverage:      measurement.DataCoverage,
+		Warnings:          mergeWarnings(measurement.Warnings, burnRates.Warnings),
 		LastUpdated:       time.Now(),
-	}, nil
+	}
+
+	if isWindowBased(slo) {
+		budget.TotalBudgetMinutes, budget.ConsumedBudgetMinutes, budget.RemainingBudgetMinutes = budgetMinutes(slo, currentSLI)
+	}
+
+	return budget, nil
+}
+
+// budgetMinutes expresses a window-based SLO's budget as minutes of allowed
+// downtime over its compliance window. The SLI is the share of good
+// intervals, so 1-SLI of the window was bad.
+func budgetMinutes(slo *models.SLO, currentSLI float64) (total, consumed, remaining float64) {
+	windowMinutes := float64(slo.TimeWindowDays) * 24 * 60
+	total = (1.0 - slo.Target) * windowMinutes
+	consumed = (1.0 - currentSLI) * windowMinutes
+	return total, consumed, total - consumed
 }
 
 func (s *SLOService) CheckDeploySafety(serviceName, environment string) (*models.DeployCheck, error) {
@@ -117,54 +293,87 @@ func (s *SLOService) CheckDeploySafety(serviceName, environment string) (*models
 		return nil, err
 	}
 
+	incidents, err := s.incidentService.GetRecentIncidents(service.ID, time.Now().Add(-recentIncidentWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	lastBreach, err := s.incidentService.LastSLOBreach(service.ID)
+	if err != nil {
+		return nil, err
+	}
+
 	if len(slos) == 0 {
-		return &models.DeployCheck{
-			ServiceName: serviceName,
-			Environment: environment,
-			Decision:    models.DeployDecisionSafe,
-			Reason:      "No SLOs defined for this service",
-			CheckedAt:   time.Now(),
-		}, nil
+		decision, reason := s.applyIncidentPolicy(models.DeployDecisionSafe, "No SLOs defined for this service", incidents)
+		return s.publishDeployCheck(&service, &models.DeployCheck{
+			ServiceName:     serviceName,
+			Environment:     environment,
+			Decision:        decision,
+			Reason:          reason,
+			RecentIncidents: len(incidents) > 0,
+			LastSLOBreach:   lastBreach,
+			CheckedAt:       time.Now(),
+		}), nil
 	}
 
-	// Get the most critical SLO status
+	// Get the most critical SLO status, rolling sliced SLOs up to their worst slice
 	var worstSLO *models.SLOStatus
+	var worstSlice map[string]string
 	var worstBudget float64 = 100
 
 	for _, slo := range slos {
 		status, err := s.CalculateSLOStatus(slo.ID)
-		if err != nil {
+		if err != nil || status.Status == "no_data" {
+			continue
+		}
+
+		if status.WorstSlice != nil && status.WorstSlice.RemainingBudget < status.RemainingBudget {
+			if status.WorstSlice.RemainingBudget < worstBudget {
+				worstBudget = status.WorstSlice.RemainingBudget
+				worstSLO = status
+				worstSlice = status.WorstSlice.Labels
+			}
 			continue
 		}
 
 		if status.RemainingBudget < worstBudget {
 			worstBudget = status.RemainingBudget
 			worstSLO = status
+			worstSlice = nil
 		}
 	}
 
 	if worstSLO == nil {
-		return &models.DeployCheck{
-			ServiceName: serviceName,
-			Environment: environment,
-			Decision:    models.DeployDecisionSafe,
-			Reason:      "Unable to calculate SLO status",
-			CheckedAt:   time.Now(),
-		}, nil
+		decision, reason := s.applyIncidentPolicy(models.DeployDecisionSafe, "Unable to calculate SLO status", incidents)
+		return s.publishDeployCheck(&service, &models.DeployCheck{
+			ServiceName:     serviceName,
+			Environment:     environment,
+			Decision:        decision,
+			Reason:          reason,
+			RecentIncidents: len(incidents) > 0,
+			LastSLOBreach:   lastBreach,
+			CheckedAt:       time.Now(),
+		}), nil
 	}
 
-	decision, reason := s.evaluateDeployDecision(worstSLO)
+	decision, reason := evaluateDeployPolicy(models.DefaultDeployPolicy, worstBudget, worstSLO.CurrentBurnRate)
+	if worstSlice != nil {
+		reason = fmt.Sprintf("%s (%s slice %s)", reason, worstSLO.SLOName, formatSliceLabels(worstSlice))
+	}
+	decision, reason = s.applyIncidentPolicy(decision, reason, incidents)
 
-	return &models.DeployCheck{
+	return s.publishDeployCheck(&service, &models.DeployCheck{
 		ServiceName:     serviceName,
 		Environment:     environment,
 		Decision:        decision,
 		Reason:          reason,
-		RemainingBudget: worstSLO.RemainingBudget,
+		RemainingBudget: worstBudget,
 		BurnRate:        worstSLO.CurrentBurnRate,
-		RecentIncidents: s.hasRecentIncidents(service.ID),
+		RecentIncidents: len(incidents) > 0,
+		LastSLOBreach:   lastBreach,
+		WorstSlice:      worstSlice,
 		CheckedAt:       time.Now(),
-	}, nil
+	}), nil
 }
 
 type ErrorBudgetCalc struct {
@@ -180,21 +389,14 @@ type BurnRates struct {
 	OneHourBurn       float64
 	SixHourBurn       float64
 	TwentyFourHourBurn float64
+	NoData             bool
+	Warnings           []string
 }
 
-func (s *SLOService) getCurrentSLI(slo *models.SLO) (float64, error) {
-	// This would normally query Prometheus
-	// For now, return a mock value based on SLO type
-	switch slo.SLIType {
-	case models.SLITypeAvailability:
-		return 0.9987, nil // 99.87% availability
-	case models.SLITypeLatency:
-		return 0.995, nil // 99.5% latency compliance
-	case models.SLITypeErrorRate:
-		return 0.992, nil // 99.2% success rate
-	default:
-		return 0.99, nil
-	}
+// publishDeployCheck sends a deploy check decision to the stream.
+func (s *SLOService) publishDeployCheck(service *models.Service, check *models.DeployCheck) *models.DeployCheck {
+	s.events.Publish(models.EventDeployCheck, service, check)
+	return check
 }
 
 func (s *SLOService) calculateErrorBudget(slo *models.SLO, currentSLI float64) *ErrorBudgetCalc {
@@ -211,17 +413,6 @@ func (s *SLOService) calculateErrorBudget(slo *models.SLO, currentSLI float64) *
 	}
 }
 
-func (s *SLOService) calculateBurnRates(slo *models.SLO) *BurnRates {
-	// Mock burn rates - in production, these would be calculated from historical data
-	return &BurnRates{
-		CurrentBurnRate:   1.2,
-		FiveMinuteBurn:    0.8,
-		OneHourBurn:       1.1,
-		SixHourBurn:       1.3,
-		TwentyFourHourBurn: 1.0,
-	}
-}
-
 func (s *SLOService) determineSLOStatus(currentSLI, target, remainingBudget float64) string {
 	if currentSLI < target {
 		return "breached"
@@ -232,7 +423,18 @@ func (s *SLOService) determineSLOStatus(currentSLI, target, remainingBudget floa
 	return "healthy"
 }
 
-func (s *SLOService) calculateTimeToExhaustion(remainingBudget, burnRate float64) int {
+// calculateTimeToExhaustion prefers the SLO's cached history-based forecast,
+// kept fresh by the evaluator, and falls back to extrapolating the current
+// burn rate when there is none.
+func (s *SLOService) calculateTimeToExhaustion(slo *models.SLO, remainingBudget, burnRate float64) int {
+	now := time.Now()
+	if forecast, ok := s.forecasts.get(slo, now, 2*forecastStep); ok && forecast != nil {
+		if forecast.ExpectedExhaustion == nil {
+			return -1 // Not within the next window
+		}
+		return int(math.Ceil(math.Max(0, forecast.ExpectedExhaustion.Sub(now).Hours())))
+	}
+
 	if burnRate <= 0 {
 		return -1 // Infinite
 	}
@@ -240,24 +442,109 @@ func (s *SLOService) calculateTimeToExhaustion(remainingBudget, burnRate float64
 	return int(math.Ceil(hoursToExhaust))
 }
 
-func (s *SLOService) evaluateDeployDecision(status *models.SLOStatus) (models.DeployDecision, string) {
+func evaluateDeployPolicy(policy models.DeployPolicy, remainingBudget, burnRate float64) (models.DeployDecision, string) {
 	// Core deploy gate logic
-	if status.RemainingBudget < 10 && status.CurrentBurnRate > 1 {
+	if remainingBudget < policy.BlockBudgetBelow && burnRate > policy.BlockBurnAbove {
 		return models.DeployDecisionBlocked, "Error budget critically low with active burn"
 	}
 	
-	if status.CurrentBurnRate > 2 {
+	if burnRate > policy.RiskyBurnAbove {
 		return models.DeployDecisionRisky, "High burn rate detected"
 	}
 	
-	if status.RemainingBudget < 20 {
+	if remainingBudget < policy.RiskyBudgetBelow {
 		return models.DeployDecisionRisky, "Low error budget remaining"
 	}
 	
 	return models.DeployDecisionSafe, "SLO status healthy"
 }
 
-func (s *SLOService) hasRecentIncidents(serviceID uint) bool {
-	// Mock implementation - would check incident management system
-	return false
+// applyIncidentPolicy tightens an SLO-based deploy decision when the service
+// has active incidents. Resolved incidents are only reported, not enforced.
+func (s *SLOService) applyIncidentPolicy(decision models.DeployDecision, reason string, incidents []models.Incident) (models.DeployDecision, string) {
+	for _, incident := range incidents {
+		if incident.IsActive() && incident.Severity == models.IncidentSeverityCritical {
+			return models.DeployDecisionBlocked, "Critical incident in progress: " + incident.Title
+		}
+	}
+
+	if decision != models.DeployDecisionSafe {
+		return decision, reason
+	}
+
+	for _, incident := range incidents {
+		if incident.IsActive() {
+			return models.DeployDecisionRisky, "Open incident: " + incident.Title
+		}
+	}
+
+	return decision, reason
+}
+
+// applySlices fills per-slice SLI, budget and status for SLOs with group_by
+// labels. Slicing failures are logged and leave the SLO-level status intact.
+func (s *SLOService) applySlices(slo *models.SLO, status *models.SLOStatus) {
+	measurement, err := s.metricsService.MeasureSLISlices(slo)
+	if err != nil {
+		zap.L().Warn("Failed to compute SLO slices", zap.Uint("slo_id", slo.ID), zap.Error(err))
+		return
+	}
+	status.Warnings = mergeWarnings(status.Warnings, measurement.Warnings)
+	if len(measurement.Slices) == 0 {
+		return
+	}
+
+	slices := measurement.Slices
+	for i := range slices {
+		budget := s.calculateErrorBudget(slo, slices[i].CurrentSLI)
+		slices[i].RemainingBudget = budget.RemainingPercent
+		slices[i].ConsumedBudget = 100 - budget.RemainingPercent
+		slices[i].Status = s.determineSLOStatus(slices[i].CurrentSLI, slo.Target, budget.RemainingPercent)
+	}
+
+	status.Slices = slices
+	status.WorstSlice = &slices[0]
+	status.SlicesTruncated = measurement.Truncated
+}
+
+func mergeWarnings(lists ...[]string) []string {
+	var merged []string
+	for _, list := range lists {
+		for _, warning := range list {
+			merged = appendUnique(merged, warning)
+		}
+	}
+	return merged
+}
+
+func formatSliceLabels(labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for key := range labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, len(keys))
+	for i, key := range keys {
+		parts[i] = fmt.Sprintf("%s=%q", key, labels[key])
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// syncBreachIncident opens an incident when an SLO breaches and resolves it
+// once the SLO recovers. Only the evaluator calls it, so reading a status
+// never changes incidents.
+func (s *SLOService) syncBreachIncident(slo *models.SLO, status *models.SLOStatus) {
+	var err error
+	switch status.Status {
+	case "breached":
+		_, err = s.incidentService.OpenSLOBreachIncident(slo, status)
+	case "no_data":
+		// Missing data neither confirms nor clears a breach.
+	default:
+		err = s.incidentService.ResolveSLOBreachIncidents(slo.ID)
+	}
+	if err != nil {
+		zap.L().Warn("Failed to sync SLO breach incident", zap.Uint("slo_id", slo.ID), zap.Error(err))
+	}
 }
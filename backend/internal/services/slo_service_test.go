@@ -0,0 +1,34 @@
+package services
+
+import (
+	"math"
+	"testing"
+
+	"slo-platform/internal/models"
+)
+
+func TestBudgetMinutes(t *testing.T) {
+	tests := []struct {
+		name                       string
+		slo                        models.SLO
+		sli                        float64
+		total, consumed, remaining float64
+	}{
+		{"half spent", models.SLO{SLIMode: models.SLIModeWindow, Target: 0.999, TimeWindowDays: 30}, 0.9995, 43.2, 21.6, 21.6},
+		{"overspent", models.SLO{SLIMode: models.SLIModeWindow, Target: 0.999, TimeWindowDays: 30}, 0.998, 43.2, 86.4, -43.2},
+		{"none spent", models.SLO{SLIType: models.SLITypeFreshness, Target: 0.99, TimeWindowDays: 7}, 1, 100.8, 0, 100.8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, consumed, remaining := budgetMinutes(&tt.slo, tt.sli)
+			for _, field := range []struct {
+				name      string
+				got, want float64
+			}{{"total", total, tt.total}, {"consumed", consumed, tt.consumed}, {"remaining", remaining, tt.remaining}} {
+				if math.Abs(field.got-field.want) > 1e-6 {
+					t.Errorf("%s minutes = %v, want %v", field.name, field.got, field.want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package services
+
+import (
+	"regexp"
+	"strings"
+
+	"slo-platform/internal/models"
+
+	"github.com/prometheus/prometheus/promql/parser"
+)
+
+// MaxTimeWindowDays is the longest compliance window an SLO may have.
+const MaxTimeWindowDays = 365
+
+var labelNamePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+// metricNamePattern accepts Prometheus metric names and the dotted names
+// OTLP metrics are pushed with.
+var metricNamePattern = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:.]*$`)
+
+// ValidateSLO checks the SLO's fields, including those required by its SLI
+// type, and returns a *ValidationError listing every invalid one.
+// SLOService also checks that the service exists.
+func ValidateSLO(slo *models.SLO) error {
+	return sloFieldErrors(slo).err()
+}
+
+func sloFieldErrors(slo *models.SLO) *ValidationError {
+	errs := &ValidationError{}
+
+	if strings.TrimSpace(slo.Name) == "" {
+		errs.add("name", "is required")
+	}
+	if slo.ServiceID == 0 {
+		errs.add("service_id", "is required")
+	}
+	if slo.Target <= 0 || slo.Target >= 1 {
+		errs.add("target", "must be between 0 and 1, got %v", slo.Target)
+	}
+	if slo.TimeWindowDays < 1 || slo.TimeWindowDays > MaxTimeWindowDays {
+		errs.add("time_window_days", "must be between 1 and %d, got %d", MaxTimeWindowDays, slo.TimeWindowDays)
+	}
+
+	switch slo.SLIType {
+	case models.SLITypeAvailability, models.SLITypeErrorRate:
+	case models.SLITypeLatency:
+		if slo.PrometheusQuery == "" && slo.LatencyThreshold <= 0 {
+			errs.add("latency_threshold", "latency SLO requires a latency_threshold or prometheus_query")
+		}
+	case models.SLITypeCustom:
+		if slo.PrometheusQuery == "" {
+			errs.add("prometheus_query", "custom SLO requires a prometheus_query")
+		}
+	case models.SLITypeFreshness:
+		if slo.PrometheusQuery == "" && slo.FreshnessMetric == "" {
+			errs.add("freshness_metric", "freshness SLO requires a freshness_metric or prometheus_query")
+		}
+		if slo.FreshnessThresholdSeconds <= 0 {
+			errs.add("freshness_threshold_seconds", "freshness SLO requires a positive freshness_threshold_seconds")
+		}
+	case models.SLITypeThroughput:
+		if slo.PrometheusQuery == "" && slo.ThroughputMetric == "" {
+			errs.add("throughput_metric", "throughput SLO requires a throughput_metric or prometheus_query")
+		}
+		if slo.MinThroughputPerMinute <= 0 {
+			errs.add("min_throughput_per_minute", "throughput SLO requires a positive min_throughput_per_minute")
+		}
+	case models.SLITypeCorrectness, models.SLITypeDurability:
+		if slo.PrometheusQuery == "" && (slo.SuccessMetric == "" || slo.TotalMetric == "") {
+			errs.add("success_metric", "%s SLO requires success_metric and total_metric or a prometheus_query", slo.SLIType)
+		}
+	case "":
+		errs.add("sli_type", "is required")
+	default:
+		errs.add("sli_type", "unsupported SLI type: %s", slo.SLIType)
+	}
+
+	if slo.PrometheusQuery != "" {
+		// Slices are read from the query's series, so a sliced SLO's query
+		// must return a vector rather than a scalar.
+		want := []parser.ValueType{parser.ValueTypeVector, parser.ValueTypeScalar}
+		if len(groupByLabels(slo)) > 0 {
+			want = want[:1]
+		}
+		if err := checkPromQL(slo.PrometheusQuery, want...); err != nil {
+			errs.add("prometheus_query", "%v", err)
+		}
+	}
+	metricFields := []struct {
+		field string
+		value string
+	}{
+		{"success_metric", slo.SuccessMetric},
+		{"total_metric", slo.TotalMetric},
+		{"latency_metric", slo.LatencyMetric},
+		{"freshness_metric", slo.FreshnessMetric},
+		{"throughput_metric", slo.ThroughputMetric},
+	}
+	for _, metric := range metricFields {
+		if metric.value != "" && !metricNamePattern.MatchString(metric.value) {
+			errs.add(metric.field, "%q is not a valid metric name", metric.value)
+		}
+	}
+
+	if slo.LatencyThreshold < 0 {
+		errs.add("latency_threshold", "must not be negative, got %v", slo.LatencyThreshold)
+	}
+	switch slo.LatencyMode {
+	case "", models.LatencyModeInterpolate, models.LatencyModeNearestBucket, models.LatencyModeNative:
+	default:
+		errs.add("latency_mode", "must be interpolate, nearest_bucket or native, got %q", slo.LatencyMode)
+	}
+
+	switch slo.SeriesAggregation {
+	case "", models.SeriesAggregationMin, models.SeriesAggregationMax, models.SeriesAggregationAvg,
+		models.SeriesAggregationSum, models.SeriesAggregationError:
+	default:
+		errs.add("series_aggregation", "must be one of min, max, avg, sum or error, got %q", slo.SeriesAggregation)
+	}
+
+	switch slo.BudgetQueryMode {
+	case "", models.BudgetQueryInstant:
+	case models.BudgetQueryRange, models.BudgetQueryRecordingRule:
+		if !isWindowBased(slo) {
+			if _, _, _, err := rangeQueries(slo, rangeStep(slo)); err != nil {
+				errs.add("budget_query_mode", "%v", err)
+			}
+		}
+	default:
+		errs.add("budget_query_mode", "must be instant, range or recording_rule, got %q", slo.BudgetQueryMode)
+	}
+	if slo.RangeStepSeconds != 0 && slo.RangeStepSeconds < int(minRangeStep.Seconds()) {
+		errs.add("range_step_seconds", "must be at least %d", int(minRangeStep.Seconds()))
+	}
+
+	switch slo.SLIMode {
+	case "", models.SLIModeRequest, models.SLIModeWindow:
+	default:
+		errs.add("sli_mode", "must be request or window, got %q", slo.SLIMode)
+	}
+	if slo.WindowIntervalSeconds < 0 {
+		errs.add("window_interval_seconds", "must not be negative, got %d", slo.WindowIntervalSeconds)
+	}
+	switch slo.WindowComparison {
+	case "", ">=", ">", "<=", "<":
+	default:
+		errs.add("window_comparison", "must be >=, >, <= or <, got %q", slo.WindowComparison)
+	}
+	if slo.SLIMode == models.SLIModeWindow && slo.PrometheusQuery == "" &&
+		slo.SLIType != models.SLITypeFreshness && slo.SLIType != models.SLITypeThroughput {
+		errs.add("prometheus_query", "window-based SLO requires a prometheus_query")
+	}
+
+	groupBy := groupByLabels(slo)
+	if len(groupBy) > maxGroupByLabels {
+		errs.add("group_by", "supports at most %d labels, got %d", maxGroupByLabels, len(groupBy))
+	}
+	for _, label := range groupBy {
+		if !labelNamePattern.MatchString(label) || label == "le" {
+			errs.add("group_by", "label %q is not a valid label name", label)
+		}
+	}
+	if slo.MaxSlices < 0 || slo.MaxSlices > maxSlicesLimit {
+		errs.add("max_slices", "must be between 0 and %d, got %d", maxSlicesLimit, slo.MaxSlices)
+	}
+
+	if _, err := parseLabelMatchers(slo.IngestMatchers); err != nil {
+		errs.add("ingest_matchers", "%v", err)
+	}
+
+	if slo.FastBurnThreshold < 0 {
+		errs.add("fast_burn_threshold", "must not be negative, got %v", slo.FastBurnThreshold)
+	}
+	if slo.SlowBurnThreshold < 0 {
+		errs.add("slow_burn_threshold", "must not be negative, got %v", slo.SlowBurnThreshold)
+	}
+	return errs
+}
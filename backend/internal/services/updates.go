@@ -0,0 +1,115 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrPreconditionFailed is returned when an update names a revision that is
+// no longer current, because someone else changed the record in between.
+var ErrPreconditionFailed = errors.New("record was modified since it was read")
+
+// ErrServiceDeleted is returned when restoring an SLO whose service is
+// still deleted.
+var ErrServiceDeleted = errors.New("service is deleted")
+
+// Revision identifies one version of a service or SLO, for ETags and
+// If-Match. It is the update time in microseconds, the precision Postgres
+// keeps.
+func Revision(updatedAt time.Time) string {
+	return strconv.FormatInt(updatedAt.UnixMicro(), 10)
+}
+
+// checkRevision passes when no revisions are given or the current one is
+// among them.
+func checkRevision(updatedAt time.Time, revisions []string) error {
+	if len(revisions) == 0 {
+		return nil
+	}
+	current := Revision(updatedAt)
+	for _, revision := range revisions {
+		if revision == current {
+			return nil
+		}
+	}
+	return ErrPreconditionFailed
+}
+
+// saveRevision writes every column of record except its key, creation and
+// deletion times, as long as the row still has the update time it was read
+// with.
+func saveRevision(db *gorm.DB, record interface{}, updatedAt time.Time) error {
+	result := db.Model(record).
+		Where("updated_at = ?", updatedAt).
+		Select("*").
+		Omit("id", "created_at", "deleted_at", clause.Associations).
+		Updates(record)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrPreconditionFailed
+	}
+	return nil
+}
+
+// mergePatch applies an RFC 7396 JSON merge patch to target: objects are
+// merged key by key, null removes a key and anything else replaces it.
+// Decode errors wrap ErrInvalidPayload.
+func mergePatch(target interface{}, patch []byte) error {
+	var changes interface{}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	if _, ok := changes.(map[string]interface{}); !ok {
+		return fmt.Errorf("%w: merge patch must be a JSON object", ErrInvalidPayload)
+	}
+
+	current, err := json.Marshal(target)
+	if err != nil {
+		return err
+	}
+	var document interface{}
+	if err := json.Unmarshal(current, &document); err != nil {
+		return err
+	}
+	merged, err := json.Marshal(mergeValue(document, changes))
+	if err != nil {
+		return err
+	}
+
+	// Removed keys must come back as zero values, so decode into a cleared
+	// target rather than over the old one.
+	value := reflect.ValueOf(target).Elem()
+	value.Set(reflect.Zero(value.Type()))
+	if err := json.Unmarshal(merged, target); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPayload, err)
+	}
+	return nil
+}
+
+func mergeValue(document, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	object, ok := document.(map[string]interface{})
+	if !ok {
+		object = map[string]interface{}{}
+	}
+	for key, change := range changes {
+		if change == nil {
+			delete(object, key)
+			continue
+		}
+		object[key] = mergeValue(object[key], change)
+	}
+	return object
+}
@@ -0,0 +1,77 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"slo-platform/internal/models"
+)
+
+// ErrServiceExists is returned when a service is registered twice in the
+// same environment.
+var ErrServiceExists = errors.New("service already exists in this environment")
+
+// FieldError is a problem with one field of a request body.
+type FieldError struct {
+	Field   string `json:"field" example:"target"`
+	Message string `json:"message" example:"must be between 0 and 1, got 5"`
+}
+
+// ValidationError lists every invalid field of a service or SLO, so a client
+// can fix them all at once.
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) add(field, format string, args ...interface{}) {
+	e.Fields = append(e.Fields, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// err returns the ValidationError, or nil when no field failed.
+func (e *ValidationError) err() error {
+	if len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+const maxServiceNameLength = 128
+
+// Service names are interpolated into PromQL label matchers and used in
+// deploy-check URLs, so they are limited to characters safe in both.
+var serviceNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]*$`)
+
+// ValidateService checks a service's own fields. ServiceRegistry also checks
+// that its environment is registered and that the name is free there.
+func ValidateService(service *models.Service) error {
+	return serviceFieldErrors(service).err()
+}
+
+func serviceFieldErrors(service *models.Service) *ValidationError {
+	errs := &ValidationError{}
+	switch {
+	case strings.TrimSpace(service.Name) == "":
+		errs.add("name", "is required")
+	case len(service.Name) > maxServiceNameLength:
+		errs.add("name", "must be at most %d characters", maxServiceNameLength)
+	case !serviceNamePattern.MatchString(service.Name):
+		errs.add("name", "may only contain letters, digits, '.', '_' and '-', got %q", service.Name)
+	}
+	if strings.TrimSpace(service.OwnerTeam) == "" {
+		errs.add("owner_team", "is required")
+	}
+	if strings.TrimSpace(service.Environment) == "" {
+		errs.add("environment", "is required")
+	}
+	return errs
+}
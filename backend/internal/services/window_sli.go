@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"slo-platform/internal/models"
+
+	v1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+)
+
+const (
+	defaultWindowInterval = time.Minute
+	// Prometheus rejects range queries returning more than 11,000 points per series.
+	maxRangeQueryPoints = 10000
+)
+
+type intervalResult struct {
+	Timestamp time.Time
+	Good      bool
+}
+
+func windowInterval(slo *models.SLO) time.Duration {
+	if slo.WindowIntervalSeconds <= 0 {
+		return defaultWindowInterval
+	}
+	return time.Duration(slo.WindowIntervalSeconds) * time.Second
+}
+
+// windowCondition is the per-interval check for a window-based SLI.
+type windowCondition struct {
+	Query      string
+	Threshold  float64
+	Comparison string
+}
+
+func (wc windowCondition) isGood(value float64) bool {
+	switch wc.Comparison {
+	case ">":
+		return value > wc.Threshold
+	case "<=":
+		return value <= wc.Threshold
+	case "<":
+		return value < wc.Threshold
+	default:
+		return value >= wc.Threshold
+	}
+}
+
+// isWindowBased reports whether the SLO counts good intervals rather than
+// good events. Freshness and throughput are always measured over time.
+func isWindowBased(slo *models.SLO) bool {
+	return slo.SLIMode == models.SLIModeWindow ||
+		slo.SLIType == models.SLITypeFreshness ||
+		slo.SLIType == models.SLITypeThroughput
+}
+
+// windowConditionFor returns the SLO's window check, building the default
+// query template for freshness and throughput SLIs when no query is given.
+func windowConditionFor(slo *models.SLO) (windowCondition, error) {
+	switch slo.SLIType {
+	case models.SLITypeFreshness:
+		query := slo.PrometheusQuery
+		if query == "" {
+			if slo.FreshnessMetric == "" {
+				return windowCondition{}, fmt.Errorf("freshness SLO requires a freshness_metric or prometheus_query")
+			}
+			query = fmt.Sprintf(`time() - max(%s{service="%s"})`, slo.FreshnessMetric, slo.Service.Name)
+		}
+		return windowCondition{Query: query, Threshold: slo.FreshnessThresholdSeconds, Comparison: "<="}, nil
+	case models.SLITypeThroughput:
+		query := slo.PrometheusQuery
+		if query == "" {
+			if slo.ThroughputMetric == "" {
+				return windowCondition{}, fmt.Errorf("throughput SLO requires a throughput_metric or prometheus_query")
+			}
+			query = fmt.Sprintf(`sum(rate(%s{service="%s"}[5m])) * 60`, slo.ThroughputMetric, slo.Service.Name)
+		}
+		return windowCondition{Query: query, Threshold: slo.MinThroughputPerMinute, Comparison: ">="}, nil
+	}
+
+	if slo.PrometheusQuery == "" {
+		return windowCondition{}, fmt.Errorf("window-based SLO requires a prometheus_query")
+	}
+	return windowCondition{Query: slo.PrometheusQuery, Threshold: slo.WindowThreshold, Comparison: slo.WindowComparison}, nil
+}
+
+// measureWindowSLI computes good intervals / total intervals over the SLO's
+// compliance window, clipped to Prometheus' retention. Intervals without data,
+// including those before the retained range, lower DataCoverage.
+func (ms *MetricsService) measureWindowSLI(ctx context.Context, slo *models.SLO) (*models.SLIMeasurement, error) {
+	interval := windowInterval(slo)
+	end := time.Now()
+	windowStart := end.AddDate(0, 0, -slo.TimeWindowDays)
+	start := ms.clipToRetention(ctx, windowStart, end, interval)
+
+	intervals, err := ms.evaluateWindows(ctx, slo, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(intervals) == 0 {
+		return nil, ErrNoData
+	}
+
+	var good float64
+	for _, interval := range intervals {
+		if interval.Good {
+			good++
+		}
+	}
+	total := float64(len(intervals))
+	expected := float64(end.Sub(windowStart) / interval)
+
+	return &models.SLIMeasurement{
+		SLOID:        slo.ID,
+		Value:        good / total,
+		Good:         good,
+		Total:        total,
+		Source:       models.SLISourcePrometheus,
+		Method:       "window",
+		DataCoverage: math.Min(100, total/expected*100),
+		WindowStart:  &start,
+		WindowEnd:    &end,
+	}, nil
+}
+
+// windowHistory buckets classified intervals into steps, counting good
+// intervals as Good and all evaluated intervals as Total.
+func (ms *MetricsService) windowHistory(ctx context.Context, slo *models.SLO, start, end time.Time, step time.Duration) ([]models.SLISample, error) {
+	intervals, err := ms.evaluateWindows(ctx, slo, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	var history []models.SLISample
+	buckets := make(map[int64]int)
+	for _, interval := range intervals {
+		index := int64(interval.Timestamp.Sub(start) / step)
+		i, ok := buckets[index]
+		if !ok {
+			history = append(history, models.SLISample{Timestamp: start.Add(time.Duration(index) * step)})
+			i = len(history) - 1
+			buckets[index] = i
+		}
+		history[i].Total++
+		if interval.Good {
+			history[i].Good++
+		}
+	}
+	return history, nil
+}
+
+// evaluateWindows evaluates the SLO's window condition at every interval
+// between start and end. An interval is good only when every returned series
+// meets the threshold; intervals without data are left out.
+func (ms *MetricsService) evaluateWindows(ctx context.Context, slo *models.SLO, start, end time.Time) ([]intervalResult, error) {
+	groups, err := ms.evaluateWindowGroups(ctx, slo, start, end, nil)
+	if err != nil {
+		return nil, err
+	}
+	if len(groups) == 0 {
+		return nil, nil
+	}
+	return groups[0].intervals, nil
+}
+
+type windowGroup struct {
+	labels    map[string]string
+	intervals []intervalResult
+}
+
+// evaluateWindowGroups classifies every interval separately for each
+// combination of the groupBy label values, querying Prometheus in chunks small
+// enough for its range query limit.
+func (ms *MetricsService) evaluateWindowGroups(ctx context.Context, slo *models.SLO, start, end time.Time, groupBy []string) ([]windowGroup, error) {
+	condition, err := windowConditionFor(slo)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := windowInterval(slo)
+	results := make(map[string]map[time.Time]bool)
+	labels := make(map[string]map[string]string)
+	for chunkStart := start; chunkStart.Before(end); {
+		chunkEnd := chunkStart.Add(interval * maxRangeQueryPoints)
+		if chunkEnd.After(end) {
+			chunkEnd = end
+		}
+
+		result, warnings, err := ms.prometheusAPI.QueryRange(ctx, condition.Query, v1.Range{Start: chunkStart, End: chunkEnd, Step: interval})
+		if err != nil {
+			return nil, fmt.Errorf("prometheus range query failed: %w", err)
+		}
+
+		recordWarnings(ctx, condition.Query, warnings)
+
+		matrix, ok := result.(model.Matrix)
+		if !ok {
+			return nil, fmt.Errorf("unsupported result type: %s", result.Type())
+		}
+
+		for _, stream := range matrix {
+			key, values := sliceKey(stream.Metric, groupBy)
+			if results[key] == nil {
+				results[key] = make(map[time.Time]bool)
+				labels[key] = values
+			}
+			for _, pair := range stream.Values {
+				ts := pair.Timestamp.Time()
+				good := condition.isGood(float64(pair.Value))
+				if previous, seen := results[key][ts]; seen {
+					good = good && previous
+				}
+				results[key][ts] = good
+			}
+		}
+
+		chunkStart = chunkEnd.Add(interval)
+	}
+
+	groups := make([]windowGroup, 0, len(results))
+	for key, byTime := range results {
+		group := windowGroup{labels: labels[key], intervals: make([]intervalResult, 0, len(byTime))}
+		for ts, good := range byTime {
+			group.intervals = append(group.intervals, intervalResult{Timestamp: ts, Good: good})
+		}
+		sort.Slice(group.intervals, func(i, j int) bool {
+			return group.intervals[i].Timestamp.Before(group.intervals[j].Timestamp)
+		})
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
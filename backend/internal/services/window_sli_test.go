@@ -0,0 +1,90 @@
+package services
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"slo-platform/internal/models"
+
+	"github.com/prometheus/common/model"
+)
+
+// stepPoints returns one series with n points step apart, the last at last;
+// value(i) is the value of the i-th point counting back from last.
+func stepPoints(last time.Time, step time.Duration, n int, value func(i int) float64) model.Matrix {
+	stream := &model.SampleStream{Metric: model.Metric{}}
+	for i := n - 1; i >= 0; i-- {
+		stream.Values = append(stream.Values, model.SamplePair{
+			Timestamp: model.TimeFromUnixNano(last.Add(-time.Duration(i) * step).UnixNano()),
+			Value:     model.SampleValue(value(i)),
+		})
+	}
+	return model.Matrix{stream}
+}
+
+func TestMeasureWindowSLI(t *testing.T) {
+	db := newTestDB(t)
+	slo := &models.SLO{ID: 1, SLIType: models.SLITypeCustom, SLIMode: models.SLIModeWindow, Target: 0.99,
+		TimeWindowDays: 1, PrometheusQuery: `up`, WindowThreshold: 1, WindowComparison: ">="}
+	// The last 12 hours have data; every other minute is bad.
+	last := time.Now().Truncate(time.Minute)
+	matrix := stepPoints(last, time.Minute, 12*60, func(i int) float64 { return float64(i % 2) })
+
+	tests := []struct {
+		name      string
+		matrix    model.Matrix
+		retention string
+		total     float64 // intervals with data, ±1 for a minute passing during the test
+		coverage  float64
+	}{
+		{"full retention", matrix, "15d", 12 * 60, 50},
+		{"size-based retention", matrix, "50GiB", 12 * 60, 50},
+		{"clipped to retention", matrix, "6h", 6 * 60, 25},
+		{"no data", model.Matrix{}, "15d", 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prometheus := &fakePrometheus{matrix: tt.matrix, retention: tt.retention}
+			ms := NewMetricsService(db, "")
+			ms.prometheusAPI = prometheus
+
+			measurement, err := ms.MeasureSLI(slo)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.total == 0 {
+				if !measurement.NoData {
+					t.Errorf("got %+v, want no data", measurement)
+				}
+				return
+			}
+			if measurement.NoData || math.Abs(measurement.Total-tt.total) > 1 {
+				t.Fatalf("%v intervals (no data %v), want %v", measurement.Total, measurement.NoData, tt.total)
+			}
+			if math.Abs(measurement.Value-0.5) > 0.01 {
+				t.Errorf("SLI %v, want 0.5", measurement.Value)
+			}
+			if want := measurement.Total / (24 * 60) * 100; measurement.DataCoverage != want {
+				t.Errorf("coverage %v, want %v", measurement.DataCoverage, want)
+			}
+			if math.Abs(measurement.DataCoverage-tt.coverage) > 0.1 {
+				t.Errorf("coverage %v, want about %v", measurement.DataCoverage, tt.coverage)
+			}
+
+			windowStart := measurement.WindowEnd.AddDate(0, 0, -slo.TimeWindowDays)
+			if retention, _ := model.ParseDuration(tt.retention); time.Duration(retention) > 0 &&
+				time.Duration(retention) < 24*time.Hour {
+				windowStart = measurement.WindowEnd.Add(-time.Duration(retention))
+			}
+			if measurement.WindowStart.Before(windowStart) || measurement.WindowStart.After(windowStart.Add(time.Minute)) {
+				t.Errorf("window starts %s, want %s", measurement.WindowStart, windowStart)
+			}
+			for _, r := range prometheus.ranges {
+				if r.Start.Before(*measurement.WindowStart) {
+					t.Errorf("queried from %s, before the window start %s", r.Start, measurement.WindowStart)
+				}
+			}
+		})
+	}
+}
@@ -1,8 +1,11 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"text/tabwriter"
 
 	"slo-platform/internal/api"
 	"slo-platform/internal/config"
@@ -11,10 +14,24 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
+	"gorm.io/gorm"
 )
 
+//go:generate swag init -g main.go --parseInternal --outputTypes go,json,yaml
+
+// @title SLO Platform API
+// @version 1.0
+// @description Services, SLOs, error budgets, incidents and deploy checks.
+// @description The API does not authenticate requests; deploy it behind a gateway that does.
+// @description Errors are returned as {"error": "message"}, except that service and SLO writes answer
+// @description invalid fields (400), conflicts (409) and failed If-Match preconditions (412) with
+// @description application/problem+json details, which repeat the message in "error".
+// @BasePath /api/v1
 func main() {
-	cfg := config.Load()
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatal("Invalid configuration:", err)
+	}
 	
 	logger, _ := zap.NewProduction()
 	defer logger.Sync()
@@ -25,16 +42,52 @@ func main() {
 		log.Fatal("Failed to connect to database:", err)
 	}
 
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(db, os.Args[2:]); err != nil {
+			log.Fatal("Migration failed:", err)
+		}
+		return
+	}
+
 	if err := database.Migrate(db); err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	eventHub := services.NewEventHub(db, cfg.StreamRetention)
+	eventHub.Start()
+
 	serviceRegistry := services.NewServiceRegistry(db)
-	sloService := services.NewSLOService(db)
 	metricsService := services.NewMetricsService(db, cfg.PrometheusURL)
+	incidentService := services.NewIncidentService(db)
+	sloService := services.NewSLOService(db, incidentService, metricsService, eventHub)
+	alertmanagerReceiver := services.NewAlertmanagerReceiver(db, incidentService, eventHub, services.AlertLabelMapping{
+		ServiceLabels:    cfg.AlertmanagerServiceLabels,
+		EnvironmentLabel: cfg.AlertmanagerEnvironmentLabel,
+		SeverityLabel:    cfg.AlertmanagerSeverityLabel,
+		SeverityMap:      cfg.AlertmanagerSeverityMap,
+	})
+	remoteWriteReceiver := services.NewRemoteWriteReceiver(db)
+	otlpReceiver := services.NewOTLPReceiver(db)
+
+	retentionManager, err := services.NewRetentionManager(db, services.RetentionPolicy{
+		Raw:      cfg.RetentionRaw,
+		Minute:   cfg.RetentionMinute,
+		Hour:     cfg.RetentionHour,
+		Interval: cfg.RetentionInterval,
+	})
+	if err != nil {
+		log.Fatal("Invalid metric retention settings:", err)
+	}
+	retentionManager.Start()
+
+	sloEvaluator, err := services.NewSLOEvaluator(db, sloService, eventHub, cfg.EvaluationInterval)
+	if err != nil {
+		log.Fatal("Invalid SLO evaluation settings:", err)
+	}
+	sloEvaluator.Start()
 
 	router := gin.Default()
-	api.SetupRoutes(router, serviceRegistry, sloService, metricsService)
+	api.SetupRoutes(router, serviceRegistry, sloService, metricsService, incidentService, alertmanagerReceiver, remoteWriteReceiver, otlpReceiver, eventHub)
 
 	port := os.Getenv("PORT")
 	if port == "" {
@@ -46,3 +99,63 @@ func main() {
 		log.Fatal("Failed to start server:", err)
 	}
 }
+
+// runMigrate implements the migrate subcommand:
+//
+//	migrate up [n]    apply n pending migrations (default all)
+//	migrate down [n]  revert the last n migrations (default 1)
+//	migrate status    list migrations and when they were applied
+func runMigrate(db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: migrate up [n] | down [n] | status")
+	}
+	steps := 0
+	if len(args) > 1 {
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid step count %q", args[1])
+		}
+		steps = n
+	}
+
+	switch args[0] {
+	case "up":
+		applied, err := database.MigrateUp(db, steps)
+		for _, migration := range applied {
+			fmt.Printf("applied %04d_%s\n", migration.Version, migration.Name)
+		}
+		if err == nil && len(applied) == 0 {
+			fmt.Println("no pending migrations")
+		}
+		return err
+	case "down":
+		if steps == 0 {
+			steps = 1
+		}
+		reverted, err := database.MigrateDown(db, steps)
+		for _, migration := range reverted {
+			fmt.Printf("reverted %04d_%s\n", migration.Version, migration.Name)
+		}
+		return err
+	case "status":
+		states, err := database.MigrationStatus(db)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, state := range states {
+			applied := "pending"
+			if state.AppliedAt != nil {
+				applied = state.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			if !state.Known {
+				applied += " (unknown to this binary)"
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", state.Version, state.Name, applied)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q", args[0])
+	}
+}
@@ -20,13 +20,13 @@ const Dashboard: React.FC = () => {
   const fetchServices = async (): Promise<void> => {
     console.log('Fetching services...');
     try {
-      const response = await serviceAPI.getServices();
+      const response = await serviceAPI.getServices({ limit: 200 });
       console.log('Services response:', response);
-      setServices(response.data);
+      setServices(response.data.data);
       
       // Fetch SLO statuses for each service
       const statuses: { [key: number]: SLOStatus[] } = {};
-      for (const service of response.data) {
+      for (const service of response.data.data) {
         try {
           console.log(`Fetching SLO status for service ${service.id}...`);
           const sloResponse = await monitoringAPI.getSLOStatus(service.id);
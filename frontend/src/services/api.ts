@@ -1,5 +1,5 @@
 import axios from 'axios';
-import { Service, SLO, SLOStatus, ErrorBudget, DeployCheck, MetricIngest } from '../types';
+import { Service, SLO, SLOStatus, ErrorBudget, DeployCheck, MetricIngest, ListResponse, ListParams, Overview, StreamFilter } from '../types';
 
 const API_BASE_URL = process.env.REACT_APP_API_URL || 'http://localhost:8080/api/v1';
 
@@ -14,8 +14,8 @@ export const serviceAPI = {
   createService: (service: Omit<Service, 'id' | 'created_at' | 'updated_at'>) =>
     api.post<Service>('/services', service),
   
-  getServices: () =>
-    api.get<Service[]>('/services'),
+  getServices: (params?: ListParams) =>
+    api.get<ListResponse<Service>>('/services', { params }),
   
   getService: (id: number) =>
     api.get<Service>(`/services/${id}`),
@@ -23,16 +23,27 @@ export const serviceAPI = {
   updateService: (id: number, service: Partial<Service>) =>
     api.put<Service>(`/services/${id}`, service),
   
+  patchService: (id: number, changes: Partial<Service>, etag?: string) =>
+    api.patch<Service>(`/services/${id}`, changes, {
+      headers: { 'Content-Type': 'application/merge-patch+json', ...(etag ? { 'If-Match': etag } : {}) },
+    }),
+  
   deleteService: (id: number) =>
     api.delete(`/services/${id}`),
+  
+  restoreService: (id: number) =>
+    api.post<Service>(`/services/${id}/restore`),
 };
 
 export const sloAPI = {
   createSLO: (slo: Omit<SLO, 'id' | 'created_at' | 'updated_at'>) =>
     api.post<SLO>('/slos', slo),
   
-  getSLOs: (serviceId: number) =>
-    api.get<SLO[]>(`/services/${serviceId}/slos`),
+  getSLOs: (serviceId: number, params?: ListParams) =>
+    api.get<ListResponse<SLO>>(`/services/${serviceId}/slos`, { params }),
+  
+  listSLOs: (params?: ListParams) =>
+    api.get<ListResponse<SLO>>('/slos', { params }),
   
   getSLO: (id: number) =>
     api.get<SLO>(`/slos/${id}`),
@@ -40,8 +51,16 @@ export const sloAPI = {
   updateSLO: (id: number, slo: Partial<SLO>) =>
     api.put<SLO>(`/slos/${id}`, slo),
   
+  patchSLO: (id: number, changes: Partial<SLO>, etag?: string) =>
+    api.patch<SLO>(`/slos/${id}`, changes, {
+      headers: { 'Content-Type': 'application/merge-patch+json', ...(etag ? { 'If-Match': etag } : {}) },
+    }),
+  
   deleteSLO: (id: number) =>
     api.delete(`/slos/${id}`),
+  
+  restoreSLO: (id: number) =>
+    api.post<SLO>(`/slos/${id}/restore`),
 };
 
 export const monitoringAPI = {
@@ -56,10 +75,26 @@ export const monitoringAPI = {
       params: { service: serviceName, env: environment },
     }),
   
+  getOverview: (params?: { owner_team?: string; environment?: string; limit?: number }) =>
+    api.get<Overview>('/overview', { params }),
+  
   ingestMetric: (metric: MetricIngest) =>
     api.post('/metrics/ingest', metric),
 };
 
+export const streamAPI = {
+  // EventSource resumes from the last event ID on its own when it reconnects.
+  openEventStream: (filter: StreamFilter = {}) => {
+    const params = new URLSearchParams();
+    Object.entries(filter).forEach(([key, value]) => {
+      if (value !== undefined && value !== '') {
+        params.set(key, Array.isArray(value) ? value.join(',') : String(value));
+      }
+    });
+    return new EventSource(`${API_BASE_URL}/stream?${params.toString()}`);
+  },
+};
+
 export const healthAPI = {
   check: () =>
     api.get('/health'),
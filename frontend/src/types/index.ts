@@ -10,6 +10,24 @@ export interface Service {
   slos?: SLO[];
 }
 
+export interface Environment {
+  id: number;
+  name: string;
+  description?: string;
+  rank: number; // promotion order, lowest first
+}
+
+export interface PromotionResult {
+  source_service_id: number;
+  from_environment: string;
+  to_environment: string;
+  service: Service;
+  service_created: boolean;
+  created: SLO[];
+  updated: SLO[];
+  skipped?: string[];
+}
+
 export interface SLO {
   id: number;
   service_id: number;
@@ -22,6 +40,13 @@ export interface SLO {
   success_metric?: string;
   total_metric?: string;
   latency_threshold?: number;
+  freshness_metric?: string;
+  freshness_threshold_seconds?: number;
+  throughput_metric?: string;
+  min_throughput_per_minute?: number;
+  group_by?: string;
+  max_slices?: number;
+  ingest_matchers?: string;
   fast_burn_threshold: number;
   slow_burn_threshold: number;
   hard_budget_policy: boolean;
@@ -31,7 +56,7 @@ export interface SLO {
   error_budget?: ErrorBudget;
 }
 
-export type SLIType = 'availability' | 'latency' | 'error_rate' | 'custom';
+export type SLIType = 'availability' | 'latency' | 'error_rate' | 'custom' | 'freshness' | 'throughput' | 'correctness' | 'durability';
 
 export interface SLOStatus {
   slo_id: number;
@@ -39,7 +64,7 @@ export interface SLOStatus {
   slo_name: string;
   current_sli: number;
   target: number;
-  status: 'healthy' | 'degraded' | 'breached';
+  status: 'healthy' | 'degraded' | 'breached' | 'no_data';
   remaining_budget: number;
   consumed_budget: number;
   current_burn_rate: number;
@@ -47,6 +72,21 @@ export interface SLOStatus {
   slow_burn_rate: number;
   time_to_exhaustion: number;
   last_updated: string;
+  slices?: SLOSlice[];
+  worst_slice?: SLOSlice;
+  slices_truncated?: boolean;
+  data_coverage?: number;
+  warnings?: string[];
+}
+
+export interface SLOSlice {
+  labels: Record<string, string>;
+  current_sli: number;
+  good?: number;
+  total?: number;
+  status: 'healthy' | 'degraded' | 'breached';
+  remaining_budget: number;
+  consumed_budget: number;
 }
 
 export interface ErrorBudget {
@@ -84,3 +124,102 @@ export interface MetricIngest {
   value: number;
   metric_type: string;
 }
+
+// Envelope returned by list endpoints (GET /services, /slos, /services/:id/slos)
+export interface PageMeta {
+  limit: number;
+  count: number;
+  total?: number;
+  sort: string;
+  next_cursor?: string;
+  has_more: boolean;
+}
+
+export interface ListResponse<T> {
+  data: T[];
+  meta: PageMeta;
+  links: {
+    self: string;
+    first: string;
+    next?: string;
+  };
+}
+
+export interface ListParams {
+  limit?: number;
+  cursor?: string;
+  sort?: string;
+  fields?: string;
+  owner_team?: string;
+  environment?: string;
+  sli_type?: SLIType;
+  name_prefix?: string;
+  status?: 'healthy' | 'degraded' | 'breached' | 'no_data';
+}
+
+export interface StatusCounts {
+  total: number;
+  healthy: number;
+  degraded: number;
+  breached: number;
+  no_data: number;
+  unevaluated: number;
+}
+
+export interface StatusRollup extends StatusCounts {
+  name: string;
+  min_remaining_budget?: number;
+  max_burn_rate?: number;
+}
+
+export interface OverviewSLO {
+  slo_id: number;
+  slo_name: string;
+  service_id: number;
+  service_name: string;
+  environment: string;
+  owner_team: string;
+  status: 'healthy' | 'degraded' | 'breached';
+  remaining_budget: number;
+  current_burn_rate: number;
+  evaluated_at: string;
+}
+
+export interface Overview {
+  counts: StatusCounts;
+  worst_by_budget: OverviewSLO[];
+  worst_by_burn_rate: OverviewSLO[];
+  teams: StatusRollup[];
+  environments: StatusRollup[];
+  oldest_evaluation?: string;
+  generated_at: string;
+}
+
+export type StreamEventType = 'slo_status' | 'alert' | 'deploy_check';
+
+export interface AlertEvent {
+  incident_id: number;
+  alertname: string;
+  fingerprint: string;
+  status: 'firing' | 'resolved';
+  severity?: string;
+}
+
+export interface StreamEvent {
+  id: number;
+  type: StreamEventType;
+  service_id: number;
+  service_name: string;
+  owner_team: string;
+  environment: string;
+  data: SLOStatus | AlertEvent | DeployCheck;
+  created_at: string;
+}
+
+export interface StreamFilter {
+  service_id?: number;
+  service?: string;
+  owner_team?: string;
+  environment?: string;
+  types?: StreamEventType[];
+}
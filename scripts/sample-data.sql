@@ -0,0 +1,40 @@
+-- Sample data for demonstration. Run after the backend has migrated the
+-- schema, against an empty database:
+--   psql "$SLO_DATABASE_URL" -f scripts/sample-data.sql
+
+INSERT INTO services (name, owner_team, environment, version, description, created_at, updated_at) VALUES
+('user-service', 'platform-team', 'prod', 'v2.1.0', 'User authentication and profile management', NOW(), NOW()),
+('payment-service', 'fintech-team', 'prod', 'v1.8.3', 'Payment processing and billing', NOW(), NOW()),
+('notification-service', 'platform-team', 'prod', 'v3.2.1', 'Email and push notifications', NOW(), NOW()),
+('api-gateway', 'platform-team', 'prod', 'v4.0.0', 'API gateway and routing', NOW(), NOW())
+ON CONFLICT (name, environment) WHERE deleted_at IS NULL DO NOTHING;
+
+-- Insert sample SLOs
+INSERT INTO slos (service_id, name, description, sli_type, target, time_window_days, prometheus_query, success_metric, total_metric, latency_threshold, fast_burn_threshold, slow_burn_threshold, hard_budget_policy, created_at, updated_at) VALUES
+-- User Service SLOs
+(1, 'API Availability', 'User service API must be available', 'availability', 0.999, 30, 'sum(rate(http_requests_total{service="user-service",status!~"5.."}[5m])) / sum(rate(http_requests_total{service="user-service"}[5m]))', 'http_requests_success', 'http_requests_total', 0, 2.0, 1.0, false, NOW(), NOW()),
+(1, 'Login Latency', 'Login response time must be fast', 'latency', 0.95, 7, 'sum(rate(http_request_duration_seconds_bucket{service="user-service",endpoint="/login",le="0.3"}[5m])) / sum(rate(http_request_duration_seconds_count{service="user-service",endpoint="/login"}[5m]))', 'login_latency', 'login_requests', 0.3, 2.0, 1.0, false, NOW(), NOW()),
+
+-- Payment Service SLOs
+(2, 'Transaction Success Rate', 'Payment transactions must succeed', 'availability', 0.9995, 30, 'sum(rate(payment_success_total[5m])) / sum(rate(payment_attempts_total[5m]))', 'payment_success', 'payment_attempts', 0, 2.0, 1.0, true, NOW(), NOW()),
+(2, 'Payment Processing Time', 'Payments must process quickly', 'latency', 0.99, 7, 'sum(rate(payment_duration_seconds_bucket{le="1"}[5m])) / sum(rate(payment_duration_seconds_count[5m]))', 'payment_latency', 'payment_total', 1.0, 2.0, 1.0, true, NOW(), NOW()),
+
+-- Notification Service SLOs
+(3, 'Email Delivery Rate', 'Emails must be delivered successfully', 'availability', 0.99, 30, 'sum(rate(email_success_total[5m])) / sum(rate(email_attempts_total[5m]))', 'email_success', 'email_attempts', 0, 2.0, 1.0, false, NOW(), NOW()),
+
+-- API Gateway SLOs
+(4, 'Gateway Uptime', 'API gateway must be highly available', 'availability', 0.9999, 30, 'up{job="api-gateway"}', 'gateway_up', 'gateway_total', 0, 2.0, 1.0, true, NOW(), NOW()),
+(4, 'Request Latency', 'Gateway requests must be fast', 'latency', 0.99, 7, '', 'gateway_latency', 'gateway_requests', 0.25, 2.0, 1.0, false, NOW(), NOW())
+ON CONFLICT DO NOTHING;
+
+-- Gateway uptime is measured in good minutes: each minute up{job="api-gateway"} >= 1 counts as good
+UPDATE slos SET sli_mode = 'window', window_interval_seconds = 60, window_threshold = 1, window_comparison = '>=' WHERE name = 'Gateway Uptime';
+
+-- Create service dependencies
+INSERT INTO service_dependencies (service_id, depends_on_id, type, critical) VALUES
+(1, 4, 'api', true),  -- user-service depends on api-gateway
+(2, 4, 'api', true),  -- payment-service depends on api-gateway
+(3, 4, 'api', false), -- notification-service depends on api-gateway (non-critical)
+(2, 1, 'api', true),  -- payment-service depends on user-service
+(3, 1, 'api', false)  -- notification-service depends on user-service (non-critical)
+ON CONFLICT DO NOTHING;
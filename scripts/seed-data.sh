@@ -65,6 +65,17 @@ curl -X POST "$API_BASE_URL/services" \
     "description": "API gateway and routing"
   }' && echo "✅ Created api-gateway"
 
+# Data Pipeline
+curl -X POST "$API_BASE_URL/services" \
+  -H "Content-Type: application/json" \
+  -d '{
+    "name": "data-pipeline",
+    "owner_team": "data-team",
+    "environment": "prod",
+    "version": "v1.2.0",
+    "description": "Event ingestion and warehouse loading"
+  }' && echo "✅ Created data-pipeline"
+
 # Create SLOs for each service
 echo "📊 Creating SLOs..."
 
@@ -198,6 +209,72 @@ curl -X POST "$API_BASE_URL/slos" \
     \"hard_budget_policy\": false
   }" && echo "✅ Created API Gateway Latency SLO"
 
+# Data Pipeline SLOs
+PIPELINE_SERVICE_ID=5
+curl -X POST "$API_BASE_URL/slos" \
+  -H "Content-Type: application/json" \
+  -d "{
+    \"service_id\": $PIPELINE_SERVICE_ID,
+    \"name\": \"Warehouse Freshness\",
+    \"description\": \"Warehouse data must be less than 15 minutes old\",
+    \"sli_type\": \"freshness\",
+    \"target\": 0.99,
+    \"time_window_days\": 30,
+    \"freshness_metric\": \"pipeline_last_success_timestamp_seconds\",
+    \"freshness_threshold_seconds\": 900,
+    \"fast_burn_threshold\": 2.0,
+    \"slow_burn_threshold\": 1.0,
+    \"hard_budget_policy\": false
+  }" && echo "✅ Created Data Pipeline Freshness SLO"
+
+curl -X POST "$API_BASE_URL/slos" \
+  -H "Content-Type: application/json" \
+  -d "{
+    \"service_id\": $PIPELINE_SERVICE_ID,
+    \"name\": \"Event Throughput\",
+    \"description\": \"At least 1000 events must be processed per minute\",
+    \"sli_type\": \"throughput\",
+    \"target\": 0.995,
+    \"time_window_days\": 7,
+    \"throughput_metric\": \"pipeline_events_processed_total\",
+    \"min_throughput_per_minute\": 1000,
+    \"fast_burn_threshold\": 2.0,
+    \"slow_burn_threshold\": 1.0,
+    \"hard_budget_policy\": false
+  }" && echo "✅ Created Data Pipeline Throughput SLO"
+
+curl -X POST "$API_BASE_URL/slos" \
+  -H "Content-Type: application/json" \
+  -d "{
+    \"service_id\": $PIPELINE_SERVICE_ID,
+    \"name\": \"Record Correctness\",
+    \"description\": \"Loaded records must pass validation\",
+    \"sli_type\": \"correctness\",
+    \"target\": 0.999,
+    \"time_window_days\": 30,
+    \"success_metric\": \"pipeline_records_valid_total\",
+    \"total_metric\": \"pipeline_records_loaded_total\",
+    \"fast_burn_threshold\": 2.0,
+    \"slow_burn_threshold\": 1.0,
+    \"hard_budget_policy\": false
+  }" && echo "✅ Created Data Pipeline Correctness SLO"
+
+curl -X POST "$API_BASE_URL/slos" \
+  -H "Content-Type: application/json" \
+  -d "{
+    \"service_id\": $PIPELINE_SERVICE_ID,
+    \"name\": \"Archive Durability\",
+    \"description\": \"Archived objects must remain intact\",
+    \"sli_type\": \"durability\",
+    \"target\": 0.99999,
+    \"time_window_days\": 30,
+    \"success_metric\": \"archive_objects_verified_total\",
+    \"total_metric\": \"archive_objects_checked_total\",
+    \"fast_burn_threshold\": 2.0,
+    \"slow_burn_threshold\": 1.0,
+    \"hard_budget_policy\": true
+  }" && echo "✅ Created Data Pipeline Durability SLO"
+
 echo ""
 echo "🎉 Sample data seeding completed!"
 echo ""
@@ -206,12 +283,14 @@ echo "   - user-service (platform-team)"
 echo "   - payment-service (fintech-team)"
 echo "   - notification-service (platform-team)"
 echo "   - api-gateway (platform-team)"
+echo "   - data-pipeline (data-team)"
 echo ""
 echo "📈 SLOs created:"
 echo "   - API Availability SLOs"
 echo "   - Latency SLOs"
 echo "   - Payment Success Rate SLOs"
 echo "   - Email Delivery Rate SLOs"
+echo "   - Data Pipeline Freshness, Throughput, Correctness and Durability SLOs"
 echo ""
 echo "🌐 Access the dashboard at: http://localhost:3000"
 echo "📊 Access Prometheus at: http://localhost:9090"